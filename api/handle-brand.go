@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/girithc/pronto-go/types"
 )
@@ -24,6 +26,8 @@ func (s *Server) handleCreateBrand(res http.ResponseWriter, req *http.Request) e
 	if err != nil {
 		return err
 	}
+	new_brand.Description = new_req.Description
+	new_brand.Logo_URL = new_req.Logo_URL
 
 	brand, err := s.store.CreateBrand(new_brand)
 	if err != nil {
@@ -51,3 +55,154 @@ func (s *Server) HandleGetBrandList(res http.ResponseWriter, req *http.Request)
 	return WriteJSON(res, http.StatusOK, brand)
 
 }
+
+// HandleGetBrandItems answers /brand-items?brand_id=&store_id= with the brand page of the store.
+func (s *Server) HandleGetBrandItems(res http.ResponseWriter, req *http.Request) error {
+	brand_id, err := strconv.Atoi(req.URL.Query().Get("brand_id"))
+	if err != nil {
+		return fmt.Errorf("invalid brand_id provided: %w", err)
+	}
+	store_id, err := strconv.Atoi(req.URL.Query().Get("store_id"))
+	if err != nil {
+		return fmt.Errorf("invalid store_id provided: %w", err)
+	}
+
+	landing, etag, err := s.store.CachedBrandLanding(brand_id, store_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteCachedJSON(res, req, etag, landing)
+}
+
+func (s *Server) HandleManagerBrand(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.BrandRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBrand")
+		return err
+	}
+
+	brand, err := s.store.GetBrandDetail(new_req.ID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, brand)
+}
+
+func (s *Server) HandleManagerBrandUpdate(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.UpdateBrand)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBrandUpdate")
+		return err
+	}
+
+	brand, err := s.store.UpdateBrand(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, brand)
+}
+
+func (s *Server) HandleManagerBrandDelete(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.BrandRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBrandDelete")
+		return err
+	}
+
+	if err := s.store.DeleteBrand(new_req.ID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, map[string]int{"deleted": new_req.ID})
+}
+
+// HandleManagerBrandLogoUpload takes a multipart form with phone_auth, token_auth, brand_id and a
+// logo file. The auth fields travel in the form because the body is not JSON.
+func (s *Server) HandleManagerBrandLogoUpload(res http.ResponseWriter, req *http.Request) error {
+	req.Body = http.MaxBytesReader(res, req.Body, types.BrandLogoMaxBytes+1<<20)
+	if err := req.ParseMultipartForm(types.BrandLogoMaxBytes); err != nil {
+		return fmt.Errorf("error reading upload, logos are limited to %d MB: %w", types.BrandLogoMaxBytes>>20, err)
+	}
+
+	authenticated, err := s.store.AuthenticateRequestManager(req.FormValue("phone_auth"), req.FormValue("token_auth"))
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unauthorized access")
+	}
+
+	brand_id, err := strconv.Atoi(req.FormValue("brand_id"))
+	if err != nil {
+		return fmt.Errorf("brand_id is not a valid brand id: %w", err)
+	}
+
+	file, _, err := req.FormFile("logo")
+	if err != nil {
+		return fmt.Errorf("logo file is missing: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, types.BrandLogoMaxBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > types.BrandLogoMaxBytes {
+		return fmt.Errorf("logo is larger than %d MB", types.BrandLogoMaxBytes>>20)
+	}
+
+	brand, err := s.store.UploadBrandLogo(brand_id, data)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, brand)
+}
+
+func (s *Server) HandleManagerBrandVendorLink(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.BrandVendorLink)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBrandVendorLink")
+		return err
+	}
+
+	brand, err := s.store.LinkBrandVendor(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, brand)
+}
+
+func (s *Server) HandleManagerBrandVendorUnlink(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.BrandVendorLink)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBrandVendorUnlink")
+		return err
+	}
+
+	brand, err := s.store.UnlinkBrandVendor(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, brand)
+}
+
+func (s *Server) HandleManagerSupplierItems(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SupplierItemsRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerSupplierItems")
+		return err
+	}
+
+	groups, err := s.store.GetSupplierItems(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, groups)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleGetBundle(res http.ResponseWriter, req *http.Request) error {
+	item_id, err := strconv.Atoi(req.URL.Query().Get("item_id"))
+	if err != nil {
+		return fmt.Errorf("item_id is not a valid item id: %w", err)
+	}
+
+	bundle, err := s.store.GetBundleDetail(item_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, bundle)
+}
+
+func (s *Server) HandleManagerBundleSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetBundle)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBundleSet")
+		return err
+	}
+
+	bundle, err := s.store.SetBundle(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, bundle)
+}
+
+func (s *Server) HandleManagerBundleRemove(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.RemoveBundle)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerBundleRemove")
+		return err
+	}
+
+	if err := s.store.RemoveBundle(new_req.BundleItemID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerCatalogImport(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CatalogImportRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCatalogImport")
+		return err
+	}
+	if new_req.CSV == "" {
+		return fmt.Errorf("csv is empty. Please provide csv value")
+	}
+
+	report, err := s.store.ImportCatalogCSV(strings.NewReader(new_req.CSV), new_req.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if !report.DryRun && !report.Committed {
+		return WriteJSON(res, http.StatusUnprocessableEntity, report)
+	}
+	return WriteJSON(res, http.StatusOK, report)
+}
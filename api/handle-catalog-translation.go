@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerCatalogTranslation(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CatalogTranslationRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCatalogTranslation")
+		return err
+	}
+
+	translation, err := s.store.GetCatalogTranslation(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, translation)
+}
+
+func (s *Server) HandleManagerCatalogTranslationSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetCatalogTranslation)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCatalogTranslationSet")
+		return err
+	}
+
+	translation, err := s.store.SetCatalogTranslation(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, translation)
+}
+
+func (s *Server) HandleManagerTranslationsMissing(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.MissingTranslationsRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerTranslationsMissing")
+		return err
+	}
+
+	report, err := s.store.MissingTranslations(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, report)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerCatalogExport(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CatalogExportRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCatalogExport")
+		return err
+	}
+	if new_req.Format == "" {
+		new_req.Format = types.CatalogExportCSV
+	}
+
+	var contentType string
+	switch new_req.Format {
+	case types.CatalogExportCSV:
+		contentType = "text/csv"
+	case types.CatalogExportJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		return fmt.Errorf("unsupported export format %q, use csv or jsonl", new_req.Format)
+	}
+	if _, err := s.store.Get_Store_By_ID(new_req.StoreID); err != nil {
+		return err
+	}
+
+	w := &exportWriter{
+		res:         res,
+		contentType: contentType,
+		filename:    fmt.Sprintf("catalog-store-%d.%s", new_req.StoreID, new_req.Format),
+	}
+	err := s.store.ExportStoreCatalog(new_req.StoreID, new_req.Format, w)
+	if err != nil && w.started {
+		// The 200 and part of the file are already out, so an error body would only corrupt it.
+		log.Printf("catalog export of store %d stopped: %v", new_req.StoreID, err)
+		return nil
+	}
+	return err
+}
+
+// exportWriter sets the download headers on the first write, so an export that fails before
+// writing anything still answers with a JSON error.
+type exportWriter struct {
+	res         http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.res.Header().Set("Content-Type", w.contentType)
+		w.res.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	}
+	return w.res.Write(p)
+}
+
+func (s *Server) HandleManagerCatalogHealth(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CatalogHealthRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCatalogHealth")
+		return err
+	}
+
+	report, err := s.store.CatalogHealthReport(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, report)
+}
@@ -22,7 +22,7 @@ func (s *Server) Handle_Create_Category(res http.ResponseWriter, req *http.Reque
 	if err != nil {
 		return err
 	}
-	category, err := s.store.Create_Category(new_category)
+	category, err := s.store.Create_Category(new_category, new_req.ParentID)
 	if err != nil {
 		return err
 	}
@@ -34,23 +34,22 @@ func (s *Server) Handle_Get_Categories(res http.ResponseWriter, req *http.Reques
 	// check if req has empty url param
 	hlc_id := req.URL.Query().Get("id")
 	hlc_promotion := req.URL.Query().Get("promotion")
+	store_id := 0
+	if v := req.URL.Query().Get("store_id"); v != "" {
+		var err error
+		if store_id, err = strconv.Atoi(v); err != nil {
+			return err
+		}
+	}
 
 	// Check if URL Param is empty or 0
 	if hlc_id == "" || hlc_id == "0" {
-		if hlc_promotion == "true" {
-			categories, err := s.store.Get_Categories(true)
-			if err != nil {
-				return err
-			}
-
-			return WriteJSON(res, http.StatusOK, categories)
-		}
-		categories, err := s.store.Get_Categories(false)
+		categories, etag, err := s.store.CachedCategories(hlc_promotion == "true", store_id, acceptLanguages(res, req))
 		if err != nil {
 			return err
 		}
 
-		return WriteJSON(res, http.StatusOK, categories)
+		return WriteCachedJSON(res, req, etag, categories)
 	} else {
 		num, err := strconv.Atoi(hlc_id)
 		if err != nil {
@@ -62,8 +61,7 @@ func (s *Server) Handle_Get_Categories(res http.ResponseWriter, req *http.Reques
 		}
 
 		if new_category_parent.ID > 0 {
-
-			categories, err := s.store.Get_Category_By_Parent_ID(new_category_parent.ID)
+			categories, err := s.store.Get_Category_By_Parent_ID(new_category_parent.ID, store_id, acceptLanguages(res, req))
 			if err != nil {
 				return err
 			}
@@ -123,4 +121,106 @@ func (s *Server) HandleGetCategoryList(res http.ResponseWriter, req *http.Reques
 
 }
 
+func (s *Server) HandleGetCategoryTree(res http.ResponseWriter, req *http.Request) error {
+	store_id, root_id := 0, 0
+	var err error
+	if v := req.URL.Query().Get("store_id"); v != "" {
+		if store_id, err = strconv.Atoi(v); err != nil {
+			return err
+		}
+	}
+	if v := req.URL.Query().Get("root_id"); v != "" {
+		if root_id, err = strconv.Atoi(v); err != nil {
+			return err
+		}
+	}
+
+	tree, etag, err := s.store.CachedCategoryTree(store_id, root_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteCachedJSON(res, req, etag, tree)
+}
+
+func (s *Server) HandleGetCategoryBreadcrumbs(res http.ResponseWriter, req *http.Request) error {
+	category_id, err := strconv.Atoi(req.URL.Query().Get("id"))
+	if err != nil {
+		return fmt.Errorf("id is not a valid category id: %w", err)
+	}
+
+	breadcrumbs, err := s.store.GetCategoryBreadcrumbs(category_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, breadcrumbs)
+}
+
+func (s *Server) HandleManagerCategoryMove(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CategoryMove)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCategoryMove")
+		return err
+	}
+
+	category, err := s.store.MoveCategory(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, category)
+}
+
+func (s *Server) HandleManagerCategoryReorder(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CategoryReorder)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCategoryReorder")
+		return err
+	}
 
+	if err := s.store.ReorderCategories(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+func (s *Server) HandleManagerCategoryStore(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CategoryStoreVisibility)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCategoryStore")
+		return err
+	}
+
+	if err := s.store.SetCategoryStoreVisibility(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+func (s *Server) HandleManagerCategorySchedule(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CategorySchedule)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCategorySchedule")
+		return err
+	}
+
+	if err := s.store.SetCategorySchedule(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+// HandleManagerCategoryMigrationDropped lists the higher level mappings the category tree
+// migration could not keep, so they can be re-placed by hand.
+func (s *Server) HandleManagerCategoryMigrationDropped(res http.ResponseWriter, req *http.Request) error {
+	dropped, err := s.store.GetCategoryTreeMigrationDropped()
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, dropped)
+}
@@ -131,6 +131,19 @@ func (s *Server) HandleVerifyCustomerLogin(res http.ResponseWriter, req *http.Re
 }
 
 func (s *Server) HandleGetCustomers(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListCustomers(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	customers, err := s.store.Get_All_Customers()
 	if err != nil {
 		return err
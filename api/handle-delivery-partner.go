@@ -60,6 +60,19 @@ func (s *Server) Handle_Delivery_Partner_FCM_Token(res http.ResponseWriter, req
 }
 
 func (s *Server) Handle_Get_Delivery_Partners(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListDeliveryPartners(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	customers, err := s.store.Get_All_Delivery_Partners()
 	if err != nil {
 		return err
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/girithc/pronto-go/types"
 )
@@ -15,12 +16,20 @@ func (s *Server) Handle_Create_Higher_Level_Category(res http.ResponseWriter, re
 }
 
 func (s *Server) Handle_Get_Higher_Level_Categories(res http.ResponseWriter, req *http.Request) error {
-	higher_level_categories, err := s.store.Get_Higher_Level_Categories()
+	store_id := 0
+	if v := req.URL.Query().Get("store_id"); v != "" {
+		var err error
+		if store_id, err = strconv.Atoi(v); err != nil {
+			return err
+		}
+	}
+
+	higher_level_categories, etag, err := s.store.CachedHigherLevelCategories(store_id, acceptLanguages(res, req))
 	if err != nil {
 		return err
 	}
 
-	return WriteJSON(res, http.StatusOK, higher_level_categories)
+	return WriteCachedJSON(res, req, etag, higher_level_categories)
 }
 
 func (s *Server) Handle_Update_Higher_Level_Category(res http.ResponseWriter, req *http.Request) error {
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// HandleGetHome answers /home?store_id= with the live home screen of the store.
+func (s *Server) HandleGetHome(res http.ResponseWriter, req *http.Request) error {
+	store_id, err := strconv.Atoi(req.URL.Query().Get("store_id"))
+	if err != nil {
+		return fmt.Errorf("invalid store_id provided: %w", err)
+	}
+
+	home, etag, err := s.store.CachedHome(store_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteCachedJSON(res, req, etag, home)
+}
+
+// HandleGetHomeCollection answers /home-collection?id=&store_id=, the see-all link of a collection.
+func (s *Server) HandleGetHomeCollection(res http.ResponseWriter, req *http.Request) error {
+	id, err := strconv.Atoi(req.URL.Query().Get("id"))
+	if err != nil {
+		return fmt.Errorf("invalid id provided: %w", err)
+	}
+	store_id, err := strconv.Atoi(req.URL.Query().Get("store_id"))
+	if err != nil {
+		return fmt.Errorf("invalid store_id provided: %w", err)
+	}
+
+	collection, err := s.store.GetHomeCollection(id, store_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, collection)
+}
+
+func (s *Server) HandleManagerHomeSections(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.HomeSectionRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeSections")
+		return err
+	}
+
+	sections, err := s.store.GetHomeSections(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, sections)
+}
+
+func (s *Server) HandleManagerHomeSectionSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetHomeSection)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeSectionSet")
+		return err
+	}
+
+	section, err := s.store.SetHomeSection(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, section)
+}
+
+func (s *Server) HandleManagerHomeSectionDelete(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.HomeSectionRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeSectionDelete")
+		return err
+	}
+
+	if err := s.store.DeleteHomeSection(new_req.ID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, map[string]int{"deleted": new_req.ID})
+}
+
+func (s *Server) HandleManagerHomeBannerSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetHomeBanner)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeBannerSet")
+		return err
+	}
+
+	section, err := s.store.SetHomeBanner(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, section)
+}
+
+func (s *Server) HandleManagerHomeBannerDelete(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.HomeBannerRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeBannerDelete")
+		return err
+	}
+
+	if err := s.store.DeleteHomeBanner(new_req.ID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, map[string]int{"deleted": new_req.ID})
+}
+
+func (s *Server) HandleManagerHomeCollectionItemsSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetHomeCollectionItems)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeCollectionItemsSet")
+		return err
+	}
+
+	section, err := s.store.SetHomeCollectionItems(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, section)
+}
+
+func (s *Server) HandleManagerHomeOrderSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetHomeOrder)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerHomeOrderSet")
+		return err
+	}
+
+	sections, err := s.store.SetHomeOrder(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, sections)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerItemAttributes(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.GetItemAttributes)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemAttributes")
+		return err
+	}
+
+	details, err := s.store.GetItemAttributeDetails(new_req.ItemID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, details)
+}
+
+func (s *Server) HandleManagerItemAttributesSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ItemAttributes)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemAttributesSet")
+		return err
+	}
+
+	details, err := s.store.SetItemAttributes(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, details)
+}
+
+func (s *Server) HandleManagerCategoryAttributeSchema(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CategoryAttributeSchema)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerCategoryAttributeSchema")
+		return err
+	}
+
+	schema, err := s.store.SetCategoryAttributeSchema(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, schema)
+}
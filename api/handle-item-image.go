@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// HandleManagerItemImageUpload takes a multipart form with phone_auth, token_auth, item_id and an
+// image file. The auth fields travel in the form because the body is not JSON.
+func (s *Server) HandleManagerItemImageUpload(res http.ResponseWriter, req *http.Request) error {
+	req.Body = http.MaxBytesReader(res, req.Body, types.ItemImageMaxBytes+1<<20)
+	if err := req.ParseMultipartForm(types.ItemImageMaxBytes); err != nil {
+		return fmt.Errorf("error reading upload, images are limited to %d MB: %w", types.ItemImageMaxBytes>>20, err)
+	}
+
+	authenticated, err := s.store.AuthenticateRequestManager(req.FormValue("phone_auth"), req.FormValue("token_auth"))
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unauthorized access")
+	}
+
+	item_id, err := strconv.Atoi(req.FormValue("item_id"))
+	if err != nil {
+		return fmt.Errorf("item_id is not a valid item id: %w", err)
+	}
+
+	file, _, err := req.FormFile("image")
+	if err != nil {
+		return fmt.Errorf("image file is missing: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, types.ItemImageMaxBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > types.ItemImageMaxBytes {
+		return fmt.Errorf("image is larger than %d MB", types.ItemImageMaxBytes>>20)
+	}
+
+	image, err := s.store.UploadItemImage(item_id, data)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, image)
+}
+
+func (s *Server) HandleManagerItemImageReorder(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ItemImageReorder)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemImageReorder")
+		return err
+	}
+
+	if err := s.store.ReorderItemImages(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+func (s *Server) HandleManagerItemImageDelete(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ItemImageDelete)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemImageDelete")
+		return err
+	}
+
+	if err := s.store.DeleteItemImage(new_req.ItemID, new_req.ImageID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, map[string]int{"deleted": new_req.ImageID})
+}
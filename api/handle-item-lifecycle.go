@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerItemLifecycle(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ItemLifecycleRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemLifecycle")
+		return err
+	}
+
+	lifecycle, err := s.store.GetItemLifecycle(new_req.ItemID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, lifecycle)
+}
+
+func (s *Server) HandleManagerItemStatusSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetItemStatus)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemStatusSet")
+		return err
+	}
+
+	lifecycle, err := s.store.SetItemStatus(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, lifecycle)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerPriceSchedule(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SchedulePriceChange)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerPriceSchedule")
+		return err
+	}
+
+	change, err := s.store.SchedulePriceChange(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, change)
+}
+
+func (s *Server) HandleManagerPriceScheduleCancel(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CancelPriceChange)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerPriceScheduleCancel")
+		return err
+	}
+
+	change, err := s.store.CancelPriceChange(new_req.ID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, change)
+}
+
+func (s *Server) HandleManagerItemPriceHistory(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ItemPriceHistoryRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemPriceHistory")
+		return err
+	}
+
+	history, err := s.store.GetItemPriceHistory(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, history)
+}
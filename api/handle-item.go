@@ -45,6 +45,19 @@ func (s *Server) Handle_Create_Item(res http.ResponseWriter, req *http.Request)
 }
 
 func (s *Server) Handle_Get_Items(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListItems(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	category_id := req.URL.Query().Get("category_id")
 	store_id := req.URL.Query().Get("store_id")
 
@@ -75,7 +88,7 @@ func (s *Server) Handle_Get_Items(res http.ResponseWriter, req *http.Request) er
 			return fmt.Errorf("invalid item_id provided: %w", err)
 		}
 
-		item, err := s.store.Get_Item_By_ID(itemID)
+		item, err := s.store.GetItemDetail(itemID, acceptLanguages(res, req))
 		if err != nil {
 			return err
 		}
@@ -96,11 +109,11 @@ func (s *Server) Handle_Get_Items(res http.ResponseWriter, req *http.Request) er
 			return fmt.Errorf("invalid store_id provided: %w", err)
 		}
 
-		items, err := s.store.Get_Items_By_CategoryID_And_StoreID(categoryID, storeID)
+		items, etag, err := s.store.CachedItemsByCategoryAndStore(categoryID, storeID, acceptLanguages(res, req))
 		if err != nil {
 			return err
 		}
-		return WriteJSON(res, http.StatusOK, items)
+		return WriteCachedJSON(res, req, etag, items)
 	}
 }
 
@@ -253,3 +266,37 @@ func (s *Server) handleManagerGetTaxBasic(res http.ResponseWriter, req *http.Req
 
 	return WriteJSON(res, http.StatusOK, taxDetails)
 }
+
+func (s *Server) HandleManagerItemBarcodeAdd(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.AddItemBarcode)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemBarcodeAdd")
+		return err
+	}
+
+	barcode, err := s.store.AddItemBarcode(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, barcode)
+}
+
+func (s *Server) HandleManagerItemBarcodeRemove(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.RemoveItemBarcode)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemBarcodeRemove")
+		return err
+	}
+
+	if err := s.store.RemoveItemBarcode(*new_req); err != nil {
+		return err
+	}
+
+	barcodes, err := s.store.GetItemBarcodes(new_req.ItemID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, barcodes)
+}
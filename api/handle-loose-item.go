@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerLooseItemSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetLooseItem)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerLooseItemSet")
+		return err
+	}
+
+	item, err := s.store.SetLooseItem(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, item)
+}
+
+func (s *Server) HandleManagerOrderWeight(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.OrderWeightRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerOrderWeight")
+		return err
+	}
+
+	settlement, err := s.store.GetOrderWeightSettlement(new_req.SalesOrderID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, settlement)
+}
+
+func (s *Server) HandleManagerOrderWeightSettle(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.OrderWeightRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerOrderWeightSettle")
+		return err
+	}
+
+	settlement, err := s.store.SettleOrderWeight(new_req.SalesOrderID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, settlement)
+}
@@ -59,6 +59,19 @@ func (s *Server) HandleManagerLogin(res http.ResponseWriter, req *http.Request)
 }
 
 func (s *Server) HandleManagerItems(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListManagerItems(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	items, err := s.store.GetManagerItems()
 	if err != nil {
 		return err
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// EventSource cannot send a request body, so the streams authenticate with phone and token query params.
+func streamAuth(req *http.Request) (string, string, error) {
+	phone := req.URL.Query().Get("phone")
+	token := req.URL.Query().Get("token")
+	if phone == "" || token == "" {
+		return "", "", fmt.Errorf("phone and token are required")
+	}
+	return phone, token, nil
+}
+
+func (s *Server) HandleCustomerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	phone, token, err := streamAuth(req)
+	if err != nil {
+		return err
+	}
+	authenticated, _, err := s.store.AuthenticateRequest(phone, token)
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unauthorized access")
+	}
+
+	customerID, err := s.store.GetCustomerIDByPhone(phone)
+	if err != nil {
+		return err
+	}
+
+	return s.streamOrderEvents(res, req, func(event types.OrderEvent) bool {
+		return event.CustomerID == customerID
+	})
+}
+
+func (s *Server) HandlePackerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	phone, token, err := streamAuth(req)
+	if err != nil {
+		return err
+	}
+	authenticated, _, err := s.store.AuthenticateRequestPacker(phone, token)
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unauthorized access")
+	}
+
+	storeID, err := strconv.Atoi(req.URL.Query().Get("store_id"))
+	if err != nil {
+		return fmt.Errorf("invalid store_id provided: %w", err)
+	}
+	// The feed carries customer addresses and phone numbers, so it stays within the packer's store
+	packerStoreID, err := s.store.GetPackerStoreByPhone(phone)
+	if err != nil {
+		return err
+	}
+	if packerStoreID == 0 || packerStoreID != storeID {
+		return fmt.Errorf("packer is not assigned to store %d", storeID)
+	}
+
+	return s.streamOrderEvents(res, req, func(event types.OrderEvent) bool {
+		return event.StoreID == storeID
+	})
+}
+
+func (s *Server) HandleDeliveryPartnerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	phone, token, err := streamAuth(req)
+	if err != nil {
+		return err
+	}
+	authenticated, _, err := s.store.AuthenticateRequestDeliveryPartner(phone, token)
+	if err != nil {
+		return err
+	}
+	if !authenticated {
+		return fmt.Errorf("unauthorized access")
+	}
+
+	deliveryPartnerID, err := s.store.GetDeliveryPartnerIDByPhone(phone)
+	if err != nil {
+		return err
+	}
+
+	return s.streamOrderEvents(res, req, func(event types.OrderEvent) bool {
+		return event.DeliveryPartnerID != nil && *event.DeliveryPartnerID == deliveryPartnerID
+	})
+}
+
+func (s *Server) HandleManagerPackerStoreSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetPackerStore)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerPackerStoreSet")
+		return err
+	}
+
+	if err := s.store.SetPackerStore(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+// streamOrderEvents writes matching order events as SSE until the client disconnects.
+// A comment line every 20s keeps proxies from closing an idle stream.
+func (s *Server) streamOrderEvents(res http.ResponseWriter, req *http.Request, filter func(types.OrderEvent) bool) error {
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("streaming is not supported")
+	}
+
+	events, unsubscribe := s.store.SubscribeOrderEvents(filter)
+	defer unsubscribe()
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, "retry: 3000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(20 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return nil
+		case <-keepAlive.C:
+			fmt.Fprint(res, ": keep-alive\n\n")
+			flusher.Flush()
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Event, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleGetProductGroup(res http.ResponseWriter, req *http.Request) error {
+	group_id, err := strconv.Atoi(req.URL.Query().Get("id"))
+	if err != nil {
+		return fmt.Errorf("id is not a valid product group id: %w", err)
+	}
+
+	group, err := s.store.GetProductGroupDetail(group_id, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, group)
+}
+
+func (s *Server) HandleManagerProductGroupCreate(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CreateProductGroup)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerProductGroupCreate")
+		return err
+	}
+
+	group, err := s.store.CreateProductGroup(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, group)
+}
+
+func (s *Server) HandleManagerProductGroupVariantAdd(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ProductGroupVariant)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerProductGroupVariantAdd")
+		return err
+	}
+
+	group, err := s.store.AddProductGroupVariant(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, group)
+}
+
+// HandleManagerProductGroupVariantRemove responds with the updated group, or null when its last variant was removed.
+func (s *Server) HandleManagerProductGroupVariantRemove(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ProductGroupVariant)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerProductGroupVariantRemove")
+		return err
+	}
+
+	group, err := s.store.RemoveProductGroupVariant(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, group)
+}
+
+func (s *Server) HandleManagerProductGroupDefault(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ProductGroupVariant)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerProductGroupDefault")
+		return err
+	}
+
+	group, err := s.store.SetProductGroupDefault(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, group)
+}
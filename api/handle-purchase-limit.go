@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerPurchaseLimits(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.PurchaseLimitRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerPurchaseLimits")
+		return err
+	}
+
+	limits, err := s.store.GetPurchaseLimits(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, limits)
+}
+
+func (s *Server) HandleManagerPurchaseLimitSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetPurchaseLimit)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerPurchaseLimitSet")
+		return err
+	}
+
+	limit, err := s.store.SetPurchaseLimit(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, limit)
+}
+
+func (s *Server) HandleManagerItemRestrictionsSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetItemRestrictions)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerItemRestrictionsSet")
+		return err
+	}
+
+	restrictions, err := s.store.SetItemRestrictions(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, types.SetItemRestrictions{ItemID: new_req.ItemID, Restrictions: restrictions})
+}
+
+func (s *Server) HandleCartRestrictions(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ConfirmCartRestriction)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleCartRestrictions")
+		return err
+	}
+
+	validCart, err := s.store.ValidShoppingCart(new_req.CartId, new_req.CustomerId)
+	if err != nil {
+		return err
+	} else if !(validCart.Valid) {
+		return fmt.Errorf("cart is invalid %v", validCart.CartId)
+	}
+
+	restrictions, err := s.store.GetCartRestrictions(validCart.CartId)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, restrictions)
+}
+
+func (s *Server) HandleCartRestrictionConfirm(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.ConfirmCartRestriction)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleCartRestrictionConfirm")
+		return err
+	}
+
+	validCart, err := s.store.ValidShoppingCart(new_req.CartId, new_req.CustomerId)
+	if err != nil {
+		return err
+	} else if !(validCart.Valid) {
+		return fmt.Errorf("cart is invalid %v", validCart.CartId)
+	}
+
+	restrictions, err := s.store.ConfirmCartRestriction(validCart.CartId, new_req.Restriction)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, restrictions)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleRecommendationsCart(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.RecommendationRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleRecommendationsCart")
+		return err
+	}
+	new_req.Languages = acceptLanguages(res, req)
+
+	items, err := s.store.CartRecommendations(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, items)
+}
+
+func (s *Server) HandleRecommendationsBuyAgain(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.RecommendationRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleRecommendationsBuyAgain")
+		return err
+	}
+	new_req.Languages = acceptLanguages(res, req)
+
+	items, err := s.store.BuyAgainRecommendations(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, items)
+}
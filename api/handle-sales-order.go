@@ -9,6 +9,19 @@ import (
 )
 
 func (s *Server) Handle_Get_Sales_Orders(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListSalesOrders(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	sales_orders, err := s.store.Get_All_Sales_Orders()
 	if err != nil {
 		return err
@@ -192,7 +205,7 @@ func (s *Server) PackerPackItem(res http.ResponseWriter, req *http.Request) erro
 		return err
 	}
 
-	records, err := s.store.PackerPackItem(new_req.Barcode, new_req.PackerPhone, new_req.SalesOrderID, new_req.StoreId)
+	records, err := s.store.PackerPackItem(new_req.Barcode, new_req.PackerPhone, new_req.SalesOrderID, new_req.StoreId, new_req.WeightGrams)
 	if err != nil {
 		return err
 	}
@@ -207,7 +220,7 @@ func (s *Server) PackerPackItemQuick(res http.ResponseWriter, req *http.Request)
 		return err
 	}
 
-	records, err := s.store.PackerPackItemQuick(new_req.ItemId, new_req.ItemQuantity, new_req.PackerPhone, new_req.SalesOrderID, new_req.StoreId)
+	records, err := s.store.PackerPackItemQuick(new_req.ItemId, new_req.ItemQuantity, new_req.PackerPhone, new_req.SalesOrderID, new_req.StoreId, new_req.WeightGrams)
 	if err != nil {
 		return err
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/girithc/pronto-go/types"
 )
@@ -17,12 +18,17 @@ func (s *Server) Handle_Post_Search_Items(res http.ResponseWriter, req *http.Req
 		fmt.Println("Error in Decoding req.body in Handle_Create_Item()")
 		return err
 	}
+	new_req.Languages = acceptLanguages(res, req)
 
-	items, err := s.store.Search_Items(new_req.Name)
+	items, search_id, err := s.store.Search_Items(*new_req)
 	if err != nil {
 		return err
 	}
 
+	// The body stays a plain list for older apps; the id for /search-event goes in a header
+	if search_id != 0 {
+		res.Header().Set("X-Search-Id", strconv.FormatInt(search_id, 10))
+	}
 	return WriteJSON(res, http.StatusOK, items)
 }
 
@@ -43,3 +49,115 @@ func (s *Server) handleManagerSearchItemBasic(res http.ResponseWriter, req *http
 
 	return WriteJSON(res, http.StatusOK, items)
 }
+
+func (s *Server) HandleSearch(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.Search_Item)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleSearch")
+		return err
+	}
+	new_req.Languages = acceptLanguages(res, req)
+
+	result, err := s.store.Search(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, result)
+}
+
+func (s *Server) HandleGetSearchSynonyms(res http.ResponseWriter, req *http.Request) error {
+	synonyms, err := s.store.GetSearchSynonyms()
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, synonyms)
+}
+
+func (s *Server) HandleManagerSearchSynonymSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SearchSynonym)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerSearchSynonymSet")
+		return err
+	}
+
+	synonym, err := s.store.SetSearchSynonym(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, synonym)
+}
+
+func (s *Server) HandleManagerSearchSynonymDelete(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.DeleteSearchSynonym)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerSearchSynonymDelete")
+		return err
+	}
+
+	if err := s.store.DeleteSearchSynonym(new_req.ID); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+func (s *Server) HandleGetSearchAutocomplete(res http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+
+	store_id := 0
+	if v := query.Get("store_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("store_id is not a valid store id: %w", err)
+		}
+		store_id = id
+	}
+
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit provided: %w", err)
+		}
+		limit = n
+	}
+
+	suggestions, err := s.store.Autocomplete(query.Get("q"), store_id, limit, acceptLanguages(res, req))
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, suggestions)
+}
+
+func (s *Server) HandleSearchEvent(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SearchEvent)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleSearchEvent")
+		return err
+	}
+
+	if err := s.store.RecordSearchEvent(*new_req); err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, new_req)
+}
+
+func (s *Server) HandleManagerSearchReport(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SearchReportRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerSearchReport")
+		return err
+	}
+
+	report, err := s.store.SearchReport(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, report)
+}
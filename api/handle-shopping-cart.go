@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/girithc/pronto-go/types"
 )
@@ -115,7 +116,16 @@ func (s *Server) handleApplyPromo(res http.ResponseWriter, req *http.Request) er
 }
 
 func (s *Server) handleResetPrice(res http.ResponseWriter, req *http.Request) error {
-	err := s.store.ResetPrices()
+	storeID := 0
+	if value := req.URL.Query().Get("store_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("store_id is not a valid integer: %w", err)
+		}
+		storeID = id
+	}
+
+	err := s.store.ResetPrices(storeID)
 	if err != nil {
 		return err
 	}
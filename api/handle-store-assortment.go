@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerStoreAssortment(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.StoreAssortmentRequest)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerStoreAssortment")
+		return err
+	}
+
+	items, err := s.store.GetStoreAssortment(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, items)
+}
+
+func (s *Server) HandleManagerStoreAssortmentSet(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.SetStoreAssortment)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerStoreAssortmentSet")
+		return err
+	}
+
+	change, err := s.store.SetStoreAssortment(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, change)
+}
+
+func (s *Server) HandleManagerStorePriceOverride(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.StorePriceOverride)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerStorePriceOverride")
+		return err
+	}
+
+	item, err := s.store.SetStorePriceOverride(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, item)
+}
+
+func (s *Server) HandleManagerStoreAssortmentClone(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CloneStoreAssortment)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerStoreAssortmentClone")
+		return err
+	}
+
+	change, err := s.store.CloneStoreAssortment(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, change)
+}
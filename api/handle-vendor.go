@@ -8,6 +8,19 @@ import (
 )
 
 func (s *Server) handleGetVendorList(res http.ResponseWriter, req *http.Request) error {
+	if wantsPage(req) {
+		params, err := parseListParams(req)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.store.ListVendors(params)
+		if err != nil {
+			return err
+		}
+		return WriteJSON(res, http.StatusOK, page)
+	}
+
 	vendorList, err := s.store.GetVendorList()
 	if err != nil {
 		return err
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func (s *Server) HandleManagerWebhookEndpoint(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.CreateWebhookEndpoint)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerWebhookEndpoint")
+		return err
+	}
+
+	endpoint, err := s.store.CreateWebhookEndpoint(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, endpoint)
+}
+
+func (s *Server) HandleManagerWebhookReplay(res http.ResponseWriter, req *http.Request) error {
+	new_req := new(types.WebhookReplay)
+	if err := json.NewDecoder(req.Body).Decode(new_req); err != nil {
+		fmt.Println("Error in Decoding req.body in HandleManagerWebhookReplay")
+		return err
+	}
+
+	result, err := s.store.ReplayWebhooks(*new_req)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(res, http.StatusOK, result)
+}
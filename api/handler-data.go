@@ -142,6 +142,84 @@ var authRequirements = map[string]Permission{
 	PackerGetOrder:                        {Role: "Customer", AuthRequired: true},
 	ApplyPromo:                            {Role: "Customer", AuthRequired: true},
 	ResetPrices:                           {Role: "Customer", AuthRequired: false},
+	CustomerOrderEvents:                   {Role: "Customer", AuthRequired: true},
+	PackerOrderEvents:                     {Role: "Customer", AuthRequired: true},
+	DeliveryPartnerOrderEvents:            {Role: "Customer", AuthRequired: true},
+	ManagerWebhookEndpoint:                {Role: "Manager", AuthRequired: true},
+	ManagerWebhookReplay:                  {Role: "Manager", AuthRequired: true},
+	ManagerCatalogImport:                  {Role: "Manager", AuthRequired: true},
+	ManagerCatalogExport:                  {Role: "Manager", AuthRequired: true},
+	CategoryTree:                          {Role: "Customer", AuthRequired: true},
+	CategoryBreadcrumbs:                   {Role: "Customer", AuthRequired: true},
+	ManagerCategoryMove:                   {Role: "Manager", AuthRequired: true},
+	ManagerCategoryReorder:                {Role: "Manager", AuthRequired: true},
+	ManagerCategoryStore:                  {Role: "Manager", AuthRequired: true},
+	ManagerCategorySchedule:               {Role: "Manager", AuthRequired: true},
+	ManagerItemImageUpload:                {Role: "Manager", AuthRequired: false},
+	ManagerItemImageReorder:               {Role: "Manager", AuthRequired: true},
+	ManagerItemImageDelete:                {Role: "Manager", AuthRequired: true},
+	ManagerItemBarcodeAdd:                 {Role: "Manager", AuthRequired: true},
+	ManagerItemBarcodeRemove:              {Role: "Manager", AuthRequired: true},
+	ManagerItemAttributes:                 {Role: "Manager", AuthRequired: true},
+	ManagerItemAttributesSet:              {Role: "Manager", AuthRequired: true},
+	ManagerCategoryAttributeSchema:        {Role: "Manager", AuthRequired: true},
+	ProductGroup:                          {Role: "Customer", AuthRequired: true},
+	ManagerProductGroupCreate:             {Role: "Manager", AuthRequired: true},
+	ManagerProductGroupVariantAdd:         {Role: "Manager", AuthRequired: true},
+	ManagerProductGroupVariantRemove:      {Role: "Manager", AuthRequired: true},
+	ManagerProductGroupDefault:            {Role: "Manager", AuthRequired: true},
+	Bundle:                                {Role: "Customer", AuthRequired: true},
+	ManagerBundleSet:                      {Role: "Manager", AuthRequired: true},
+	ManagerBundleRemove:                   {Role: "Manager", AuthRequired: true},
+	RecommendationsCart:                   {Role: "Customer", AuthRequired: true},
+	RecommendationsBuyAgain:               {Role: "Customer", AuthRequired: true},
+	Search:                                {Role: "Customer", AuthRequired: true},
+	SearchSynonyms:                        {Role: "Customer", AuthRequired: true},
+	ManagerSearchSynonymSet:               {Role: "Manager", AuthRequired: true},
+	ManagerSearchSynonymDelete:            {Role: "Manager", AuthRequired: true},
+	SearchAutocomplete:                    {Role: "Customer", AuthRequired: true},
+	SearchEvent:                           {Role: "Customer", AuthRequired: true},
+	ManagerSearchReport:                   {Role: "Manager", AuthRequired: true},
+	ManagerPriceSchedule:                  {Role: "Manager", AuthRequired: true},
+	ManagerPriceScheduleCancel:            {Role: "Manager", AuthRequired: true},
+	ManagerItemPriceHistory:               {Role: "Manager", AuthRequired: true},
+	ManagerStoreAssortment:                {Role: "Manager", AuthRequired: true},
+	ManagerStoreAssortmentSet:             {Role: "Manager", AuthRequired: true},
+	ManagerStorePriceOverride:             {Role: "Manager", AuthRequired: true},
+	ManagerStoreAssortmentClone:           {Role: "Manager", AuthRequired: true},
+	ManagerItemLifecycle:                  {Role: "Manager", AuthRequired: true},
+	ManagerItemStatusSet:                  {Role: "Manager", AuthRequired: true},
+	ManagerCatalogHealth:                  {Role: "Manager", AuthRequired: true},
+	ManagerLooseItemSet:                   {Role: "Manager", AuthRequired: true},
+	ManagerOrderWeight:                    {Role: "Manager", AuthRequired: true},
+	ManagerOrderWeightSettle:              {Role: "Manager", AuthRequired: true},
+	ManagerPurchaseLimits:                 {Role: "Manager", AuthRequired: true},
+	ManagerPurchaseLimitSet:               {Role: "Manager", AuthRequired: true},
+	ManagerItemRestrictionsSet:            {Role: "Manager", AuthRequired: true},
+	CartRestrictions:                      {Role: "Customer", AuthRequired: true},
+	CartRestrictionConfirm:                {Role: "Customer", AuthRequired: true},
+	ManagerCatalogTranslation:             {Role: "Manager", AuthRequired: true},
+	ManagerCatalogTranslationSet:          {Role: "Manager", AuthRequired: true},
+	ManagerTranslationsMissing:            {Role: "Manager", AuthRequired: true},
+	BrandItems:                            {Role: "Customer", AuthRequired: true},
+	ManagerBrand:                          {Role: "Manager", AuthRequired: true},
+	ManagerBrandUpdate:                    {Role: "Manager", AuthRequired: true},
+	ManagerBrandDelete:                    {Role: "Manager", AuthRequired: true},
+	ManagerBrandLogoUpload:                {Role: "Manager", AuthRequired: false},
+	ManagerBrandVendorLink:                {Role: "Manager", AuthRequired: true},
+	ManagerBrandVendorUnlink:              {Role: "Manager", AuthRequired: true},
+	ManagerSupplierItems:                  {Role: "Manager", AuthRequired: true},
+	Home:                                  {Role: "Customer", AuthRequired: true},
+	HomeCollection:                        {Role: "Customer", AuthRequired: true},
+	ManagerHomeSections:                   {Role: "Manager", AuthRequired: true},
+	ManagerHomeSectionSet:                 {Role: "Manager", AuthRequired: true},
+	ManagerHomeSectionDelete:              {Role: "Manager", AuthRequired: true},
+	ManagerHomeBannerSet:                  {Role: "Manager", AuthRequired: true},
+	ManagerHomeBannerDelete:               {Role: "Manager", AuthRequired: true},
+	ManagerHomeCollectionItemsSet:         {Role: "Manager", AuthRequired: true},
+	ManagerHomeOrderSet:                   {Role: "Manager", AuthRequired: true},
+	ManagerPackerStoreSet:                 {Role: "Manager", AuthRequired: true},
+	ManagerCategoryMigrationDropped:       {Role: "Manager", AuthRequired: true},
 }
 
 const (
@@ -282,4 +360,82 @@ const (
 	ManagerCreateOrder                    = "manager-create-order"
 	ManagerFCM                            = "manager-fcm"
 	ResetPrices                           = "reset-prices"
+	CustomerOrderEvents                   = "customer-order-events"
+	PackerOrderEvents                     = "packer-order-events"
+	DeliveryPartnerOrderEvents            = "delivery-partner-order-events"
+	ManagerWebhookEndpoint                = "manager-webhook-endpoint"
+	ManagerWebhookReplay                  = "manager-webhook-replay"
+	ManagerCatalogImport                  = "manager-catalog-import"
+	ManagerCatalogExport                  = "manager-catalog-export"
+	CategoryTree                          = "category-tree"
+	CategoryBreadcrumbs                   = "category-breadcrumbs"
+	ManagerCategoryMove                   = "manager-category-move"
+	ManagerCategoryReorder                = "manager-category-reorder"
+	ManagerCategoryStore                  = "manager-category-store"
+	ManagerCategorySchedule               = "manager-category-schedule"
+	ManagerItemImageUpload                = "manager-item-image-upload"
+	ManagerItemImageReorder               = "manager-item-image-reorder"
+	ManagerItemImageDelete                = "manager-item-image-delete"
+	ManagerItemBarcodeAdd                 = "manager-item-barcode-add"
+	ManagerItemBarcodeRemove              = "manager-item-barcode-remove"
+	ManagerItemAttributes                 = "manager-item-attributes"
+	ManagerItemAttributesSet              = "manager-item-attributes-set"
+	ManagerCategoryAttributeSchema        = "manager-category-attribute-schema"
+	ProductGroup                          = "product-group"
+	ManagerProductGroupCreate             = "manager-product-group-create"
+	ManagerProductGroupVariantAdd         = "manager-product-group-variant-add"
+	ManagerProductGroupVariantRemove      = "manager-product-group-variant-remove"
+	ManagerProductGroupDefault            = "manager-product-group-default"
+	Bundle                                = "bundle"
+	ManagerBundleSet                      = "manager-bundle-set"
+	ManagerBundleRemove                   = "manager-bundle-remove"
+	RecommendationsCart                   = "recommendations-cart"
+	RecommendationsBuyAgain               = "recommendations-buy-again"
+	Search                                = "search"
+	SearchSynonyms                        = "search-synonyms"
+	ManagerSearchSynonymSet               = "manager-search-synonym-set"
+	ManagerSearchSynonymDelete            = "manager-search-synonym-delete"
+	SearchAutocomplete                    = "search-autocomplete"
+	SearchEvent                           = "search-event"
+	ManagerSearchReport                   = "manager-search-report"
+	ManagerPriceSchedule                  = "manager-price-schedule"
+	ManagerPriceScheduleCancel            = "manager-price-schedule-cancel"
+	ManagerItemPriceHistory               = "manager-item-price-history"
+	ManagerStoreAssortment                = "manager-store-assortment"
+	ManagerStoreAssortmentSet             = "manager-store-assortment-set"
+	ManagerStorePriceOverride             = "manager-store-price-override"
+	ManagerStoreAssortmentClone           = "manager-store-assortment-clone"
+	ManagerItemLifecycle                  = "manager-item-lifecycle"
+	ManagerItemStatusSet                  = "manager-item-status-set"
+	ManagerCatalogHealth                  = "manager-catalog-health"
+	ManagerLooseItemSet                   = "manager-loose-item-set"
+	ManagerOrderWeight                    = "manager-order-weight"
+	ManagerOrderWeightSettle              = "manager-order-weight-settle"
+	ManagerPurchaseLimits                 = "manager-purchase-limits"
+	ManagerPurchaseLimitSet               = "manager-purchase-limit-set"
+	ManagerItemRestrictionsSet            = "manager-item-restrictions-set"
+	CartRestrictions                      = "cart-restrictions"
+	CartRestrictionConfirm                = "cart-restriction-confirm"
+	ManagerCatalogTranslation             = "manager-catalog-translation"
+	ManagerCatalogTranslationSet          = "manager-catalog-translation-set"
+	ManagerTranslationsMissing            = "manager-translations-missing"
+	BrandItems                            = "brand-items"
+	ManagerBrand                          = "manager-brand"
+	ManagerBrandUpdate                    = "manager-brand-update"
+	ManagerBrandDelete                    = "manager-brand-delete"
+	ManagerBrandLogoUpload                = "manager-brand-logo-upload"
+	ManagerBrandVendorLink                = "manager-brand-vendor-link"
+	ManagerBrandVendorUnlink              = "manager-brand-vendor-unlink"
+	ManagerSupplierItems                  = "manager-supplier-items"
+	Home                                  = "home"
+	HomeCollection                        = "home-collection"
+	ManagerHomeSections                   = "manager-home-sections"
+	ManagerHomeSectionSet                 = "manager-home-section-set"
+	ManagerHomeSectionDelete              = "manager-home-section-delete"
+	ManagerHomeBannerSet                  = "manager-home-banner-set"
+	ManagerHomeBannerDelete               = "manager-home-banner-delete"
+	ManagerHomeCollectionItemsSet         = "manager-home-collection-items-set"
+	ManagerHomeOrderSet                   = "manager-home-order-set"
+	ManagerPackerStoreSet                 = "manager-packer-store-set"
+	ManagerCategoryMigrationDropped       = "manager-category-migration-dropped"
 )
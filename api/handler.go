@@ -1304,3 +1304,627 @@ func (s *Server) handleResetPrices(res http.ResponseWriter, req *http.Request) e
 	}
 	return nil
 }
+
+func (s *Server) handleCustomerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "customer order events")
+		return s.goRoutineWrapper(CustomerOrderEvents, s.HandleCustomerOrderEvents, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handlePackerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "packer order events")
+		return s.goRoutineWrapper(PackerOrderEvents, s.HandlePackerOrderEvents, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleDeliveryPartnerOrderEvents(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "delivery partner order events")
+		return s.goRoutineWrapper(DeliveryPartnerOrderEvents, s.HandleDeliveryPartnerOrderEvents, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerWebhookEndpoint(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-webhook-endpoint")
+		return s.goRoutineWrapper(ManagerWebhookEndpoint, s.HandleManagerWebhookEndpoint, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerWebhookReplay(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-webhook-replay")
+		return s.goRoutineWrapper(ManagerWebhookReplay, s.HandleManagerWebhookReplay, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCatalogImport(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-catalog-import")
+		return s.goRoutineWrapper(ManagerCatalogImport, s.HandleManagerCatalogImport, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCatalogExport(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-catalog-export")
+		return s.goRoutineWrapper(ManagerCatalogExport, s.HandleManagerCatalogExport, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleCategoryTree(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "category-tree")
+		return s.goRoutineWrapper(CategoryTree, s.HandleGetCategoryTree, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleCategoryBreadcrumbs(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "category-breadcrumbs")
+		return s.goRoutineWrapper(CategoryBreadcrumbs, s.HandleGetCategoryBreadcrumbs, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategoryMove(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-move")
+		return s.goRoutineWrapper(ManagerCategoryMove, s.HandleManagerCategoryMove, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategoryReorder(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-reorder")
+		return s.goRoutineWrapper(ManagerCategoryReorder, s.HandleManagerCategoryReorder, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategoryStore(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-store")
+		return s.goRoutineWrapper(ManagerCategoryStore, s.HandleManagerCategoryStore, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategorySchedule(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-schedule")
+		return s.goRoutineWrapper(ManagerCategorySchedule, s.HandleManagerCategorySchedule, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemImageUpload(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-image-upload")
+		return s.goRoutineWrapper(ManagerItemImageUpload, s.HandleManagerItemImageUpload, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemImageReorder(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-image-reorder")
+		return s.goRoutineWrapper(ManagerItemImageReorder, s.HandleManagerItemImageReorder, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemImageDelete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-image-delete")
+		return s.goRoutineWrapper(ManagerItemImageDelete, s.HandleManagerItemImageDelete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemBarcodeAdd(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-barcode-add")
+		return s.goRoutineWrapper(ManagerItemBarcodeAdd, s.HandleManagerItemBarcodeAdd, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemBarcodeRemove(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-barcode-remove")
+		return s.goRoutineWrapper(ManagerItemBarcodeRemove, s.HandleManagerItemBarcodeRemove, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemAttributes(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-attributes")
+		return s.goRoutineWrapper(ManagerItemAttributes, s.HandleManagerItemAttributes, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemAttributesSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-attributes-set")
+		return s.goRoutineWrapper(ManagerItemAttributesSet, s.HandleManagerItemAttributesSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategoryAttributeSchema(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-attribute-schema")
+		return s.goRoutineWrapper(ManagerCategoryAttributeSchema, s.HandleManagerCategoryAttributeSchema, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleProductGroup(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "product-group")
+		return s.goRoutineWrapper(ProductGroup, s.HandleGetProductGroup, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerProductGroupCreate(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-product-group-create")
+		return s.goRoutineWrapper(ManagerProductGroupCreate, s.HandleManagerProductGroupCreate, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerProductGroupVariantAdd(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-product-group-variant-add")
+		return s.goRoutineWrapper(ManagerProductGroupVariantAdd, s.HandleManagerProductGroupVariantAdd, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerProductGroupVariantRemove(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-product-group-variant-remove")
+		return s.goRoutineWrapper(ManagerProductGroupVariantRemove, s.HandleManagerProductGroupVariantRemove, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerProductGroupDefault(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-product-group-default")
+		return s.goRoutineWrapper(ManagerProductGroupDefault, s.HandleManagerProductGroupDefault, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleBundle(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "bundle")
+		return s.goRoutineWrapper(Bundle, s.HandleGetBundle, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBundleSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-bundle-set")
+		return s.goRoutineWrapper(ManagerBundleSet, s.HandleManagerBundleSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBundleRemove(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-bundle-remove")
+		return s.goRoutineWrapper(ManagerBundleRemove, s.HandleManagerBundleRemove, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleRecommendationsCart(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "recommendations/cart")
+		return s.goRoutineWrapper(RecommendationsCart, s.HandleRecommendationsCart, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleRecommendationsBuyAgain(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "recommendations/buy-again")
+		return s.goRoutineWrapper(RecommendationsBuyAgain, s.HandleRecommendationsBuyAgain, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleSearch(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "search")
+		return s.goRoutineWrapper(Search, s.HandleSearch, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleSearchSynonyms(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "search-synonyms")
+		return s.goRoutineWrapper(SearchSynonyms, s.HandleGetSearchSynonyms, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerSearchSynonymSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-search-synonym-set")
+		return s.goRoutineWrapper(ManagerSearchSynonymSet, s.HandleManagerSearchSynonymSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerSearchSynonymDelete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-search-synonym-delete")
+		return s.goRoutineWrapper(ManagerSearchSynonymDelete, s.HandleManagerSearchSynonymDelete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleSearchAutocomplete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "search-autocomplete")
+		return s.goRoutineWrapper(SearchAutocomplete, s.HandleGetSearchAutocomplete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleSearchEvent(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "search-event")
+		return s.goRoutineWrapper(SearchEvent, s.HandleSearchEvent, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerSearchReport(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-search-report")
+		return s.goRoutineWrapper(ManagerSearchReport, s.HandleManagerSearchReport, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerPriceSchedule(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-price-schedule")
+		return s.goRoutineWrapper(ManagerPriceSchedule, s.HandleManagerPriceSchedule, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerPriceScheduleCancel(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-price-schedule-cancel")
+		return s.goRoutineWrapper(ManagerPriceScheduleCancel, s.HandleManagerPriceScheduleCancel, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemPriceHistory(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-price-history")
+		return s.goRoutineWrapper(ManagerItemPriceHistory, s.HandleManagerItemPriceHistory, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerStoreAssortment(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-store-assortment")
+		return s.goRoutineWrapper(ManagerStoreAssortment, s.HandleManagerStoreAssortment, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerStoreAssortmentSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-store-assortment-set")
+		return s.goRoutineWrapper(ManagerStoreAssortmentSet, s.HandleManagerStoreAssortmentSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerStorePriceOverride(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-store-price-override")
+		return s.goRoutineWrapper(ManagerStorePriceOverride, s.HandleManagerStorePriceOverride, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerStoreAssortmentClone(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-store-assortment-clone")
+		return s.goRoutineWrapper(ManagerStoreAssortmentClone, s.HandleManagerStoreAssortmentClone, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemLifecycle(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-lifecycle")
+		return s.goRoutineWrapper(ManagerItemLifecycle, s.HandleManagerItemLifecycle, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemStatusSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-status-set")
+		return s.goRoutineWrapper(ManagerItemStatusSet, s.HandleManagerItemStatusSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCatalogHealth(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-catalog-health")
+		return s.goRoutineWrapper(ManagerCatalogHealth, s.HandleManagerCatalogHealth, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerLooseItemSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-loose-item-set")
+		return s.goRoutineWrapper(ManagerLooseItemSet, s.HandleManagerLooseItemSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerOrderWeight(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-order-weight")
+		return s.goRoutineWrapper(ManagerOrderWeight, s.HandleManagerOrderWeight, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerOrderWeightSettle(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-order-weight-settle")
+		return s.goRoutineWrapper(ManagerOrderWeightSettle, s.HandleManagerOrderWeightSettle, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerPurchaseLimits(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-purchase-limits")
+		return s.goRoutineWrapper(ManagerPurchaseLimits, s.HandleManagerPurchaseLimits, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerPurchaseLimitSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-purchase-limit-set")
+		return s.goRoutineWrapper(ManagerPurchaseLimitSet, s.HandleManagerPurchaseLimitSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerItemRestrictionsSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-item-restrictions-set")
+		return s.goRoutineWrapper(ManagerItemRestrictionsSet, s.HandleManagerItemRestrictionsSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleCartRestrictions(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "cart-restrictions")
+		return s.goRoutineWrapper(CartRestrictions, s.HandleCartRestrictions, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleCartRestrictionConfirm(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "cart-restriction-confirm")
+		return s.goRoutineWrapper(CartRestrictionConfirm, s.HandleCartRestrictionConfirm, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCatalogTranslation(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-catalog-translation")
+		return s.goRoutineWrapper(ManagerCatalogTranslation, s.HandleManagerCatalogTranslation, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCatalogTranslationSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-catalog-translation-set")
+		return s.goRoutineWrapper(ManagerCatalogTranslationSet, s.HandleManagerCatalogTranslationSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerTranslationsMissing(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-translations-missing")
+		return s.goRoutineWrapper(ManagerTranslationsMissing, s.HandleManagerTranslationsMissing, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleBrandItems(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "brand-items")
+		return s.goRoutineWrapper(BrandItems, s.HandleGetBrandItems, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrand(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand")
+		return s.goRoutineWrapper(ManagerBrand, s.HandleManagerBrand, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrandUpdate(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand-update")
+		return s.goRoutineWrapper(ManagerBrandUpdate, s.HandleManagerBrandUpdate, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrandDelete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand-delete")
+		return s.goRoutineWrapper(ManagerBrandDelete, s.HandleManagerBrandDelete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrandLogoUpload(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand-logo-upload")
+		return s.goRoutineWrapper(ManagerBrandLogoUpload, s.HandleManagerBrandLogoUpload, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrandVendorLink(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand-vendor-link")
+		return s.goRoutineWrapper(ManagerBrandVendorLink, s.HandleManagerBrandVendorLink, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerBrandVendorUnlink(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-brand-vendor-unlink")
+		return s.goRoutineWrapper(ManagerBrandVendorUnlink, s.HandleManagerBrandVendorUnlink, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerSupplierItems(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-supplier-items")
+		return s.goRoutineWrapper(ManagerSupplierItems, s.HandleManagerSupplierItems, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleHome(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "home")
+		return s.goRoutineWrapper(Home, s.HandleGetHome, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleHomeCollection(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "GET" {
+		print_path("GET", "home-collection")
+		return s.goRoutineWrapper(HomeCollection, s.HandleGetHomeCollection, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeSections(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-sections")
+		return s.goRoutineWrapper(ManagerHomeSections, s.HandleManagerHomeSections, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeSectionSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-section-set")
+		return s.goRoutineWrapper(ManagerHomeSectionSet, s.HandleManagerHomeSectionSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeSectionDelete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-section-delete")
+		return s.goRoutineWrapper(ManagerHomeSectionDelete, s.HandleManagerHomeSectionDelete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeBannerSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-banner-set")
+		return s.goRoutineWrapper(ManagerHomeBannerSet, s.HandleManagerHomeBannerSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeBannerDelete(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-banner-delete")
+		return s.goRoutineWrapper(ManagerHomeBannerDelete, s.HandleManagerHomeBannerDelete, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeCollectionItemsSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-collection-items-set")
+		return s.goRoutineWrapper(ManagerHomeCollectionItemsSet, s.HandleManagerHomeCollectionItemsSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerHomeOrderSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-home-order-set")
+		return s.goRoutineWrapper(ManagerHomeOrderSet, s.HandleManagerHomeOrderSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerPackerStoreSet(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-packer-store-set")
+		return s.goRoutineWrapper(ManagerPackerStoreSet, s.HandleManagerPackerStoreSet, res, req)
+	}
+	return nil
+}
+
+func (s *Server) handleManagerCategoryMigrationDropped(res http.ResponseWriter, req *http.Request) error {
+	if req.Method == "POST" {
+		print_path("POST", "manager-category-migration-dropped")
+		return s.goRoutineWrapper(ManagerCategoryMigrationDropped, s.HandleManagerCategoryMigrationDropped, res, req)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// acceptLanguages returns the catalog languages of the Accept-Language header in preference order,
+// e.g. "mr-IN, hi;q=0.8, en;q=0.5" gives [mr hi]. English is the base text, so the chain stops at
+// it and an English-first header gives none. The response is marked as varying by the header.
+func acceptLanguages(res http.ResponseWriter, req *http.Request) []string {
+	res.Header().Add("Vary", "Accept-Language")
+
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var accepted []weighted
+	for _, part := range strings.Split(req.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		accepted = append(accepted, weighted{lang, q})
+	}
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].q > accepted[j].q })
+
+	langs := []string{}
+	for _, a := range accepted {
+		if a.lang == types.LanguageEnglish {
+			break
+		}
+		for _, supported := range types.CatalogLanguages {
+			if a.lang == supported && !containsLanguage(langs, a.lang) {
+				langs = append(langs, a.lang)
+			}
+		}
+	}
+	return langs
+}
+
+func containsLanguage(langs []string, lang string) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
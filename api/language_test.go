@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"hi", []string{"hi"}},
+		{"mr-IN, hi;q=0.8, en;q=0.5", []string{"mr", "hi"}},
+		{"hi;q=0.5, mr;q=0.9", []string{"mr", "hi"}},
+		{"en-US, hi;q=0.8", []string{}},
+		{"hi, en, mr", []string{"hi"}},
+		{"fr, hi-IN;q=0.7", []string{"hi"}},
+		{"hi;q=0, mr", []string{"mr"}},
+		{"hi;q=abc, mr;q=0.4", []string{"mr"}},
+		{"hi-IN, hi;q=0.9", []string{"hi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/home", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			res := httptest.NewRecorder()
+
+			if got := acceptLanguages(res, req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acceptLanguages(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+			if vary := res.Header().Get("Vary"); vary != "Accept-Language" {
+				t.Errorf("Vary = %q, want Accept-Language", vary)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// wantsPage reports whether the caller asked for a paginated response.
+// Requests without limit or cursor keep receiving the full list for older app builds.
+func wantsPage(req *http.Request) bool {
+	q := req.URL.Query()
+	return q.Has("limit") || q.Has("cursor")
+}
+
+// parseListParams reads limit, cursor, sort, order, store_id, status, from, to, brand_id and category_id
+// from the query string. from/to accept RFC3339 or YYYY-MM-DD; a bare to date covers the whole day.
+func parseListParams(req *http.Request) (types.ListParams, error) {
+	q := req.URL.Query()
+	params := types.ListParams{
+		Cursor: q.Get("cursor"),
+		Sort:   q.Get("sort"),
+		Status: q.Get("status"),
+	}
+
+	ints := map[string]*int{
+		"limit":       &params.Limit,
+		"store_id":    &params.StoreID,
+		"brand_id":    &params.BrandID,
+		"category_id": &params.CategoryID,
+	}
+	for name, dest := range ints {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return params, fmt.Errorf("invalid %s provided: %s", name, v)
+			}
+			*dest = n
+		}
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		params.Desc = true
+	default:
+		return params, fmt.Errorf("invalid order provided: %s", q.Get("order"))
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseListTime(v)
+		if err != nil {
+			return params, fmt.Errorf("invalid from provided: %w", err)
+		}
+		params.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseListTime(v)
+		if err != nil {
+			return params, fmt.Errorf("invalid to provided: %w", err)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		params.To = &to
+	}
+
+	return params, nil
+}
+
+func parseListTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
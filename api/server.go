@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/girithc/pronto-go/store"
 	"github.com/girithc/pronto-go/worker"
@@ -153,6 +155,94 @@ func (s *Server) Run( /*gs *GoogleServer*/ ) {
 
 	http.HandleFunc("/invoice", makeHTTPHandleFunc(s.handleGenInvoice))
 	http.HandleFunc("/export", makeHTTPHandleFunc(s.handleExport))
+
+	http.HandleFunc("/customer-order-events", makeHTTPHandleFunc(s.handleCustomerOrderEvents))
+	http.HandleFunc("/packer-order-events", makeHTTPHandleFunc(s.handlePackerOrderEvents))
+	http.HandleFunc("/delivery-partner-order-events", makeHTTPHandleFunc(s.handleDeliveryPartnerOrderEvents))
+
+	http.HandleFunc("/manager-webhook-endpoint", makeHTTPHandleFunc(s.handleManagerWebhookEndpoint))
+	http.HandleFunc("/manager-webhook-replay", makeHTTPHandleFunc(s.handleManagerWebhookReplay))
+
+	http.HandleFunc("/manager-catalog-import", makeHTTPHandleFunc(s.handleManagerCatalogImport))
+	http.HandleFunc("/manager-catalog-export", makeHTTPHandleFunc(s.handleManagerCatalogExport))
+
+	http.HandleFunc("/category-tree", makeHTTPHandleFunc(s.handleCategoryTree))
+	http.HandleFunc("/category-breadcrumbs", makeHTTPHandleFunc(s.handleCategoryBreadcrumbs))
+	http.HandleFunc("/manager-category-move", makeHTTPHandleFunc(s.handleManagerCategoryMove))
+	http.HandleFunc("/manager-category-reorder", makeHTTPHandleFunc(s.handleManagerCategoryReorder))
+	http.HandleFunc("/manager-category-store", makeHTTPHandleFunc(s.handleManagerCategoryStore))
+	http.HandleFunc("/manager-category-schedule", makeHTTPHandleFunc(s.handleManagerCategorySchedule))
+
+	http.HandleFunc("/manager-item-image-upload", makeHTTPHandleFunc(s.handleManagerItemImageUpload))
+	http.HandleFunc("/manager-item-image-reorder", makeHTTPHandleFunc(s.handleManagerItemImageReorder))
+	http.HandleFunc("/manager-item-image-delete", makeHTTPHandleFunc(s.handleManagerItemImageDelete))
+	// Uploaded images live on local disk in development; serve them next to the API.
+	if dir, base := s.store.LocalImageDir(), s.store.LocalImageBaseURL(); dir != "" && strings.HasPrefix(base, "/") {
+		http.Handle(base+"/", http.StripPrefix(base+"/", http.FileServer(http.Dir(dir))))
+	}
+
+	http.HandleFunc("/manager-item-barcode-add", makeHTTPHandleFunc(s.handleManagerItemBarcodeAdd))
+	http.HandleFunc("/manager-item-barcode-remove", makeHTTPHandleFunc(s.handleManagerItemBarcodeRemove))
+	http.HandleFunc("/manager-item-attributes", makeHTTPHandleFunc(s.handleManagerItemAttributes))
+	http.HandleFunc("/manager-item-attributes-set", makeHTTPHandleFunc(s.handleManagerItemAttributesSet))
+	http.HandleFunc("/manager-category-attribute-schema", makeHTTPHandleFunc(s.handleManagerCategoryAttributeSchema))
+	http.HandleFunc("/product-group", makeHTTPHandleFunc(s.handleProductGroup))
+	http.HandleFunc("/manager-product-group-create", makeHTTPHandleFunc(s.handleManagerProductGroupCreate))
+	http.HandleFunc("/manager-product-group-variant-add", makeHTTPHandleFunc(s.handleManagerProductGroupVariantAdd))
+	http.HandleFunc("/manager-product-group-variant-remove", makeHTTPHandleFunc(s.handleManagerProductGroupVariantRemove))
+	http.HandleFunc("/manager-product-group-default", makeHTTPHandleFunc(s.handleManagerProductGroupDefault))
+	http.HandleFunc("/bundle", makeHTTPHandleFunc(s.handleBundle))
+	http.HandleFunc("/manager-bundle-set", makeHTTPHandleFunc(s.handleManagerBundleSet))
+	http.HandleFunc("/manager-bundle-remove", makeHTTPHandleFunc(s.handleManagerBundleRemove))
+	http.HandleFunc("/recommendations/cart", makeHTTPHandleFunc(s.handleRecommendationsCart))
+	http.HandleFunc("/recommendations/buy-again", makeHTTPHandleFunc(s.handleRecommendationsBuyAgain))
+	http.HandleFunc("/search", makeHTTPHandleFunc(s.handleSearch))
+	http.HandleFunc("/search-synonyms", makeHTTPHandleFunc(s.handleSearchSynonyms))
+	http.HandleFunc("/manager-search-synonym-set", makeHTTPHandleFunc(s.handleManagerSearchSynonymSet))
+	http.HandleFunc("/manager-search-synonym-delete", makeHTTPHandleFunc(s.handleManagerSearchSynonymDelete))
+	http.HandleFunc("/search-autocomplete", makeHTTPHandleFunc(s.handleSearchAutocomplete))
+	http.HandleFunc("/search-event", makeHTTPHandleFunc(s.handleSearchEvent))
+	http.HandleFunc("/manager-search-report", makeHTTPHandleFunc(s.handleManagerSearchReport))
+	http.HandleFunc("/manager-price-schedule", makeHTTPHandleFunc(s.handleManagerPriceSchedule))
+	http.HandleFunc("/manager-price-schedule-cancel", makeHTTPHandleFunc(s.handleManagerPriceScheduleCancel))
+	http.HandleFunc("/manager-item-price-history", makeHTTPHandleFunc(s.handleManagerItemPriceHistory))
+	http.HandleFunc("/manager-store-assortment", makeHTTPHandleFunc(s.handleManagerStoreAssortment))
+	http.HandleFunc("/manager-store-assortment-set", makeHTTPHandleFunc(s.handleManagerStoreAssortmentSet))
+	http.HandleFunc("/manager-store-price-override", makeHTTPHandleFunc(s.handleManagerStorePriceOverride))
+	http.HandleFunc("/manager-store-assortment-clone", makeHTTPHandleFunc(s.handleManagerStoreAssortmentClone))
+	http.HandleFunc("/manager-item-lifecycle", makeHTTPHandleFunc(s.handleManagerItemLifecycle))
+	http.HandleFunc("/manager-item-status-set", makeHTTPHandleFunc(s.handleManagerItemStatusSet))
+	http.HandleFunc("/manager-catalog-health", makeHTTPHandleFunc(s.handleManagerCatalogHealth))
+	http.HandleFunc("/manager-loose-item-set", makeHTTPHandleFunc(s.handleManagerLooseItemSet))
+	http.HandleFunc("/manager-order-weight", makeHTTPHandleFunc(s.handleManagerOrderWeight))
+	http.HandleFunc("/manager-order-weight-settle", makeHTTPHandleFunc(s.handleManagerOrderWeightSettle))
+	http.HandleFunc("/manager-purchase-limits", makeHTTPHandleFunc(s.handleManagerPurchaseLimits))
+	http.HandleFunc("/manager-purchase-limit-set", makeHTTPHandleFunc(s.handleManagerPurchaseLimitSet))
+	http.HandleFunc("/manager-item-restrictions-set", makeHTTPHandleFunc(s.handleManagerItemRestrictionsSet))
+	http.HandleFunc("/cart-restrictions", makeHTTPHandleFunc(s.handleCartRestrictions))
+	http.HandleFunc("/cart-restriction-confirm", makeHTTPHandleFunc(s.handleCartRestrictionConfirm))
+	http.HandleFunc("/manager-catalog-translation", makeHTTPHandleFunc(s.handleManagerCatalogTranslation))
+	http.HandleFunc("/manager-catalog-translation-set", makeHTTPHandleFunc(s.handleManagerCatalogTranslationSet))
+	http.HandleFunc("/manager-translations-missing", makeHTTPHandleFunc(s.handleManagerTranslationsMissing))
+	http.HandleFunc("/brand-items", makeHTTPHandleFunc(s.handleBrandItems))
+	http.HandleFunc("/manager-brand", makeHTTPHandleFunc(s.handleManagerBrand))
+	http.HandleFunc("/manager-brand-update", makeHTTPHandleFunc(s.handleManagerBrandUpdate))
+	http.HandleFunc("/manager-brand-delete", makeHTTPHandleFunc(s.handleManagerBrandDelete))
+	http.HandleFunc("/manager-brand-logo-upload", makeHTTPHandleFunc(s.handleManagerBrandLogoUpload))
+	http.HandleFunc("/manager-brand-vendor-link", makeHTTPHandleFunc(s.handleManagerBrandVendorLink))
+	http.HandleFunc("/manager-brand-vendor-unlink", makeHTTPHandleFunc(s.handleManagerBrandVendorUnlink))
+	http.HandleFunc("/manager-supplier-items", makeHTTPHandleFunc(s.handleManagerSupplierItems))
+	http.HandleFunc("/home", makeHTTPHandleFunc(s.handleHome))
+	http.HandleFunc("/home-collection", makeHTTPHandleFunc(s.handleHomeCollection))
+	http.HandleFunc("/manager-home-sections", makeHTTPHandleFunc(s.handleManagerHomeSections))
+	http.HandleFunc("/manager-home-section-set", makeHTTPHandleFunc(s.handleManagerHomeSectionSet))
+	http.HandleFunc("/manager-home-section-delete", makeHTTPHandleFunc(s.handleManagerHomeSectionDelete))
+	http.HandleFunc("/manager-home-banner-set", makeHTTPHandleFunc(s.handleManagerHomeBannerSet))
+	http.HandleFunc("/manager-home-banner-delete", makeHTTPHandleFunc(s.handleManagerHomeBannerDelete))
+	http.HandleFunc("/manager-home-collection-items-set", makeHTTPHandleFunc(s.handleManagerHomeCollectionItemsSet))
+	http.HandleFunc("/manager-home-order-set", makeHTTPHandleFunc(s.handleManagerHomeOrderSet))
+	http.HandleFunc("/manager-packer-store-set", makeHTTPHandleFunc(s.handleManagerPackerStoreSet))
+	http.HandleFunc("/manager-category-migration-dropped", makeHTTPHandleFunc(s.handleManagerCategoryMigrationDropped))
 	fmt.Println("Listening PORT", s.listen_address)
 
 	http.ListenAndServe(s.listen_address, nil)
@@ -164,12 +254,23 @@ type apiFunc func(http.ResponseWriter, *http.Request) error
 
 type ApiError struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// codedError is implemented by store errors that carry a code clients can branch on.
+type codedError interface {
+	ErrorCode() string
 }
 
 func makeHTTPHandleFunc(f apiFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := f(w, r); err != nil {
-			WriteJSON(w, http.StatusBadRequest, ApiError{Error: err.Error()})
+			apiErr := ApiError{Error: err.Error()}
+			var coded codedError
+			if errors.As(err, &coded) {
+				apiErr.Code = coded.ErrorCode()
+			}
+			WriteJSON(w, http.StatusBadRequest, apiErr)
 		}
 	}
 }
@@ -180,3 +281,19 @@ func WriteJSON(w http.ResponseWriter, status int, v any) error {
 
 	return json.NewEncoder(w).Encode(v)
 }
+
+// WriteCachedJSON writes v with its ETag, or a bare 304 when the client's If-None-Match already holds it.
+func WriteCachedJSON(w http.ResponseWriter, req *http.Request, etag string, v any) error {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	for _, tag := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	return WriteJSON(w, http.StatusOK, v)
+}
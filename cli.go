@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/girithc/pronto-go/store"
+	"github.com/girithc/pronto-go/types"
+)
+
+// runCLI runs a one-off command instead of the server, e.g.
+//
+//	pronto-go export-catalog -store 1 -format jsonl -out catalog.jsonl
+//	pronto-go refresh-recommendations
+//	pronto-go catalog-health -store 1 -limit 20
+func runCLI(command string, args []string) int {
+	switch command {
+	case "export-catalog":
+		return exportCatalogCommand(args)
+	case "refresh-recommendations":
+		return refreshRecommendationsCommand(args)
+	case "catalog-health":
+		return catalogHealthCommand(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", command)
+		fmt.Fprintln(os.Stderr, "commands: export-catalog, refresh-recommendations, catalog-health")
+		return 2
+	}
+}
+
+func exportCatalogCommand(args []string) int {
+	fs := flag.NewFlagSet("export-catalog", flag.ContinueOnError)
+	storeID := fs.Int("store", 1, "store id to export")
+	format := fs.String("format", types.CatalogExportCSV, "csv or jsonl")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	s, stdout, cleanup := openQuietStore()
+	defer cleanup()
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	buffered := bufio.NewWriter(w)
+
+	if err := s.ExportStoreCatalog(*storeID, *format, buffered); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := buffered.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// openQuietStore opens the store for a command whose output goes to stdout. The store reports its
+// progress with fmt.Println, so until cleanup runs os.Stdout points at stderr and the command
+// writes its output to the returned writer.
+func openQuietStore() (*store.PostgresStore, io.Writer, func()) {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	s, closeStore := store.NewPostgresStore()
+	return s, stdout, func() {
+		closeStore()
+		os.Stdout = stdout
+	}
+}
+
+func refreshRecommendationsCommand(args []string) int {
+	fs := flag.NewFlagSet("refresh-recommendations", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	s, cleanup := store.NewPostgresStore()
+	defer cleanup()
+
+	run, err := s.RefreshRecommendations()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if run == nil {
+		fmt.Fprintln(os.Stderr, "another refresh is already running")
+		return 1
+	}
+	fmt.Printf("recommendations: %d orders, %d pairs, %d customers\n", run.Orders, run.Pairs, run.Customers)
+	return 0
+}
+
+func catalogHealthCommand(args []string) int {
+	fs := flag.NewFlagSet("catalog-health", flag.ContinueOnError)
+	storeID := fs.Int("store", 0, "store id to check (default every store)")
+	limit := fs.Int("limit", 20, "items listed per issue")
+	discontinued := fs.Bool("discontinued", false, "include discontinued items")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	s, out, cleanup := openQuietStore()
+	defer cleanup()
+
+	report, err := s.CatalogHealthReport(types.CatalogHealthRequest{
+		StoreID:             *storeID,
+		Limit:               *limit,
+		IncludeDiscontinued: *discontinued,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		fmt.Fprintf(out, "catalog health: %d items checked, %d with issues\n", report.ItemsChecked, report.ItemsWithIssues)
+		for _, issue := range report.Issues {
+			fmt.Fprintf(out, "\n%s: %d\n", issue.Issue, issue.Count)
+			for _, item := range issue.Items {
+				line := fmt.Sprintf("  %d\t%s\t%s", item.ItemID, item.Name, item.Status)
+				if item.Detail != "" {
+					line += "\t" + item.Detail
+				}
+				fmt.Fprintln(out, line)
+			}
+			if hidden := issue.Count - len(issue.Items); hidden > 0 {
+				fmt.Fprintf(out, "  ... and %d more\n", hidden)
+			}
+		}
+	}
+
+	// a non-zero exit lets the command gate a catalog pipeline
+	if report.ItemsWithIssues > 0 {
+		return 1
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1], os.Args[2:]))
+	}
+
 	fmt.Printf("Starting Pronto-DB\n\n")
 
 	workerPool := worker.NewWorkerPool(10)
@@ -27,6 +32,31 @@ func main() {
 	} else {
 		fmt.Println("Store.Init() is successful.")
 	}
+
+	go func() {
+		if err := store.ListenOrderEvents(context.Background()); err != nil {
+			log.Printf("order events listener stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := store.RunWebhookRelay(context.Background()); err != nil {
+			log.Printf("webhook relay stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := store.RunRecommendationJob(context.Background()); err != nil {
+			log.Printf("recommendation job stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := store.RunPriceScheduler(context.Background()); err != nil {
+			log.Printf("price scheduler stopped: %v", err)
+		}
+	}()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -1,10 +1,14 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/girithc/pronto-go/types"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func (s *PostgresStore) CreateBrandTable(tx *sql.Tx) error {
@@ -22,19 +26,39 @@ func (s *PostgresStore) CreateBrandTable(tx *sql.Tx) error {
 		return err
 	}
 
+	// logo_prefix locates uploaded logo files, so replacing a logo removes them
+	alterQuery := `
+    ALTER TABLE brand
+        ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS logo_url TEXT NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS logo_thumbnail_url TEXT NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS logo_prefix TEXT,
+        ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP`
+
+	if _, err := tx.Exec(alterQuery); err != nil {
+		return fmt.Errorf("error adding brand profile columns: %w", err)
+	}
+
 	return nil
 }
 
+// brandColumns are scanned by scanBrand, in its order.
+const brandColumns = `id, name, description, logo_url, logo_thumbnail_url, created_at, COALESCE(created_by, 0)`
+
+func scanBrand(row interface{ Scan(...interface{}) error }, brand *types.Brand) error {
+	return row.Scan(&brand.ID, &brand.Name, &brand.Description, &brand.Logo_URL, &brand.Logo_Thumbnail_URL, &brand.Created_At, &brand.Created_By)
+}
+
 func (s *PostgresStore) CreateBrand(br *types.Brand) (*types.Brand, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
-	query := `SELECT id, name, created_at, created_by FROM brand WHERE name = $1`
+	query := `SELECT ` + brandColumns + ` FROM brand WHERE name = $1`
 	existBrand := &types.Brand{}
 
-	err = tx.QueryRow(query, br.Name).Scan(&existBrand.ID, &existBrand.Name, &existBrand.Created_At, &existBrand.Created_By)
+	err = scanBrand(tx.QueryRow(query, br.Name), existBrand)
 	if err == nil {
 		return existBrand, nil
 	} else if err != sql.ErrNoRows {
@@ -42,12 +66,12 @@ func (s *PostgresStore) CreateBrand(br *types.Brand) (*types.Brand, error) {
 	}
 
 	brandInsertQuery := `
-	INSERT INTO brand (name, created_by) 
-	VALUES ($1, $2) 
-	RETURNING id, name, created_at, created_by`
+	INSERT INTO brand (name, description, logo_url, logo_thumbnail_url, created_by) 
+	VALUES ($1, $2, $3, $3, $4) 
+	RETURNING ` + brandColumns
 
 	result := &types.Brand{}
-	err = tx.QueryRow(brandInsertQuery, br.Name, br.Created_By).Scan(&result.ID, &result.Name, &result.Created_At, &result.Created_By)
+	err = scanBrand(tx.QueryRow(brandInsertQuery, br.Name, br.Description, br.Logo_URL, br.Created_By), result)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +94,7 @@ func (s *PostgresStore) GetBrands() ([]*types.Brand, error) {
 	defer tx.Rollback()
 
 	query := `
-	SELECT id, name, created_by, created_at 
+	SELECT ` + brandColumns + `
 	FROM brand
 	ORDER BY name
 	`
@@ -89,12 +113,7 @@ func (s *PostgresStore) GetBrands() ([]*types.Brand, error) {
 	for rows.Next() {
 		brand := &types.Brand{}
 
-		err := rows.Scan(
-			&brand.ID,
-			&brand.Name,
-			&brand.Created_By,
-			&brand.Created_At,
-		)
+		err := scanBrand(rows, brand)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into brand in getbrands: %w", err)
 		}
@@ -108,7 +127,7 @@ func (s *PostgresStore) GetBrands() ([]*types.Brand, error) {
 func (s *PostgresStore) GetBrandsList() ([]Brand, error) {
 	var brands []Brand
 
-	query := `SELECT id, name FROM brand ORDER BY name ASC` // Updated query
+	query := `SELECT id, name, logo_thumbnail_url FROM brand ORDER BY name ASC` // Updated query
 	rows, err := s.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying brands: %w", err)
@@ -117,7 +136,7 @@ func (s *PostgresStore) GetBrandsList() ([]Brand, error) {
 
 	for rows.Next() {
 		var brand Brand
-		if err := rows.Scan(&brand.ID, &brand.Name); err != nil {
+		if err := rows.Scan(&brand.ID, &brand.Name, &brand.Logo); err != nil {
 			return nil, fmt.Errorf("error scanning brand row: %w", err)
 		}
 		brands = append(brands, brand)
@@ -134,4 +153,364 @@ func (s *PostgresStore) GetBrandsList() ([]Brand, error) {
 type Brand struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+// brandLogoThumbnail is the side of the square a brand logo thumbnail fits in.
+const brandLogoThumbnail = 256
+
+func (s *PostgresStore) GetBrand(id int) (*types.Brand, error) {
+	brand := &types.Brand{}
+	err := scanBrand(s.db.QueryRow(`SELECT `+brandColumns+` FROM brand WHERE id = $1`, id), brand)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brand with id = [%d] not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying brand %d: %w", id, err)
+	}
+	return brand, nil
+}
+
+// GetBrandDetail returns a brand with its vendors, the primary one first, and its item count.
+func (s *PostgresStore) GetBrandDetail(id int) (*types.BrandDetail, error) {
+	brand, err := s.GetBrand(id)
+	if err != nil {
+		return nil, err
+	}
+	detail := &types.BrandDetail{Brand: *brand, Vendors: []types.BrandVendor{}}
+
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM item WHERE brand_id = $1`, id).Scan(&detail.ItemCount); err != nil {
+		return nil, fmt.Errorf("error counting items of brand %d: %w", id, err)
+	}
+
+	rows, err := s.db.Query(`
+        SELECT v.id, v.name, v.phone, vb.is_primary
+        FROM vendor_brand vb
+        JOIN vendor v ON v.id = vb.vendor_id
+        WHERE vb.brand_id = $1
+        ORDER BY vb.is_primary DESC, v.name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error querying vendors of brand %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var vendor types.BrandVendor
+		if err := rows.Scan(&vendor.VendorID, &vendor.Name, &vendor.Phone, &vendor.Primary); err != nil {
+			return nil, fmt.Errorf("error scanning brand vendor: %w", err)
+		}
+		detail.Vendors = append(detail.Vendors, vendor)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// UpdateBrand edits a brand's name, description and linked logo; empty fields keep their value.
+// Item listings embed the brand name, so they are dropped from the catalog cache.
+func (s *PostgresStore) UpdateBrand(req types.UpdateBrand) (*types.Brand, error) {
+	var oldPrefix sql.NullString
+	brand := &types.Brand{}
+	err := s.db.QueryRow(`
+        WITH old AS (SELECT logo_prefix FROM brand WHERE id = $1 FOR UPDATE)
+        UPDATE brand SET
+            name = COALESCE(NULLIF($2, ''), brand.name),
+            description = COALESCE(NULLIF($3, ''), brand.description),
+            logo_url = COALESCE(NULLIF($4, ''), brand.logo_url),
+            logo_thumbnail_url = COALESCE(NULLIF($4, ''), brand.logo_thumbnail_url),
+            logo_prefix = CASE WHEN $4 = '' THEN brand.logo_prefix END,
+            updated_at = CURRENT_TIMESTAMP
+        FROM old
+        WHERE brand.id = $1
+        RETURNING CASE WHEN $4 <> '' THEN old.logo_prefix END, `+brandColumnsQualified,
+		req.ID, strings.TrimSpace(req.Name), strings.TrimSpace(req.Description), strings.TrimSpace(req.Logo_URL)).Scan(
+		&oldPrefix, &brand.ID, &brand.Name, &brand.Description, &brand.Logo_URL, &brand.Logo_Thumbnail_URL, &brand.Created_At, &brand.Created_By)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brand with id = [%d] not found", req.ID)
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("a brand named %q already exists", req.Name)
+		}
+		return nil, fmt.Errorf("error updating brand %d: %w", req.ID, err)
+	}
+	if oldPrefix.Valid {
+		s.removeItemImageFiles(oldPrefix.String)
+	}
+
+	s.catalog.invalidateAllItems()
+	return brand, nil
+}
+
+// DeleteBrand removes a brand that no item uses, with its vendor links and logo files.
+func (s *PostgresStore) DeleteBrand(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var prefix sql.NullString
+	err = tx.QueryRow(`SELECT logo_prefix FROM brand WHERE id = $1 FOR UPDATE`, id).Scan(&prefix)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("brand with id = [%d] not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error querying brand %d: %w", id, err)
+	}
+
+	// item.brand_id cascades, so deleting a brand in use would delete its items
+	var items int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM item WHERE brand_id = $1`, id).Scan(&items); err != nil {
+		return fmt.Errorf("error counting items of brand %d: %w", id, err)
+	}
+	if items > 0 {
+		return fmt.Errorf("brand %d has %d items, move them to another brand first", id, items)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM vendor_brand WHERE brand_id = $1`, id); err != nil {
+		return fmt.Errorf("error removing vendors of brand %d: %w", id, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM brand WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("error deleting brand %d: %w", id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	if prefix.Valid {
+		s.removeItemImageFiles(prefix.String)
+	}
+	return nil
+}
+
+// UploadBrandLogo validates an uploaded JPEG or PNG logo, stores it with a thumbnail and replaces
+// the brand's logo.
+func (s *PostgresStore) UploadBrandLogo(brandID int, data []byte) (*types.Brand, error) {
+	if _, err := s.GetBrand(brandID); err != nil {
+		return nil, err
+	}
+
+	img, format, err := decodeItemImage(data)
+	if err != nil {
+		return nil, err
+	}
+	thumbnailData, err := encodeJPEG(downscale(flattenImage(img), brandLogoThumbnail))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	prefix := fmt.Sprintf("brands/%d/%s", brandID, uuid.NewString())
+	originalKey, contentType := prefix+"/original.jpg", "image/jpeg"
+	if format == "png" {
+		originalKey, contentType = prefix+"/original.png", "image/png"
+	}
+
+	logoURL, err := s.images.put(ctx, originalKey, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	thumbnailURL, err := s.images.put(ctx, prefix+"/thumbnail.jpg", "image/jpeg", thumbnailData)
+	if err != nil {
+		s.removeItemImageFiles(prefix)
+		return nil, err
+	}
+
+	var oldPrefix sql.NullString
+	brand := &types.Brand{}
+	err = s.db.QueryRow(`
+        WITH old AS (SELECT logo_prefix FROM brand WHERE id = $1 FOR UPDATE)
+        UPDATE brand SET logo_url = $2, logo_thumbnail_url = $3, logo_prefix = $4, updated_at = CURRENT_TIMESTAMP
+        FROM old
+        WHERE brand.id = $1
+        RETURNING old.logo_prefix, `+brandColumnsQualified, brandID, logoURL, thumbnailURL, prefix).Scan(
+		&oldPrefix, &brand.ID, &brand.Name, &brand.Description, &brand.Logo_URL, &brand.Logo_Thumbnail_URL, &brand.Created_At, &brand.Created_By)
+	if err != nil {
+		s.removeItemImageFiles(prefix)
+		return nil, fmt.Errorf("error saving logo of brand %d: %w", brandID, err)
+	}
+	if oldPrefix.Valid {
+		s.removeItemImageFiles(oldPrefix.String)
+	}
+
+	s.catalog.invalidateAllItems()
+	return brand, nil
+}
+
+// brandColumnsQualified is brandColumns for queries that join brand with other tables.
+const brandColumnsQualified = `brand.id, brand.name, brand.description, brand.logo_url, brand.logo_thumbnail_url, brand.created_at, COALESCE(brand.created_by, 0)`
+
+// CachedBrandLanding returns the brand page of a store, with the brand's active items in stock
+// there, through the catalog cache along with its ETag.
+func (s *PostgresStore) CachedBrandLanding(brandID int, storeID int, langs []string) (*types.BrandLanding, string, error) {
+	key := fmt.Sprintf("%sbrand=%d:store=%d", catalogKeyItemsByCatAndStore, brandID, storeID) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.(*types.BrandLanding), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	landing, err := s.GetBrandLanding(brandID, storeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.translateItemListing(langs, landing.Items); err != nil {
+		return nil, "", err
+	}
+	etag, err := catalogETag(landing)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The page lists only items in stock, so it also goes when one of the brand's other items moves
+	itemIDs, err := brandStoreItemIDs(s.reader(readCatalog), brandID, storeID)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, item := range landing.Items {
+		itemIDs[item.ID] = true
+		for _, variant := range item.Variants {
+			itemIDs[variant.ItemID] = true
+		}
+	}
+	s.catalog.put(key, gen, &catalogEntry{value: landing, etag: etag, storeID: storeID, itemIDs: itemIDs})
+	return landing, etag, nil
+}
+
+// brandStoreItemIDs returns the items of brandID that storeID carries, whether in stock or not.
+func brandStoreItemIDs(q queryer, brandID int, storeID int) (map[int]bool, error) {
+	rows, err := q.Query(`
+        SELECT istore.item_id FROM item_store istore
+        JOIN item i ON i.id = istore.item_id
+        WHERE i.brand_id = $1 AND istore.store_id = $2`, brandID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying items of brand %d: %w", brandID, err)
+	}
+	defer rows.Close()
+
+	itemIDs := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning item of brand %d: %w", brandID, err)
+		}
+		itemIDs[id] = true
+	}
+	return itemIDs, rows.Err()
+}
+
+func (s *PostgresStore) GetBrandLanding(brandID int, storeID int) (*types.BrandLanding, error) {
+	tx, err := s.reader(readCatalog).Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	landing := &types.BrandLanding{StoreID: storeID}
+	err = scanBrand(tx.QueryRow(`SELECT `+brandColumns+` FROM brand WHERE id = $1`, brandID), &landing.Brand)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("brand with id = [%d] not found", brandID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying brand %d: %w", brandID, err)
+	}
+
+	query := storeItemCardsQuery("i.brand_id = $2 AND istore.stock_quantity > 0", "istore.stock_quantity DESC")
+	landing.Items, err = queryItemCards(tx, query, storeID, brandID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying items of brand %d: %w", brandID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return landing, nil
+}
+
+// LinkBrandVendor links a brand to a vendor. Making the vendor primary demotes the brand's
+// previous primary vendor.
+func (s *PostgresStore) LinkBrandVendor(link types.BrandVendorLink) (*types.BrandDetail, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if link.Primary {
+		_, err := tx.Exec(`UPDATE vendor_brand SET is_primary = FALSE WHERE brand_id = $1 AND vendor_id <> $2 AND is_primary`,
+			link.BrandID, link.VendorID)
+		if err != nil {
+			return nil, fmt.Errorf("error demoting primary vendor of brand %d: %w", link.BrandID, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO vendor_brand (vendor_id, brand_id, is_primary)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (vendor_id, brand_id) DO UPDATE SET is_primary = EXCLUDED.is_primary`,
+		link.VendorID, link.BrandID, link.Primary)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("brand %d or vendor %d does not exist", link.BrandID, link.VendorID)
+		}
+		return nil, fmt.Errorf("error linking brand %d to vendor %d: %w", link.BrandID, link.VendorID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return s.GetBrandDetail(link.BrandID)
+}
+
+func (s *PostgresStore) UnlinkBrandVendor(link types.BrandVendorLink) (*types.BrandDetail, error) {
+	res, err := s.db.Exec(`DELETE FROM vendor_brand WHERE vendor_id = $1 AND brand_id = $2`, link.VendorID, link.BrandID)
+	if err != nil {
+		return nil, fmt.Errorf("error unlinking brand %d from vendor %d: %w", link.BrandID, link.VendorID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("brand %d is not linked to vendor %d", link.BrandID, link.VendorID)
+	}
+	return s.GetBrandDetail(link.BrandID)
+}
+
+// GetSupplierItems groups the items listed at a store by the vendor of their brand. A brand with
+// several vendors is bought from its primary vendor, or else from the vendor linked first.
+// VendorID narrows the result to one vendor, 0 lists every vendor and the unassigned items.
+func (s *PostgresStore) GetSupplierItems(req types.SupplierItemsRequest) ([]types.SupplierItems, error) {
+	rows, err := s.reader(readExport).Query(`
+        SELECT COALESCE(v.id, 0), COALESCE(v.name, ''), i.id, i.name, COALESCE(b.id, 0), COALESCE(b.name, ''),
+               istore.stock_quantity, istore.locked_quantity
+        FROM item_store istore
+        JOIN item i ON i.id = istore.item_id
+        LEFT JOIN brand b ON b.id = i.brand_id
+        LEFT JOIN LATERAL (
+            SELECT vb.vendor_id FROM vendor_brand vb
+            WHERE vb.brand_id = i.brand_id
+            ORDER BY vb.is_primary DESC, vb.created_at, vb.vendor_id
+            LIMIT 1
+        ) supplier ON TRUE
+        LEFT JOIN vendor v ON v.id = supplier.vendor_id
+        WHERE istore.store_id = $1 AND istore.listed AND i.status <> 'discontinued'
+          AND ($2 = 0 OR v.id = $2)
+        ORDER BY v.name NULLS LAST, v.id, b.name, i.name`, req.StoreID, req.VendorID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying supplier items: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []types.SupplierItems{}
+	for rows.Next() {
+		var vendorID int
+		var vendor string
+		var item types.SupplierItem
+		if err := rows.Scan(&vendorID, &vendor, &item.ItemID, &item.Name, &item.BrandID, &item.Brand,
+			&item.Stock_Quantity, &item.Locked_Quantity); err != nil {
+			return nil, fmt.Errorf("error scanning supplier item: %w", err)
+		}
+		if len(groups) == 0 || groups[len(groups)-1].VendorID != vendorID {
+			groups = append(groups, types.SupplierItems{VendorID: vendorID, Vendor: vendor, Items: []types.SupplierItem{}})
+		}
+		groups[len(groups)-1].Items = append(groups[len(groups)-1].Items, item)
+	}
+	return groups, rows.Err()
 }
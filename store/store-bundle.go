@@ -0,0 +1,317 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// CreateBundleTables lets an item be a bundle of component items. A bundle keeps its own item_store
+// row for its price, but the stock_quantity of that row is maintained by triggers as the number of
+// bundles the components in the same store can make. cart_pick_item expands carts into what is picked.
+func (s *PostgresStore) CreateBundleTables(tx *sql.Tx) error {
+	tableQuery := `
+    CREATE TABLE IF NOT EXISTS bundle_component (
+        bundle_item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        component_item_id INT NOT NULL REFERENCES item(id) ON DELETE RESTRICT,
+        quantity INT NOT NULL CHECK (quantity > 0),
+        PRIMARY KEY (bundle_item_id, component_item_id),
+        CONSTRAINT bundle_component_not_self CHECK (bundle_item_id <> component_item_id)
+    );
+
+    CREATE INDEX IF NOT EXISTS bundle_component_component ON bundle_component (component_item_id);`
+
+	if _, err := tx.Exec(tableQuery); err != nil {
+		return fmt.Errorf("error creating bundle_component table: %w", err)
+	}
+
+	functionQuery := `
+    CREATE OR REPLACE FUNCTION bundle_available_stock(p_bundle_item_id INT, p_store_id INT) RETURNS INT AS $$
+        SELECT COALESCE(MIN(COALESCE(cs.stock_quantity, 0) / bc.quantity), 0)::INT
+        FROM bundle_component bc
+        LEFT JOIN item_store cs ON cs.item_id = bc.component_item_id AND cs.store_id = p_store_id
+        WHERE bc.bundle_item_id = p_bundle_item_id
+    $$ LANGUAGE sql STABLE;
+
+    CREATE OR REPLACE FUNCTION item_store_bundle_stock() RETURNS trigger AS $$
+    BEGIN
+        IF EXISTS (SELECT 1 FROM bundle_component WHERE bundle_item_id = NEW.item_id) THEN
+            NEW.stock_quantity := bundle_available_stock(NEW.item_id, NEW.store_id);
+        END IF;
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    CREATE OR REPLACE FUNCTION item_store_refresh_bundles() RETURNS trigger AS $$
+    BEGIN
+        -- Touching the bundle rows lets item_store_bundle_stock recompute them
+        UPDATE item_store b SET stock_quantity = b.stock_quantity
+        WHERE b.store_id = NEW.store_id
+          AND b.item_id IN (SELECT bundle_item_id FROM bundle_component WHERE component_item_id = NEW.item_id);
+        RETURN NULL;
+    END;
+    $$ LANGUAGE plpgsql;`
+
+	if _, err := tx.Exec(functionQuery); err != nil {
+		return fmt.Errorf("error creating bundle stock functions: %w", err)
+	}
+
+	triggerQuery := `
+    DROP TRIGGER IF EXISTS item_store_bundle_stock ON item_store;
+    CREATE TRIGGER item_store_bundle_stock
+        BEFORE INSERT OR UPDATE ON item_store
+        FOR EACH ROW EXECUTE FUNCTION item_store_bundle_stock();
+
+    DROP TRIGGER IF EXISTS item_store_refresh_bundles ON item_store;
+    CREATE TRIGGER item_store_refresh_bundles
+        AFTER INSERT OR UPDATE OF stock_quantity ON item_store
+        FOR EACH ROW EXECUTE FUNCTION item_store_refresh_bundles();`
+
+	if _, err := tx.Exec(triggerQuery); err != nil {
+		return fmt.Errorf("error creating bundle stock triggers: %w", err)
+	}
+
+	viewQuery := `
+    CREATE OR REPLACE VIEW cart_pick_item AS
+    SELECT ci.cart_id,
+           COALESCE(bc.component_item_id, ci.item_id) AS item_id,
+           ci.quantity * COALESCE(bc.quantity, 1) AS quantity,
+           CASE WHEN bc.bundle_item_id IS NOT NULL THEN ci.item_id END AS bundle_item_id
+    FROM cart_item ci
+    LEFT JOIN bundle_component bc ON bc.bundle_item_id = ci.item_id`
+
+	if _, err := tx.Exec(viewQuery); err != nil {
+		return fmt.Errorf("error creating cart_pick_item view: %w", err)
+	}
+	return nil
+}
+
+// stockLines replaces bundles in cartItems by their components, merges lines for the same item and
+// orders them by item id, the order rows are locked in. touched lists every item whose listed stock
+// changes with these lines, including other bundles sharing the components.
+func stockLines(q queryer, cartItems []*types.Checkout_Cart_Item) ([]*types.Checkout_Cart_Item, []int, error) {
+	ids := make([]int, len(cartItems))
+	for i, c := range cartItems {
+		ids[i] = c.Item_Id
+	}
+
+	components := map[int][]*types.Checkout_Cart_Item{}
+	rows, err := q.Query(`SELECT bundle_item_id, component_item_id, quantity FROM bundle_component WHERE bundle_item_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying bundle components: %w", err)
+	}
+	for rows.Next() {
+		var bundleID int
+		component := &types.Checkout_Cart_Item{}
+		if err := rows.Scan(&bundleID, &component.Item_Id, &component.Quantity); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("error scanning bundle component: %w", err)
+		}
+		components[bundleID] = append(components[bundleID], component)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	quantities := map[int]int{}
+	for _, c := range cartItems {
+		parts, ok := components[c.Item_Id]
+		if !ok {
+			quantities[c.Item_Id] += c.Quantity
+			continue
+		}
+		for _, part := range parts {
+			quantities[part.Item_Id] += c.Quantity * part.Quantity
+		}
+	}
+
+	lines := make([]*types.Checkout_Cart_Item, 0, len(quantities))
+	stockIDs := make([]int, 0, len(quantities))
+	for id, quantity := range quantities {
+		lines = append(lines, &types.Checkout_Cart_Item{Item_Id: id, Quantity: quantity})
+		stockIDs = append(stockIDs, id)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Item_Id < lines[j].Item_Id })
+
+	touched := append(ids, stockIDs...)
+	rows, err = q.Query(`SELECT DISTINCT bundle_item_id FROM bundle_component WHERE component_item_id = ANY($1)`, pq.Array(stockIDs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying bundles of components: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, nil, fmt.Errorf("error scanning bundle: %w", err)
+		}
+		touched = append(touched, id)
+	}
+	return lines, touched, rows.Err()
+}
+
+// lockStockRows locks the item_store rows of itemIDs in item id order. Changing a component also
+// updates the bundles holding it, so checkouts lock everything they touch up front to avoid deadlocks.
+func lockStockRows(tx *sql.Tx, itemIDs []int) error {
+	if _, err := tx.Exec(`SELECT id FROM item_store WHERE item_id = ANY($1) ORDER BY item_id, id FOR UPDATE`, pq.Array(itemIDs)); err != nil {
+		return fmt.Errorf("error locking stock: %w", err)
+	}
+	return nil
+}
+
+// SetBundle makes an item a bundle of the given components, replacing any previous ones.
+// Bundles cannot be nested.
+func (s *PostgresStore) SetBundle(req types.SetBundle) (*types.Bundle, error) {
+	if len(req.Components) == 0 {
+		return nil, fmt.Errorf("a bundle needs at least one component")
+	}
+	ids := make([]int, 0, len(req.Components))
+	quantities := make([]int, 0, len(req.Components))
+	seen := map[int]bool{}
+	for _, c := range req.Components {
+		if c.ItemID == req.BundleItemID {
+			return nil, fmt.Errorf("item %d cannot be a component of itself", c.ItemID)
+		}
+		if seen[c.ItemID] {
+			return nil, fmt.Errorf("component %d is listed more than once", c.ItemID)
+		}
+		if c.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity of component %d must be positive", c.ItemID)
+		}
+		seen[c.ItemID] = true
+		ids = append(ids, c.ItemID)
+		quantities = append(quantities, c.Quantity)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialized, so two concurrent edits cannot nest bundles into each other
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('bundle_component'))`); err != nil {
+		return nil, fmt.Errorf("error locking bundles: %w", err)
+	}
+
+	var found int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM item WHERE id = ANY($1)`, pq.Array(append(ids, req.BundleItemID))).Scan(&found); err != nil {
+		return nil, fmt.Errorf("error checking items of bundle %d: %w", req.BundleItemID, err)
+	}
+	if found != len(ids)+1 {
+		return nil, fmt.Errorf("bundle %d or some of its components do not exist", req.BundleItemID)
+	}
+
+	var nested bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM bundle_component WHERE component_item_id = $1)`, req.BundleItemID).Scan(&nested); err != nil {
+		return nil, fmt.Errorf("error checking item %d: %w", req.BundleItemID, err)
+	}
+	if nested {
+		return nil, fmt.Errorf("item %d is a component of another bundle and cannot be a bundle itself", req.BundleItemID)
+	}
+
+	var bundleID sql.NullInt64
+	if err := tx.QueryRow(`SELECT MIN(bundle_item_id) FROM bundle_component WHERE bundle_item_id = ANY($1)`, pq.Array(ids)).Scan(&bundleID); err != nil {
+		return nil, fmt.Errorf("error checking components of bundle %d: %w", req.BundleItemID, err)
+	}
+	if bundleID.Valid {
+		return nil, fmt.Errorf("item %d is a bundle and cannot be a component", bundleID.Int64)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM bundle_component WHERE bundle_item_id = $1`, req.BundleItemID); err != nil {
+		return nil, fmt.Errorf("error clearing components of bundle %d: %w", req.BundleItemID, err)
+	}
+
+	insertQuery := `
+    INSERT INTO bundle_component (bundle_item_id, component_item_id, quantity)
+    SELECT $1, c.item_id, c.quantity
+    FROM unnest($2::int[], $3::int[]) AS c(item_id, quantity)`
+
+	if _, err := tx.Exec(insertQuery, req.BundleItemID, pq.Array(ids), pq.Array(quantities)); err != nil {
+		return nil, fmt.Errorf("error saving components of bundle %d: %w", req.BundleItemID, err)
+	}
+
+	// Recompute the bundle's stock in every store from the new components
+	if _, err := tx.Exec(`UPDATE item_store SET stock_quantity = stock_quantity WHERE item_id = $1`, req.BundleItemID); err != nil {
+		return nil, fmt.Errorf("error updating stock of bundle %d: %w", req.BundleItemID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateItems(0, req.BundleItemID)
+	return s.GetBundle(req.BundleItemID)
+}
+
+// RemoveBundle turns a bundle back into a plain item. Its stock is cleared, since it held none of its own.
+func (s *PostgresStore) RemoveBundle(bundleItemID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM bundle_component WHERE bundle_item_id = $1`, bundleItemID)
+	if err != nil {
+		return fmt.Errorf("error removing components of bundle %d: %w", bundleItemID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("item %d is not a bundle", bundleItemID)
+	}
+	if _, err := tx.Exec(`UPDATE item_store SET stock_quantity = 0 WHERE item_id = $1`, bundleItemID); err != nil {
+		return fmt.Errorf("error clearing stock of item %d: %w", bundleItemID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.catalog.invalidateItems(0, bundleItemID)
+	return nil
+}
+
+func (s *PostgresStore) GetBundle(bundleItemID int) (*types.Bundle, error) {
+	bundle := &types.Bundle{BundleItemID: bundleItemID}
+	err := s.db.QueryRow(`SELECT name FROM item WHERE id = $1`, bundleItemID).Scan(&bundle.Name)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item with id = [%d] not found", bundleItemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying item %d: %w", bundleItemID, err)
+	}
+
+	components, err := bundleComponents(s.db, bundleItemID)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Components = components
+	return bundle, nil
+}
+
+func bundleComponents(q queryer, bundleItemID int) ([]types.BundleComponent, error) {
+	query := `
+    SELECT i.id, i.name, bc.quantity, i.quantity, i.unit_of_quantity,
+           COALESCE((SELECT image_url FROM item_image WHERE item_id = i.id ORDER BY order_position LIMIT 1), '')
+    FROM bundle_component bc
+    JOIN item i ON i.id = bc.component_item_id
+    WHERE bc.bundle_item_id = $1
+    ORDER BY i.name`
+
+	rows, err := q.Query(query, bundleItemID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying components of bundle %d: %w", bundleItemID, err)
+	}
+	defer rows.Close()
+
+	components := []types.BundleComponent{}
+	for rows.Next() {
+		var c types.BundleComponent
+		if err := rows.Scan(&c.ItemID, &c.Name, &c.Quantity, &c.Size, &c.Unit_Of_Quantity, &c.Image); err != nil {
+			return nil, fmt.Errorf("error scanning bundle component: %w", err)
+		}
+		components = append(components, c)
+	}
+	return components, rows.Err()
+}
@@ -3,6 +3,8 @@ package store
 import (
 	"database/sql"
 	"fmt"
+
+	"github.com/girithc/pronto-go/types"
 )
 
 func (s *PostgresStore) Cancel_Checkout(cart_id int, sign string, merchantTransactionID string, lockType string) error {
@@ -50,6 +52,12 @@ func (s *PostgresStore) Cancel_Checkout(cart_id int, sign string, merchantTransa
 		}
 
 		fmt.Println("Completed DeleteTransaction")
+
+		event := types.CheckoutCancelledEvent{Event: CheckoutEventCancelled, CartID: cart_id, MerchantTransactionID: merchantTransactionID}
+		if err = writeOutboxEvent(tx, CheckoutEventCancelled, cart_id, event); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
 	// Commit the transaction
@@ -57,36 +65,29 @@ func (s *PostgresStore) Cancel_Checkout(cart_id int, sign string, merchantTransa
 	if err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+	if cartUnlock {
+		s.invalidateCartStock(cart_id)
+	}
 
 	fmt.Println("Exiting Cancel_Checkout")
 	return nil
 }
 
+// ResetLockedQuantities returns the cart's locked quantities to stock. Bundles release their components.
+// The caller invalidates the cart's listings with invalidateCartStock once tx commits.
 func (s *PostgresStore) ResetLockedQuantities(tx *sql.Tx, cart_id int) error {
-	type ItemUpdate struct {
-		ItemID   int
-		Quantity int
-	}
-
 	// First, retrieve the item_id and quantity from cart_item for the given cart_id
-	var updates []ItemUpdate
-	query := `SELECT item_id, quantity FROM cart_item WHERE cart_id = $1`
-	rows, err := s.db.Query(query, cart_id)
+	cartItems, err := s.getCartItems(cart_id)
 	if err != nil {
 		return fmt.Errorf("error querying cart_item table: %w", err)
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var update ItemUpdate
-		if err := rows.Scan(&update.ItemID, &update.Quantity); err != nil {
-			return fmt.Errorf("error scanning cart_item row: %w", err)
-		}
-		updates = append(updates, update)
+	updates, touched, err := stockLines(tx, cartItems)
+	if err != nil {
+		return err
 	}
-
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating over cart_item rows: %w", err)
+	if err := lockStockRows(tx, touched); err != nil {
+		return err
 	}
 
 	// Now, batch update item_store for each item
@@ -97,10 +98,9 @@ func (s *PostgresStore) ResetLockedQuantities(tx *sql.Tx, cart_id int) error {
         locked_quantity = locked_quantity - $1
     WHERE item_id = $2 AND locked_quantity >= $1
 `
-		if _, err := tx.Exec(updateQuery, update.Quantity, update.ItemID); err != nil {
-			return fmt.Errorf("error updating item_store table for item_id %d: %w", update.ItemID, err)
+		if _, err := tx.Exec(updateQuery, update.Quantity, update.Item_Id); err != nil {
+			return fmt.Errorf("error updating item_store table for item_id %d: %w", update.Item_Id, err)
 		}
-
 	}
 
 	return nil
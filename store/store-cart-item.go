@@ -157,7 +157,16 @@ func (s *PostgresStore) Add_Cart_Item(cartId int, itemId int, quantity int) (*ty
 
 	// Check if item exists in the item_store
 	var stockQuantity int
-	if err := tx.QueryRow("SELECT stock_quantity FROM item_store WHERE item_id=$1", itemId).Scan(&stockQuantity); err != nil {
+	err = tx.QueryRow(`
+        SELECT istore.stock_quantity
+        FROM item_store istore
+        JOIN shopping_cart sc ON sc.store_id = istore.store_id
+        JOIN item i ON i.id = istore.item_id
+        WHERE istore.item_id = $1 AND sc.id = $2 AND istore.listed AND i.status = 'active'`, itemId, cartId).Scan(&stockQuantity)
+	if err == sql.ErrNoRows && quantity > 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("item id %d is not available at the cart's store", itemId)
+	} else if err != nil && err != sql.ErrNoRows {
 		tx.Rollback()
 		return nil, fmt.Errorf("error checking item stock for item_id %d: %w", itemId, err)
 	}
@@ -191,7 +200,7 @@ func (s *PostgresStore) Add_Cart_Item(cartId int, itemId int, quantity int) (*ty
 			cartItemId += 1
 
 			// Calculate sold price by subtracting discount from MRP price
-			_, err = tx.Exec("INSERT INTO cart_item (id, cart_id, item_id, quantity, sold_price) VALUES ($4, $1, $2, $3, (SELECT (ifin.mrp_price - istore.discount) FROM item_financial ifin JOIN item_store istore ON ifin.item_id = istore.item_id WHERE ifin.item_id=$2))", cartId, itemId, quantity, cartItemId)
+			_, err = tx.Exec("INSERT INTO cart_item (id, cart_id, item_id, quantity, sold_price) VALUES ($4, $1, $2, $3, "+cartItemPriceSQL("$1", "$2")+")", cartId, itemId, quantity, cartItemId)
 			if err != nil {
 				tx.Rollback()
 				return nil, fmt.Errorf("error inserting cart item for cart_id %d: %w", cartId, err)
@@ -210,11 +219,11 @@ func (s *PostgresStore) Add_Cart_Item(cartId int, itemId int, quantity int) (*ty
 			if stockQuantity <= 0 {
 				_, err = tx.Exec("DELETE FROM cart_item WHERE cart_id=$1 AND item_id=$2", cartId, itemId)
 			} else {
-				_, err = tx.Exec("UPDATE cart_item SET quantity=$1, sold_price=(SELECT (ifin.mrp_price - istore.discount) FROM item_financial ifin JOIN item_store istore ON ifin.item_id = istore.item_id WHERE ifin.item_id=$3) WHERE cart_id=$2 AND item_id=$3", stockQuantity, cartId, itemId)
+				_, err = tx.Exec("UPDATE cart_item SET quantity=$1, sold_price="+cartItemPriceSQL("$2", "$3")+" WHERE cart_id=$2 AND item_id=$3", stockQuantity, cartId, itemId)
 				finalQuantity = stockQuantity
 			}
 		} else {
-			_, err = tx.Exec("UPDATE cart_item SET quantity=$1, sold_price=(SELECT (ifin.mrp_price - istore.discount) FROM item_financial ifin JOIN item_store istore ON ifin.item_id = istore.item_id WHERE ifin.item_id=$3) WHERE cart_id=$2 AND item_id=$3", newTotalQuantity, cartId, itemId)
+			_, err = tx.Exec("UPDATE cart_item SET quantity=$1, sold_price="+cartItemPriceSQL("$2", "$3")+" WHERE cart_id=$2 AND item_id=$3", newTotalQuantity, cartId, itemId)
 			finalQuantity = newTotalQuantity
 		}
 		if err != nil {
@@ -226,6 +235,14 @@ func (s *PostgresStore) Add_Cart_Item(cartId int, itemId int, quantity int) (*ty
 		return nil, fmt.Errorf("error checking cart item quantity for cart_id %d: %w", cartId, err)
 	}
 
+	// Purchase limits and restrictions only stop the cart from growing
+	if quantity > 0 {
+		if err := checkPurchaseLimits(tx, cartId, itemId); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("error committing transaction for cart_id %d: %w", cartId, err)
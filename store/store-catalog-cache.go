@@ -0,0 +1,293 @@
+package store
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// catalogCacheTTL bounds how stale an entry can get when another instance writes to the database.
+const catalogCacheTTL = 2 * time.Minute
+
+const (
+	catalogKeyCategories         = "categories:"
+	catalogKeyHigherLevel        = "higher-level-categories"
+	catalogKeyItemsByCatAndStore = "items:"
+	// Home screens list items, so they sit under the item listings and item edits reach them.
+	catalogKeyHome = catalogKeyItemsByCatAndStore + "home:"
+)
+
+type catalogEntry struct {
+	value   interface{}
+	etag    string
+	storeID int
+	itemIDs map[int]bool
+	expires time.Time
+}
+
+// catalogCache holds the home-screen catalog reads. Writers invalidate the entries they touch.
+// gen counts invalidations, so a read that overlapped one is not cached.
+type catalogCache struct {
+	mu      sync.RWMutex
+	entries map[string]*catalogEntry
+	gen     uint64
+	router  *readRouter
+}
+
+func newCatalogCache(router *readRouter) *catalogCache {
+	return &catalogCache{entries: make(map[string]*catalogEntry), router: router}
+}
+
+func (c *catalogCache) get(key string) (*catalogEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// generation is taken before the database read whose result is later passed to put.
+func (c *catalogCache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.gen
+}
+
+// put caches entry under key, unless an invalidation ran since gen was taken: the read may have
+// seen the data from before that write.
+func (c *catalogCache) put(key string, gen uint64, entry *catalogEntry) {
+	if c == nil {
+		return
+	}
+	entry.expires = time.Now().Add(catalogCacheTTL)
+	c.mu.Lock()
+	if c.gen == gen {
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+}
+
+// markEdit pins catalog reads, and the exports and reports built from the catalog, to the primary
+// for the read-your-writes window. Writers invalidate once their transaction has committed, so the
+// window starts when the edit is visible.
+func (c *catalogCache) markEdit() {
+	c.router.markWrite(readCatalog, readExport)
+}
+
+// invalidateCategories drops every entry, since category names and images are embedded in item listings too.
+func (c *catalogCache) invalidateCategories() {
+	if c == nil {
+		return
+	}
+	c.markEdit()
+	c.mu.Lock()
+	c.gen++
+	c.entries = make(map[string]*catalogEntry)
+	c.mu.Unlock()
+}
+
+// invalidate drops the entry under key.
+func (c *catalogCache) invalidate(key string) {
+	if c == nil {
+		return
+	}
+	c.markEdit()
+	c.mu.Lock()
+	c.gen++
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// invalidatePrefix drops every entry whose key starts with prefix.
+func (c *catalogCache) invalidatePrefix(prefix string) {
+	if c == nil {
+		return
+	}
+	c.markEdit()
+	c.mu.Lock()
+	c.gen++
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// invalidateAllItems drops every item listing, used when an edit can change which items a listing contains.
+func (c *catalogCache) invalidateAllItems() {
+	if c == nil {
+		return
+	}
+	c.markEdit()
+	c.mu.Lock()
+	c.gen++
+	for key := range c.entries {
+		if strings.HasPrefix(key, catalogKeyItemsByCatAndStore) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// invalidateItems drops the item listings of storeID that contain any of itemIDs after an edit to
+// the items. A storeID of 0 matches every store.
+func (c *catalogCache) invalidateItems(storeID int, itemIDs ...int) {
+	if c == nil || len(itemIDs) == 0 {
+		return
+	}
+	c.markEdit()
+	c.dropItems(storeID, itemIDs)
+}
+
+// invalidateStock drops the item listings of storeID that contain any of itemIDs after their stock
+// moved. Catalog reads go to the primary for the read-your-writes window, so the next listing does
+// not re-cache the stock a lagging replica still holds.
+func (c *catalogCache) invalidateStock(storeID int, itemIDs ...int) {
+	if c == nil || len(itemIDs) == 0 {
+		return
+	}
+	c.markEdit()
+	c.dropItems(storeID, itemIDs)
+}
+
+func (c *catalogCache) dropItems(storeID int, itemIDs []int) {
+	c.mu.Lock()
+	c.gen++
+	for key, entry := range c.entries {
+		if !strings.HasPrefix(key, catalogKeyItemsByCatAndStore) {
+			continue
+		}
+		if storeID != 0 && entry.storeID != storeID {
+			continue
+		}
+		for _, id := range itemIDs {
+			if entry.itemIDs[id] {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+	c.mu.Unlock()
+}
+
+func catalogETag(v interface{}) (string, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("error encoding catalog entry: %w", err)
+	}
+	sum := sha1.Sum(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`, nil
+}
+
+// CachedCategories returns Get_Categories in langs through the catalog cache along with its ETag.
+func (s *PostgresStore) CachedCategories(promotion bool, storeID int, langs []string) ([]*types.Category, string, error) {
+	key := fmt.Sprintf("%sstore=%d:promotion=%t", catalogKeyCategories, storeID, promotion) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.([]*types.Category), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	categories, err := s.Get_Categories(promotion, storeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.translateCategories(langs, categories); err != nil {
+		return nil, "", err
+	}
+	etag, err := catalogETag(categories)
+	if err != nil {
+		return nil, "", err
+	}
+	s.catalog.put(key, gen, &catalogEntry{value: categories, etag: etag})
+	return categories, etag, nil
+}
+
+// CachedHigherLevelCategories returns Get_Higher_Level_Categories in langs through the catalog cache along with its ETag.
+func (s *PostgresStore) CachedHigherLevelCategories(storeID int, langs []string) ([]*types.Higher_Level_Category, string, error) {
+	key := fmt.Sprintf("%s:store=%d", catalogKeyHigherLevel, storeID) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.([]*types.Higher_Level_Category), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	categories, err := s.Get_Higher_Level_Categories(storeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.translateHigherLevelCategories(langs, categories); err != nil {
+		return nil, "", err
+	}
+	etag, err := catalogETag(categories)
+	if err != nil {
+		return nil, "", err
+	}
+	s.catalog.put(key, gen, &catalogEntry{value: categories, etag: etag})
+	return categories, etag, nil
+}
+
+// CachedCategoryTree returns GetCategoryTree in langs through the catalog cache along with its ETag.
+func (s *PostgresStore) CachedCategoryTree(storeID int, rootID int, langs []string) ([]*types.CategoryNode, string, error) {
+	key := fmt.Sprintf("%stree:store=%d:root=%d", catalogKeyCategories, storeID, rootID) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.([]*types.CategoryNode), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	tree, err := s.GetCategoryTree(storeID, rootID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.translateCategoryTree(langs, tree); err != nil {
+		return nil, "", err
+	}
+	etag, err := catalogETag(tree)
+	if err != nil {
+		return nil, "", err
+	}
+	s.catalog.put(key, gen, &catalogEntry{value: tree, etag: etag})
+	return tree, etag, nil
+}
+
+// CachedItemsByCategoryAndStore returns Get_Items_By_CategoryID_And_StoreID in langs through the catalog cache along with its ETag.
+func (s *PostgresStore) CachedItemsByCategoryAndStore(category_id int, store_id int, langs []string) ([]*types.Get_Items_By_CategoryID_And_StoreID, string, error) {
+	key := fmt.Sprintf("%sstore=%d:category=%d", catalogKeyItemsByCatAndStore, store_id, category_id) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.([]*types.Get_Items_By_CategoryID_And_StoreID), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	items, err := s.Get_Items_By_CategoryID_And_StoreID(category_id, store_id)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.translateItemListing(langs, items); err != nil {
+		return nil, "", err
+	}
+	etag, err := catalogETag(items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	itemIDs := make(map[int]bool, len(items))
+	for _, item := range items {
+		itemIDs[item.ID] = true
+	}
+	s.catalog.put(key, gen, &catalogEntry{value: items, etag: etag, storeID: store_id, itemIDs: itemIDs})
+	return items, etag, nil
+}
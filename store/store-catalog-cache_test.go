@@ -0,0 +1,28 @@
+package store
+
+import "testing"
+
+func TestCatalogCachePutAfterInvalidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(c *catalogCache)
+		cached     bool
+	}{
+		{"no write", func(c *catalogCache) {}, true},
+		{"categories", func(c *catalogCache) { c.invalidateCategories() }, false},
+		{"key", func(c *catalogCache) { c.invalidate("other") }, false},
+		{"prefix", func(c *catalogCache) { c.invalidatePrefix(catalogKeyCategories) }, false},
+		{"all items", func(c *catalogCache) { c.invalidateAllItems() }, false},
+		{"items", func(c *catalogCache) { c.invalidateItems(1, 7) }, false},
+		{"stock", func(c *catalogCache) { c.invalidateStock(1, 7) }, false},
+	}
+	for _, tt := range tests {
+		c := newCatalogCache(nil)
+		gen := c.generation()
+		tt.invalidate(c)
+		c.put("key", gen, &catalogEntry{value: 1})
+		if _, ok := c.get("key"); ok != tt.cached {
+			t.Errorf("%s: cached = %t, want %t", tt.name, ok, tt.cached)
+		}
+	}
+}
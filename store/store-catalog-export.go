@@ -0,0 +1,128 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+var catalogExportHeader = []string{
+	"item_id", "name", "brand", "categories", "barcodes", "quantity", "unit_of_quantity",
+	"mrp_price", "store_price", "discount", "buy_price", "margin_net", "gst", "cess",
+	"stock_quantity", "locked_quantity", "shelves",
+}
+
+// ExportStoreCatalog streams every item stocked by storeID to w as CSV or JSON Lines.
+// Rows are written as they are read so large catalogs are never held in memory.
+func (s *PostgresStore) ExportStoreCatalog(storeID int, format string, w io.Writer) error {
+	if format != types.CatalogExportCSV && format != types.CatalogExportJSONL {
+		return fmt.Errorf("unsupported export format %q, use csv or jsonl", format)
+	}
+
+	query := `
+    SELECT i.id, i.name, COALESCE(b.name, ''),
+        ARRAY(SELECT c.name FROM item_category ic JOIN category c ON c.id = ic.category_id
+              WHERE ic.item_id = i.id ORDER BY c.name),
+        ARRAY(SELECT ib.barcode FROM item_barcode ib
+              WHERE ib.item_id = i.id ORDER BY ib.is_primary DESC, ib.created_at, ib.id),
+        i.quantity, COALESCE(i.unit_of_quantity::text, ''),
+        f.mrp_price, COALESCE(store_selling_price(f.mrp_price, ist.discount, ist.price_override), ist.store_price),
+        ist.discount, f.buy_price, f.margin_net,
+        t.gst / 100.0, t.cess / 100.0,
+        ist.stock_quantity, COALESCE(ist.locked_quantity, 0),
+        ARRAY(SELECT sh.horizontal || sh.vertical FROM shelf sh
+              WHERE sh.item_id = i.id AND sh.store_id = ist.store_id ORDER BY sh.horizontal, sh.vertical)
+    FROM item_store ist
+    JOIN item i ON i.id = ist.item_id
+    LEFT JOIN brand b ON b.id = i.brand_id
+    LEFT JOIN item_financial f ON f.item_id = i.id
+    LEFT JOIN tax t ON t.id = f.tax_id
+    WHERE ist.store_id = $1
+    ORDER BY i.id`
+
+	rows, err := s.reader(readExport).Query(query, storeID)
+	if err != nil {
+		return fmt.Errorf("error querying store catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	if format == types.CatalogExportCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(catalogExportHeader); err != nil {
+			return err
+		}
+	} else {
+		jsonEncoder = json.NewEncoder(w)
+	}
+
+	for rows.Next() {
+		var row types.CatalogExportRow
+		var mrp, buy, margin, gst, cess sql.NullFloat64
+		err := rows.Scan(&row.ItemID, &row.Name, &row.Brand, pq.Array(&row.Categories),
+			pq.Array(&row.Barcodes), &row.Quantity, &row.UnitOfQuantity,
+			&mrp, &row.StorePrice, &row.Discount, &buy, &margin, &gst, &cess,
+			&row.StockQuantity, &row.LockedQuantity, pq.Array(&row.Shelves))
+		if err != nil {
+			return fmt.Errorf("error scanning store catalog row: %w", err)
+		}
+		row.MRPPrice, row.BuyPrice, row.MarginNet = nullFloat(mrp), nullFloat(buy), nullFloat(margin)
+		row.GST, row.Cess = nullFloat(gst), nullFloat(cess)
+
+		if jsonEncoder != nil {
+			if err := jsonEncoder.Encode(row); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := csvWriter.Write(catalogExportRecord(row)); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating store catalog rows: %w", err)
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	return nil
+}
+
+func catalogExportRecord(row types.CatalogExportRow) []string {
+	return []string{
+		strconv.Itoa(row.ItemID), row.Name, row.Brand, strings.Join(row.Categories, "|"), strings.Join(row.Barcodes, "|"),
+		strconv.Itoa(row.Quantity), row.UnitOfQuantity,
+		formatNullFloat(row.MRPPrice), formatFloat(row.StorePrice), formatFloat(row.Discount),
+		formatNullFloat(row.BuyPrice), formatNullFloat(row.MarginNet),
+		formatNullFloat(row.GST), formatNullFloat(row.Cess),
+		strconv.Itoa(row.StockQuantity), strconv.Itoa(row.LockedQuantity), strings.Join(row.Shelves, "|"),
+	}
+}
+
+func nullFloat(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatNullFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
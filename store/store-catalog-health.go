@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// catalogHealthDefaultLimit caps the items listed under each issue when the request sets no limit.
+const catalogHealthDefaultLimit = 100
+
+const catalogHealthQuery = `
+    SELECT i.id, i.name, i.status,
+           ifin.item_id IS NOT NULL, ifin.mrp_price, ifin.buy_price, ifin.margin_net, ` + itemHasTaxClause + `,
+           EXISTS (SELECT 1 FROM item_image ii WHERE ii.item_id = i.id),
+           COALESCE(i.barcode, '') <> '' OR EXISTS (SELECT 1 FROM item_barcode ib WHERE ib.item_id = i.id),
+           ARRAY(
+               SELECT istore.store_id FROM item_store istore
+               WHERE istore.item_id = i.id AND istore.listed AND ($1 = 0 OR istore.store_id = $1)
+                 AND NOT EXISTS (SELECT 1 FROM shelf sh WHERE sh.item_id = i.id AND sh.store_id = istore.store_id)
+                 AND NOT EXISTS (SELECT 1 FROM bundle_component bc WHERE bc.bundle_item_id = i.id)
+               ORDER BY istore.store_id
+           )
+    FROM item i
+    LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+    WHERE ($1 = 0 OR EXISTS (SELECT 1 FROM item_store fs WHERE fs.item_id = i.id AND fs.store_id = $1))
+      AND ($2 OR i.status <> 'discontinued')
+    ORDER BY i.id`
+
+// CatalogHealthReport lists the items with missing or inconsistent catalog data, grouped by issue:
+// no financials, MRP, buy price or tax; no image or barcode; listed at a store without a shelf
+// there; and a margin at or below zero or an MRP below the buy price. Each issue is listed, with a
+// zero count when no item has it.
+func (s *PostgresStore) CatalogHealthReport(req types.CatalogHealthRequest) (*types.CatalogHealthReport, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = catalogHealthDefaultLimit
+	}
+
+	rows, err := s.reader(readExport).Query(catalogHealthQuery, req.StoreID, req.IncludeDiscontinued)
+	if err != nil {
+		return nil, fmt.Errorf("error querying catalog health: %w", err)
+	}
+	defer rows.Close()
+
+	report := &types.CatalogHealthReport{
+		StoreID:     req.StoreID,
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Issues:      make([]types.CatalogHealthIssue, len(types.CatalogIssues)),
+	}
+	index := make(map[string]int, len(types.CatalogIssues))
+	for i, issue := range types.CatalogIssues {
+		report.Issues[i] = types.CatalogHealthIssue{Issue: issue, Items: []types.CatalogHealthItem{}}
+		index[issue] = i
+	}
+
+	for rows.Next() {
+		var item types.CatalogHealthItem
+		var hasFinancials, hasTax, hasImage, hasBarcode bool
+		var mrp, buy, margin sql.NullFloat64
+		var unshelved pq.Int64Array
+		if err := rows.Scan(&item.ItemID, &item.Name, &item.Status, &hasFinancials, &mrp, &buy, &margin, &hasTax,
+			&hasImage, &hasBarcode, &unshelved); err != nil {
+			return nil, fmt.Errorf("error scanning catalog health: %w", err)
+		}
+		report.ItemsChecked++
+
+		flagged := false
+		flag := func(issue, detail string) {
+			flagged = true
+			entry := &report.Issues[index[issue]]
+			entry.Count++
+			if len(entry.Items) < limit {
+				found := item
+				found.Detail = detail
+				entry.Items = append(entry.Items, found)
+			}
+		}
+
+		if !hasFinancials {
+			flag(types.CatalogIssueMissingFinancials, "")
+		} else {
+			if !mrp.Valid || mrp.Float64 <= 0 {
+				flag(types.CatalogIssueMissingMRP, "")
+			}
+			if !buy.Valid || buy.Float64 <= 0 {
+				flag(types.CatalogIssueMissingBuyPrice, "")
+			}
+			if !hasTax {
+				flag(types.CatalogIssueMissingTax, "")
+			}
+			if margin.Valid && margin.Float64 <= 0 {
+				flag(types.CatalogIssueNonPositiveMargin, fmt.Sprintf("margin %.2f%%", margin.Float64))
+			}
+			if mrp.Valid && buy.Valid && mrp.Float64 < buy.Float64 {
+				flag(types.CatalogIssueMRPBelowBuyPrice, fmt.Sprintf("mrp %.2f, buy price %.2f", mrp.Float64, buy.Float64))
+			}
+		}
+		if !hasImage {
+			flag(types.CatalogIssueMissingImage, "")
+		}
+		if !hasBarcode {
+			flag(types.CatalogIssueMissingBarcode, "")
+		}
+		if len(unshelved) > 0 {
+			flag(types.CatalogIssueMissingShelf, fmt.Sprintf("stores %v", []int64(unshelved)))
+		}
+
+		if flagged {
+			report.ItemsWithIssues++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
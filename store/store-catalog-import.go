@@ -0,0 +1,482 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+const catalogImportMaxRows = 5000
+
+// catalogImportMaxMRP is the first value item_financial.mrp_price cannot hold.
+const catalogImportMaxMRP = 1e8
+
+var catalogImportRequired = []string{"name", "brand", "categories", "quantity", "unit", "mrp"}
+
+var catalogImportUnits = map[string]bool{"g": true, "mg": true, "ml": true, "l": true, "kg": true, "ct": true, "pcs": true}
+
+type catalogImportStock struct {
+	storeID  int
+	quantity int
+}
+
+type catalogImportRow struct {
+	line        int
+	name        string
+	brandID     int
+	categoryIDs []int
+	quantity    int
+	unit        string
+	barcode     string
+	mrp         float64
+	buyPrice    *float64
+	margin      *float64
+	tax         *itemTax
+	taxID       int
+	images      []string
+	stock       []catalogImportStock
+	description string
+}
+
+// catalogLookups resolves names in the CSV to ids. Keys are lowercased.
+type catalogLookups struct {
+	brands     map[string]int
+	categories map[string]int
+	stores     map[string]int
+	storeIDs   map[int]bool
+	taxes      map[[2]int]int
+}
+
+// ImportCatalogCSV validates every row before writing anything. With dryRun, or when any row
+// is invalid, it only returns the report; otherwise all rows are inserted in one transaction, as
+// draft items.
+func (s *PostgresStore) ImportCatalogCSV(r io.Reader, dryRun bool) (*types.CatalogImportReport, error) {
+	report := &types.CatalogImportReport{DryRun: dryRun, Errors: []types.CatalogImportError{}}
+
+	lookups, err := s.loadCatalogLookups()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := parseCatalogCSV(r, lookups, report)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCatalogDuplicates(rows, report); err != nil {
+		return nil, err
+	}
+
+	failed := map[int]bool{}
+	for _, e := range report.Errors {
+		failed[e.Row] = true
+	}
+	report.ValidRows = report.Rows - len(failed)
+
+	if dryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	itemIDs, rowErr := s.insertCatalogRows(rows)
+	if rowErr != nil {
+		report.Errors = append(report.Errors, *rowErr)
+		report.ValidRows = 0
+		return report, nil
+	}
+
+	s.catalog.invalidateAllItems()
+	report.Committed = true
+	report.ItemIDs = itemIDs
+	return report, nil
+}
+
+func (s *PostgresStore) loadCatalogLookups() (*catalogLookups, error) {
+	l := &catalogLookups{
+		brands:     map[string]int{},
+		categories: map[string]int{},
+		stores:     map[string]int{},
+		storeIDs:   map[int]bool{},
+		taxes:      map[[2]int]int{},
+	}
+
+	named := []struct {
+		query string
+		dest  map[string]int
+	}{
+		{`SELECT id, LOWER(name) FROM brand`, l.brands},
+		{`SELECT id, LOWER(name) FROM category`, l.categories},
+		{`SELECT id, LOWER(name) FROM store`, l.stores},
+	}
+	for _, n := range named {
+		rows, err := s.db.Query(n.query)
+		if err != nil {
+			return nil, fmt.Errorf("error loading catalog lookups: %w", err)
+		}
+		for rows.Next() {
+			var id int
+			var name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("error scanning catalog lookup: %w", err)
+			}
+			n.dest[name] = id
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating catalog lookups: %w", err)
+		}
+	}
+	for _, id := range l.stores {
+		l.storeIDs[id] = true
+	}
+
+	rows, err := s.db.Query(`SELECT id, gst, cess FROM tax`)
+	if err != nil {
+		return nil, fmt.Errorf("error loading tax slabs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, gst, cess int
+		if err := rows.Scan(&id, &gst, &cess); err != nil {
+			return nil, fmt.Errorf("error scanning tax slab: %w", err)
+		}
+		l.taxes[[2]int{gst, cess}] = id
+	}
+
+	return l, rows.Err()
+}
+
+func parseCatalogCSV(r io.Reader, lookups *catalogLookups, report *types.CatalogImportReport) ([]*catalogImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range catalogImportRequired {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("csv is missing required column %q", c)
+		}
+	}
+
+	var rows []*catalogImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				report.Rows++
+				report.Errors = append(report.Errors, types.CatalogImportError{Row: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("error reading csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		report.Rows++
+		if report.Rows > catalogImportMaxRows {
+			return nil, fmt.Errorf("csv has more than %d rows", catalogImportMaxRows)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		fail := func(column, format string, args ...interface{}) {
+			report.Errors = append(report.Errors, types.CatalogImportError{Row: line, Column: column, Message: fmt.Sprintf(format, args...)})
+		}
+
+		row := &catalogImportRow{line: line, description: field("description")}
+
+		row.name = field("name")
+		if row.name == "" {
+			fail("name", "name is required")
+		} else if len(row.name) > 100 {
+			fail("name", "name is longer than 100 characters")
+		}
+		if row.description == "" {
+			row.description = row.name
+		}
+
+		if brand := field("brand"); brand == "" {
+			fail("brand", "brand is required")
+		} else if id, ok := lookups.brands[strings.ToLower(brand)]; ok {
+			row.brandID = id
+		} else {
+			fail("brand", "unknown brand %q", brand)
+		}
+
+		for _, name := range splitList(field("categories"), "|") {
+			if id, ok := lookups.categories[strings.ToLower(name)]; ok {
+				row.categoryIDs = append(row.categoryIDs, id)
+			} else {
+				fail("categories", "unknown category %q", name)
+			}
+		}
+		if field("categories") == "" {
+			fail("categories", "at least one category is required")
+		}
+
+		if q, err := strconv.Atoi(field("quantity")); err != nil || q <= 0 {
+			fail("quantity", "quantity must be a positive whole number")
+		} else {
+			row.quantity = q
+		}
+
+		row.unit = strings.ToLower(field("unit"))
+		if !catalogImportUnits[row.unit] {
+			fail("unit", "unit must be one of g, mg, ml, l, kg, ct, pcs")
+		}
+
+		row.barcode = field("barcode")
+		if row.barcode != "" {
+			if _, err := validateGTIN(row.barcode); err != nil {
+				fail("barcode", err.Error())
+			}
+		}
+
+		if mrp, err := strconv.ParseFloat(field("mrp"), 64); err != nil || mrp <= 0 {
+			fail("mrp", "mrp must be a positive number")
+		} else if math.Abs(mrp*100-math.Round(mrp*100)) > 1e-6 || mrp >= catalogImportMaxMRP {
+			// item_financial.mrp_price is DECIMAL(10, 2)
+			fail("mrp", "mrp must be below %d with at most 2 decimal places", int(catalogImportMaxMRP))
+		} else {
+			row.mrp = mrp
+		}
+
+		if v := field("buy_price"); v != "" {
+			buy, err := strconv.ParseFloat(v, 64)
+			if err != nil || buy < 0 {
+				fail("buy_price", "buy_price must be a non-negative number")
+			} else {
+				row.buyPrice = &buy
+			}
+		}
+
+		if v := field("tax"); v != "" {
+			rate, err := strconv.ParseFloat(v, 64)
+			if err != nil || rate < 0 {
+				fail("tax", "tax must be a non-negative percentage")
+			} else {
+				buy := 0.0
+				if row.buyPrice != nil {
+					buy = *row.buyPrice
+				}
+				tax := computeItemTax(rate, buy, row.mrp)
+				key := [2]int{int(math.Round(tax.gstRate * 100)), int(math.Round(tax.cessRate * 100))}
+				if id, ok := lookups.taxes[key]; ok {
+					row.tax = &tax
+					row.taxID = id
+				} else {
+					fail("tax", "no tax slab for %s%%", v)
+				}
+			}
+		}
+		if row.buyPrice != nil && row.tax == nil {
+			fail("tax", "tax is required when buy_price is set")
+		}
+
+		if v := field("margin"); v != "" {
+			margin, err := strconv.ParseFloat(v, 64)
+			if err != nil || margin < 0 || margin > 100 {
+				fail("margin", "margin must be a percentage between 0 and 100")
+			} else {
+				row.margin = &margin
+			}
+		} else if row.buyPrice != nil && row.tax != nil && row.mrp > 0 {
+			margin := math.Round((row.mrp-row.tax.buyPriceIncTax)/row.mrp*10000) / 100
+			row.margin = &margin
+		}
+
+		row.images = splitList(field("images"), "|")
+
+		for _, entry := range splitList(field("stock"), ";") {
+			store, qty, ok := strings.Cut(entry, ":")
+			quantity, err := strconv.Atoi(strings.TrimSpace(qty))
+			if !ok || err != nil || quantity < 0 {
+				fail("stock", "stock entry %q must look like <store>:<quantity>", entry)
+				continue
+			}
+			store = strings.TrimSpace(store)
+			storeID, err := strconv.Atoi(store)
+			if err != nil {
+				storeID = lookups.stores[strings.ToLower(store)]
+			}
+			if !lookups.storeIDs[storeID] {
+				fail("stock", "unknown store %q", store)
+				continue
+			}
+			row.stock = append(row.stock, catalogImportStock{storeID: storeID, quantity: quantity})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// checkCatalogDuplicates reports names and barcodes repeated in the file or already in the catalog.
+func (s *PostgresStore) checkCatalogDuplicates(rows []*catalogImportRow, report *types.CatalogImportReport) error {
+	seenNames := map[string]int{}
+	seenBarcodes := map[string]int{}
+	var names, barcodes []string
+	for _, row := range rows {
+		name := strings.ToLower(row.name)
+		if first, ok := seenNames[name]; ok && name != "" {
+			report.Errors = append(report.Errors, types.CatalogImportError{Row: row.line, Column: "name", Message: fmt.Sprintf("duplicate of row %d", first)})
+		} else {
+			seenNames[name] = row.line
+			names = append(names, name)
+		}
+		if row.barcode == "" {
+			continue
+		}
+		key := gtinKey(row.barcode)
+		if first, ok := seenBarcodes[key]; ok {
+			report.Errors = append(report.Errors, types.CatalogImportError{Row: row.line, Column: "barcode", Message: fmt.Sprintf("duplicate of row %d", first)})
+		} else {
+			seenBarcodes[key] = row.line
+			barcodes = append(barcodes, key)
+		}
+	}
+
+	existing := func(query string, values []string, column string, seen map[string]int) error {
+		rows, err := s.db.Query(query, pq.Array(values))
+		if err != nil {
+			return fmt.Errorf("error checking existing %s: %w", column, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var v string
+			if err := rows.Scan(&v); err != nil {
+				return fmt.Errorf("error scanning existing %s: %w", column, err)
+			}
+			report.Errors = append(report.Errors, types.CatalogImportError{Row: seen[v], Column: column, Message: fmt.Sprintf("%s %q already exists", column, v)})
+		}
+		return rows.Err()
+	}
+	if err := existing(`SELECT LOWER(name) FROM item WHERE LOWER(name) = ANY($1)`, names, "name", seenNames); err != nil {
+		return err
+	}
+	return existing(`SELECT gtin FROM item_barcode WHERE gtin = ANY($1)`, barcodes, "barcode", seenBarcodes)
+}
+
+// insertCatalogRows writes all rows in one transaction; the first failing row rolls everything back.
+func (s *PostgresStore) insertCatalogRows(rows []*catalogImportRow) ([]int, *types.CatalogImportError) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, &types.CatalogImportError{Message: fmt.Sprintf("error starting transaction: %v", err)}
+	}
+	defer tx.Rollback()
+
+	if err := setPriceSource(tx, "import"); err != nil {
+		return nil, &types.CatalogImportError{Message: err.Error()}
+	}
+
+	var itemIDs []int
+	for _, row := range rows {
+		itemID, err := insertCatalogRow(tx, row)
+		if err != nil {
+			return nil, &types.CatalogImportError{Row: row.line, Message: err.Error()}
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, &types.CatalogImportError{Message: fmt.Sprintf("error committing transaction: %v", err)}
+	}
+	return itemIDs, nil
+}
+
+func insertCatalogRow(tx *sql.Tx, row *catalogImportRow) (int, error) {
+	var itemID int
+	err := tx.QueryRow(`
+        INSERT INTO item (name, brand_id, quantity, unit_of_quantity, description, status)
+        VALUES ($1, $2, $3, $4, $5, 'draft')
+        RETURNING id`,
+		row.name, row.brandID, row.quantity, row.unit, row.description).Scan(&itemID)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting item: %w", err)
+	}
+
+	if row.barcode != "" {
+		if _, err := addItemBarcode(tx, itemID, row.barcode, true); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, categoryID := range row.categoryIDs {
+		_, err = tx.Exec(`INSERT INTO item_category (item_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, itemID, categoryID)
+		if err != nil {
+			return 0, fmt.Errorf("error inserting into item_category: %w", err)
+		}
+	}
+
+	for i, image := range row.images {
+		_, err = tx.Exec(`INSERT INTO item_image (item_id, image_url, order_position) VALUES ($1, $2, $3)`, itemID, image, i+1)
+		if err != nil {
+			return 0, fmt.Errorf("error inserting into item_image: %w", err)
+		}
+	}
+
+	if row.tax != nil {
+		_, err = tx.Exec(`
+            INSERT INTO item_financial (item_id, buy_price, mrp_price, gst_on_buy, cess_on_buy, gst_on_mrp, cess_on_mrp, margin, tax_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			itemID, nullableBuyPrice(row), row.mrp, row.tax.gstOnBuy, row.tax.cessOnBuy, row.tax.gstOnMRP, row.tax.cessOnMRP, row.margin, row.taxID)
+	} else {
+		_, err = tx.Exec(`INSERT INTO item_financial (item_id, mrp_price, margin) VALUES ($1, $2, $3)`, itemID, row.mrp, row.margin)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error inserting into item_financial: %w", err)
+	}
+
+	// store_price is a whole-rupee INT; store_selling_price rounds the MRP the way carts charge it
+	for _, stock := range row.stock {
+		_, err = tx.Exec(`
+            INSERT INTO item_store (item_id, store_price, discount, store_id, stock_quantity)
+            VALUES ($1, store_selling_price($2, 0, NULL), 0, $3, $4)`,
+			itemID, row.mrp, stock.storeID, stock.quantity)
+		if err != nil {
+			return 0, fmt.Errorf("error inserting into item_store for store %d: %w", stock.storeID, err)
+		}
+	}
+
+	return itemID, nil
+}
+
+// nullableBuyPrice stores the tax-inclusive buy price, matching ManagerEditItemFinancialByItemId.
+func nullableBuyPrice(row *catalogImportRow) interface{} {
+	if row.buyPrice == nil {
+		return nil
+	}
+	return row.tax.buyPriceIncTax
+}
+
+func splitList(v, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(v, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,463 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// translationReportDefaultLimit caps the entities listed per field and language when the request
+// sets no limit.
+const translationReportDefaultLimit = 100
+
+// translationEntityFilter narrows the rows of an entity that need translating.
+var translationEntityFilter = map[string]string{
+	types.TranslationEntityItem:     "AND e.status <> 'discontinued'",
+	types.TranslationEntityCategory: "",
+}
+
+// CreateCatalogTranslationTables creates catalog_translation, one row per entity, field and
+// language. Translations are removed with the item or category they belong to.
+func (s *PostgresStore) CreateCatalogTranslationTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS catalog_translation (
+        entity TEXT NOT NULL CHECK (entity IN ('item', 'category')),
+        entity_id INT NOT NULL,
+        field TEXT NOT NULL,
+        language TEXT NOT NULL,
+        value TEXT NOT NULL CHECK (value <> ''),
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_by INT,
+        PRIMARY KEY (entity, entity_id, field, language)
+    );
+
+    CREATE OR REPLACE FUNCTION catalog_translation_cleanup() RETURNS TRIGGER AS $$
+    BEGIN
+        DELETE FROM catalog_translation WHERE entity = TG_ARGV[0] AND entity_id = OLD.id;
+        RETURN OLD;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS catalog_translation_item ON item;
+    CREATE TRIGGER catalog_translation_item AFTER DELETE ON item
+    FOR EACH ROW EXECUTE FUNCTION catalog_translation_cleanup('item');
+
+    DROP TRIGGER IF EXISTS catalog_translation_category ON category;
+    CREATE TRIGGER catalog_translation_category AFTER DELETE ON category
+    FOR EACH ROW EXECUTE FUNCTION catalog_translation_cleanup('category');`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating catalog translation tables: %w", err)
+	}
+	return nil
+}
+
+// catalogText is a translatable field of a response, replaced in place by translate.
+type catalogText struct {
+	entity string
+	id     int
+	field  string
+	value  *string
+}
+
+// catalogLanguageKey is appended to a catalog cache key, so each fallback chain is cached apart.
+func catalogLanguageKey(langs []string) string {
+	if len(langs) == 0 {
+		return ""
+	}
+	return ":lang=" + strings.Join(langs, ",")
+}
+
+// translate replaces each text with its translation in the first of langs that has one. Texts
+// with no translation in any of langs keep their English value.
+func (s *PostgresStore) translate(langs []string, texts []catalogText) error {
+	if len(langs) == 0 || len(texts) == 0 {
+		return nil
+	}
+
+	ids := map[string][]int{}
+	for _, text := range texts {
+		ids[text.entity] = append(ids[text.entity], text.id)
+	}
+
+	found := map[string]string{}
+	for entity, entityIDs := range ids {
+		rows, err := s.reader(readCatalog).Query(`
+            SELECT DISTINCT ON (entity_id, field) entity_id, field, value
+            FROM catalog_translation
+            WHERE entity = $1 AND entity_id = ANY($2) AND language = ANY($3)
+            ORDER BY entity_id, field, array_position($3, language)`, entity, pq.Array(entityIDs), pq.Array(langs))
+		if err != nil {
+			return fmt.Errorf("error querying %s translations: %w", entity, err)
+		}
+		for rows.Next() {
+			var id int
+			var field, value string
+			if err := rows.Scan(&id, &field, &value); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning translation: %w", err)
+			}
+			found[fmt.Sprintf("%s:%d:%s", entity, id, field)] = value
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	for _, text := range texts {
+		if value, ok := found[fmt.Sprintf("%s:%d:%s", text.entity, text.id, text.field)]; ok {
+			*text.value = value
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) translateCategories(langs []string, categories []*types.Category) error {
+	texts := make([]catalogText, 0, len(categories))
+	for _, category := range categories {
+		texts = append(texts, catalogText{types.TranslationEntityCategory, category.ID, "name", &category.Name})
+	}
+	return s.translate(langs, texts)
+}
+
+func (s *PostgresStore) translateHigherLevelCategories(langs []string, categories []*types.Higher_Level_Category) error {
+	texts := make([]catalogText, 0, len(categories))
+	for _, category := range categories {
+		texts = append(texts, catalogText{types.TranslationEntityCategory, category.ID, "name", &category.Name})
+	}
+	return s.translate(langs, texts)
+}
+
+func (s *PostgresStore) translateCategoryTree(langs []string, nodes []*types.CategoryNode) error {
+	texts := []catalogText{}
+	var collect func(nodes []*types.CategoryNode)
+	collect = func(nodes []*types.CategoryNode) {
+		for _, node := range nodes {
+			texts = append(texts, catalogText{types.TranslationEntityCategory, node.ID, "name", &node.Name})
+			collect(node.Children)
+		}
+	}
+	collect(nodes)
+	return s.translate(langs, texts)
+}
+
+func (s *PostgresStore) translateItemListing(langs []string, items []*types.Get_Items_By_CategoryID_And_StoreID) error {
+	texts := make([]catalogText, 0, len(items))
+	for _, item := range items {
+		texts = append(texts, catalogText{types.TranslationEntityItem, item.ID, "name", &item.Name})
+		texts = appendVariantTexts(texts, item.Variants)
+	}
+	return s.translate(langs, texts)
+}
+
+func appendVariantTexts(texts []catalogText, variants []types.ItemVariant) []catalogText {
+	for i := range variants {
+		texts = append(texts, catalogText{types.TranslationEntityItem, variants[i].ItemID, "name", &variants[i].Name})
+	}
+	return texts
+}
+
+// translateSearchResult translates the item names and category facet labels of a search.
+func (s *PostgresStore) translateSearchResult(langs []string, result *types.SearchResult) error {
+	texts := make([]catalogText, 0, len(result.Items)+len(result.Facets.Categories))
+	for _, item := range result.Items {
+		texts = append(texts, catalogText{types.TranslationEntityItem, item.ID, "name", &item.Name})
+		texts = appendVariantTexts(texts, item.Variants)
+	}
+	for i := range result.Facets.Categories {
+		facet := &result.Facets.Categories[i]
+		if id, err := strconv.Atoi(facet.Key); err == nil {
+			texts = append(texts, catalogText{types.TranslationEntityCategory, id, "name", &facet.Label})
+		}
+	}
+	return s.translate(langs, texts)
+}
+
+// GetItemDetail returns an active item as Get_Item_By_ID does, with its name and description
+// translated.
+func (s *PostgresStore) GetItemDetail(id int, langs []string) (*types.Get_Item_Barcode, error) {
+	item, err := s.getItemByID(id, true)
+	if err != nil {
+		return nil, err
+	}
+	err = s.translate(langs, []catalogText{
+		{types.TranslationEntityItem, item.ID, "name", &item.Name},
+		{types.TranslationEntityItem, item.ID, "description", &item.Description},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetProductGroupDetail returns a product group as GetProductGroup does, with its variant names
+// translated.
+func (s *PostgresStore) GetProductGroupDetail(groupID int, langs []string) (*types.ProductGroup, error) {
+	group, err := s.GetProductGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translate(langs, appendVariantTexts(nil, group.Variants)); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetBundleDetail returns a bundle as GetBundle does, with the names of the bundle and its
+// components translated.
+func (s *PostgresStore) GetBundleDetail(bundleItemID int, langs []string) (*types.Bundle, error) {
+	bundle, err := s.GetBundle(bundleItemID)
+	if err != nil {
+		return nil, err
+	}
+	texts := []catalogText{{types.TranslationEntityItem, bundle.BundleItemID, "name", &bundle.Name}}
+	for i := range bundle.Components {
+		component := &bundle.Components[i]
+		texts = append(texts, catalogText{types.TranslationEntityItem, component.ItemID, "name", &component.Name})
+	}
+	if err := s.translate(langs, texts); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (s *PostgresStore) translateRecommendedItems(langs []string, items []types.RecommendedItem) error {
+	texts := make([]catalogText, 0, len(items))
+	for i := range items {
+		texts = append(texts, catalogText{types.TranslationEntityItem, items[i].ItemID, "name", &items[i].Name})
+	}
+	return s.translate(langs, texts)
+}
+
+// validTranslationTarget checks the entity and language of a translation request.
+func validTranslationTarget(entity, language string) error {
+	if _, ok := types.TranslatableFields[entity]; !ok {
+		return fmt.Errorf("unknown translation entity %q", entity)
+	}
+	if language == "" {
+		return nil
+	}
+	for _, lang := range types.CatalogLanguages {
+		if lang == language {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported catalog language %q", language)
+}
+
+// GetCatalogTranslation returns the English text of an entity's translatable fields together with
+// its translations and the fields each language still misses.
+func (s *PostgresStore) GetCatalogTranslation(req types.CatalogTranslationRequest) (*types.CatalogTranslation, error) {
+	if err := validTranslationTarget(req.Entity, ""); err != nil {
+		return nil, err
+	}
+	fields := types.TranslatableFields[req.Entity]
+
+	columns := make([]string, len(fields))
+	values := make([]string, len(fields))
+	dest := make([]interface{}, len(fields))
+	for i, field := range fields {
+		columns[i] = "COALESCE(" + field + ", '')"
+		dest[i] = &values[i]
+	}
+	err := s.db.QueryRow(`SELECT `+strings.Join(columns, ", ")+` FROM `+req.Entity+` WHERE id = $1`, req.EntityID).Scan(dest...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s %d does not exist", req.Entity, req.EntityID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s %d: %w", req.Entity, req.EntityID, err)
+	}
+
+	translation := &types.CatalogTranslation{
+		Entity:       req.Entity,
+		EntityID:     req.EntityID,
+		Base:         map[string]string{},
+		Translations: map[string]map[string]string{},
+		Missing:      map[string][]string{},
+	}
+	for i, field := range fields {
+		translation.Base[field] = values[i]
+	}
+	for _, lang := range types.CatalogLanguages {
+		translation.Translations[lang] = map[string]string{}
+	}
+
+	rows, err := s.db.Query(`
+        SELECT language, field, value
+        FROM catalog_translation
+        WHERE entity = $1 AND entity_id = $2`, req.Entity, req.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying translations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lang, field, value string
+		if err := rows.Scan(&lang, &field, &value); err != nil {
+			return nil, fmt.Errorf("error scanning translation: %w", err)
+		}
+		if _, ok := translation.Translations[lang]; ok {
+			translation.Translations[lang][field] = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, lang := range types.CatalogLanguages {
+		missing := []string{}
+		for _, field := range fields {
+			if translation.Base[field] != "" && translation.Translations[lang][field] == "" {
+				missing = append(missing, field)
+			}
+		}
+		translation.Missing[lang] = missing
+	}
+	return translation, nil
+}
+
+// SetCatalogTranslation writes the translations of an entity's fields in one language and drops
+// the cached listings that show them.
+func (s *PostgresStore) SetCatalogTranslation(req types.SetCatalogTranslation) (*types.CatalogTranslation, error) {
+	if req.Language == "" {
+		return nil, fmt.Errorf("language is required")
+	}
+	if err := validTranslationTarget(req.Entity, req.Language); err != nil {
+		return nil, err
+	}
+	for field := range req.Fields {
+		known := false
+		for _, f := range types.TranslatableFields[req.Entity] {
+			known = known || f == field
+		}
+		if !known {
+			return nil, fmt.Errorf("%s has no translatable field %q", req.Entity, field)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM `+req.Entity+` WHERE id = $1)`, req.EntityID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking %s %d: %w", req.Entity, req.EntityID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s %d does not exist", req.Entity, req.EntityID)
+	}
+
+	for field, value := range req.Fields {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			_, err = tx.Exec(`
+                DELETE FROM catalog_translation
+                WHERE entity = $1 AND entity_id = $2 AND field = $3 AND language = $4`,
+				req.Entity, req.EntityID, field, req.Language)
+		} else {
+			_, err = tx.Exec(`
+                INSERT INTO catalog_translation (entity, entity_id, field, language, value, updated_by)
+                VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))
+                ON CONFLICT (entity, entity_id, field, language) DO UPDATE
+                SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP`,
+				req.Entity, req.EntityID, field, req.Language, value, req.UpdatedBy)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error saving %s translation of %s %d: %w", field, req.Entity, req.EntityID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	if req.Entity == types.TranslationEntityCategory {
+		s.catalog.invalidateCategories()
+	} else {
+		s.catalog.invalidateItems(0, req.EntityID)
+	}
+	s.catalog.invalidatePrefix(catalogKeyAutocomplete)
+
+	return s.GetCatalogTranslation(types.CatalogTranslationRequest{Entity: req.Entity, EntityID: req.EntityID})
+}
+
+// MissingTranslations reports, per entity, field and language, how many values are translated and
+// lists the ones that are not. Entity and Language narrow the report when set.
+func (s *PostgresStore) MissingTranslations(req types.MissingTranslationsRequest) (*types.MissingTranslationsReport, error) {
+	entities, languages := types.TranslationEntities, types.CatalogLanguages
+	if req.Entity != "" {
+		if err := validTranslationTarget(req.Entity, ""); err != nil {
+			return nil, err
+		}
+		entities = []string{req.Entity}
+	}
+	if req.Language != "" {
+		if err := validTranslationTarget(types.TranslationEntityItem, req.Language); err != nil {
+			return nil, err
+		}
+		languages = []string{req.Language}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = translationReportDefaultLimit
+	}
+
+	report := &types.MissingTranslationsReport{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Coverage:    []types.TranslationCoverage{},
+	}
+	for _, entity := range entities {
+		for _, field := range types.TranslatableFields[entity] {
+			for _, lang := range languages {
+				coverage, err := s.translationCoverage(entity, field, lang, limit)
+				if err != nil {
+					return nil, err
+				}
+				report.Coverage = append(report.Coverage, *coverage)
+			}
+		}
+	}
+	return report, nil
+}
+
+func (s *PostgresStore) translationCoverage(entity, field, lang string, limit int) (*types.TranslationCoverage, error) {
+	rows, err := s.reader(readExport).Query(`
+        SELECT e.id, COALESCE(e.`+field+`, ''), ct.value IS NOT NULL
+        FROM `+entity+` e
+        LEFT JOIN catalog_translation ct
+               ON ct.entity = $1 AND ct.entity_id = e.id AND ct.field = $2 AND ct.language = $3
+        WHERE COALESCE(e.`+field+`, '') <> '' `+translationEntityFilter[entity]+`
+        ORDER BY e.id`, entity, field, lang)
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s %s translations: %w", entity, field, err)
+	}
+	defer rows.Close()
+
+	coverage := &types.TranslationCoverage{
+		Entity:   entity,
+		Field:    field,
+		Language: lang,
+		Missing:  []types.MissingTranslation{},
+	}
+	for rows.Next() {
+		var missing types.MissingTranslation
+		var translated bool
+		if err := rows.Scan(&missing.EntityID, &missing.Base, &translated); err != nil {
+			return nil, fmt.Errorf("error scanning translation coverage: %w", err)
+		}
+		coverage.Total++
+		if translated {
+			coverage.Translated++
+		} else if len(coverage.Missing) < limit {
+			coverage.Missing = append(coverage.Missing, missing)
+		}
+	}
+	return coverage, rows.Err()
+}
@@ -29,76 +29,28 @@ func (s *PostgresStore) Create_Category_Higher_Level_Mapping_Table(tx *sql.Tx) e
 	return err
 }
 
+// Mappings are parent links of the category tree now: a mapping's ID is its category's ID, and
+// creating or updating one moves the category (with its subtree) under the higher level category.
 func (s *PostgresStore) Create_Category_Higher_Level_Mapping(chlm *types.Category_Higher_Level_Mapping) (*types.Category_Higher_Level_Mapping, error) {
-	// Start a new transaction.
-	tx, err := s.db.Begin()
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback() // Rollback the transaction in case of failure.
-
-	// Try inserting the new record.
-	query := `
-        INSERT INTO category_higher_level_mapping
-        (higher_level_category_id, category_id, created_by) 
-        VALUES ($1, $2, $3) 
-        ON CONFLICT (higher_level_category_id, category_id) DO NOTHING 
-        RETURNING id, higher_level_category_id, category_id, created_by;
-    `
-	rows, err := tx.Query(
-		query,
-		chlm.Higher_Level_Category_ID,
-		chlm.Category_ID,
-		chlm.Created_By,
-	)
-	if err != nil {
-		return nil, err
+	existing, err := s.Get_Category_Higher_Level_Mapping_By_ID(chlm.Category_ID)
+	if err == nil && existing.Higher_Level_Category_ID == chlm.Higher_Level_Category_ID {
+		return existing, nil
 	}
 
-	// If we have results from the INSERT query, then the insertion was successful.
-	if rows.Next() {
-		category_higher_level_mapping, err := scan_Into_Category_Higher_Level_Mapping(rows)
-		if err != nil {
-			return nil, err
-		}
-		err = tx.Commit() // Commit the transaction.
-		if err != nil {
-			return nil, err
-		}
-		return category_higher_level_mapping, nil
-	}
-
-	// If we reached here, it means the record already exists. Fetch and return the existing record.
-	existingQuery := `
-        SELECT id, higher_level_category_id, category_id, created_by 
-        FROM category_higher_level_mapping 
-        WHERE higher_level_category_id = $1 AND category_id = $2;
-    `
-	existingRows, err := tx.Query(existingQuery, chlm.Higher_Level_Category_ID, chlm.Category_ID)
-	if err != nil {
+	parentID := chlm.Higher_Level_Category_ID
+	if _, err := s.MoveCategory(types.CategoryMove{CategoryID: chlm.Category_ID, ParentID: &parentID}); err != nil {
 		return nil, err
 	}
 
-	if existingRows.Next() {
-		existingMapping, err := scan_Into_Category_Higher_Level_Mapping(existingRows)
-		if err != nil {
-			return nil, err
-		}
-		err = tx.Commit() // Commit the transaction.
-		if err != nil {
-			return nil, err
-		}
-		return existingMapping, nil
-	}
-
-	return nil, errors.New("expected to find an existing record, but none found")
+	return s.Get_Category_Higher_Level_Mapping_By_ID(chlm.Category_ID)
 }
 
 func (s *PostgresStore) Get_Category_Higher_Level_Mappings() ([]*types.Category_Higher_Level_Mapping, error) {
-	rows, err := s.db.Query("select id, higher_level_category_id, category_id, created_by from category_higher_level_mapping")
+	rows, err := s.db.Query("select id, parent_id, id, COALESCE(created_by, 0) from category where parent_id is not null order by parent_id, sort_order, id")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	chlms := []*types.Category_Higher_Level_Mapping{}
 	for rows.Next() {
@@ -113,10 +65,11 @@ func (s *PostgresStore) Get_Category_Higher_Level_Mappings() ([]*types.Category_
 }
 
 func (s *PostgresStore) Get_Category_Higher_Level_Mapping_By_ID(id int) (*types.Category_Higher_Level_Mapping, error) {
-	row, err := s.db.Query("select id, higher_level_category_id, category_id, created_by from category_higher_level_mapping where id = $1", id)
+	row, err := s.db.Query("select id, parent_id, id, COALESCE(created_by, 0) from category where id = $1 and parent_id is not null", id)
 	if err != nil {
 		return nil, err
 	}
+	defer row.Close()
 
 	for row.Next() {
 		return scan_Into_Category_Higher_Level_Mapping(row)
@@ -126,52 +79,28 @@ func (s *PostgresStore) Get_Category_Higher_Level_Mapping_By_ID(id int) (*types.
 }
 
 func (s *PostgresStore) Update_Category_Higher_Level_Mapping(chlm *types.Update_Category_Higher_Level_Mapping) (*types.Update_Category_Higher_Level_Mapping, error) {
-	query := `update category_higher_level_mapping
-	set 
-	higher_level_category_id = $1
-	category_id = $2
-	where id = $3 
-	returning higher_level_category_id, category_id, id`
-
-	rows, err := s.db.Query(
-		query,
-		chlm.Higher_Level_Category_ID,
-		chlm.Category_ID,
-		chlm.ID,
-	)
-	if err != nil {
-		return nil, err
+	if chlm.Category_ID != chlm.ID {
+		return nil, errors.New("a mapping can only move its own category, category_id must equal id")
 	}
 
-	chlms := []*types.Update_Category_Higher_Level_Mapping{}
-
-	for rows.Next() {
-		chlm, err := scan_Into_Update_Category_Higher_Level_Mapping(rows)
-		if err != nil {
-			return nil, err
-		}
-		chlms = append(chlms, chlm)
+	parentID := chlm.Higher_Level_Category_ID
+	if _, err := s.MoveCategory(types.CategoryMove{CategoryID: chlm.Category_ID, ParentID: &parentID}); err != nil {
+		return nil, err
 	}
 
-	return chlms[0], nil
+	return chlm, nil
 }
 
+// Delete_Category_Higher_Level_Mapping detaches a category from its parent, making it a top level category.
 func (s *PostgresStore) Delete_Category_Higher_Level_Mapping(id int) error {
-	_, err := s.db.Exec("DELETE FROM category_higher_level_mapping WHERE higher_level_category_id = $1", id)
-	if err != nil {
+	if _, err := s.Get_Category_Higher_Level_Mapping_By_ID(id); err != nil {
 		return err
 	}
 
-	_, err = s.db.Exec("DELETE FROM higher_level_category_image WHERE higher_level_category_id = $1", id)
-	if err != nil {
-		return err
-	}
-	_, err = s.db.Exec("DELETE FROM higher_level_category WHERE id = $1", id)
+	_, err := s.MoveCategory(types.CategoryMove{CategoryID: id})
 	return err
 }
 
-
-
 func scan_Into_Category_Higher_Level_Mapping(rows *sql.Rows) (*types.Category_Higher_Level_Mapping, error) {
 	chlm := new(types.Category_Higher_Level_Mapping)
 	err := rows.Scan(
@@ -183,13 +112,3 @@ func scan_Into_Category_Higher_Level_Mapping(rows *sql.Rows) (*types.Category_Hi
 
 	return chlm, err
 }
-
-func scan_Into_Update_Category_Higher_Level_Mapping(rows *sql.Rows) (*types.Update_Category_Higher_Level_Mapping, error) {
-	chlm := new(types.Update_Category_Higher_Level_Mapping)
-	error := rows.Scan(
-		&chlm.Higher_Level_Category_ID,
-		&chlm.Category_ID,
-		&chlm.ID,
-	)
-	return chlm, error
-}
@@ -0,0 +1,491 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/girithc/pronto-go/types"
+
+	"github.com/lib/pq"
+)
+
+// categoryVisibleClause is true for a category c that is inside its schedule window and not disabled
+// in store $1. A store of 0 skips the per-store check.
+const categoryVisibleClause = `
+    (c.active_from IS NULL OR c.active_from <= CURRENT_TIMESTAMP)
+    AND (c.active_until IS NULL OR c.active_until > CURRENT_TIMESTAMP)
+    AND ($1 = 0 OR NOT EXISTS (
+        SELECT 1 FROM category_store cs
+        WHERE cs.category_id = c.id AND cs.store_id = $1 AND NOT cs.enabled
+    ))`
+
+// CreateCategoryTree turns category into a tree: parent_id and sort_order place a category among
+// its siblings, active_from/active_until schedule seasonal categories and category_store disables a
+// category per store. It then migrates higher_level_category and category_higher_level_mapping into
+// the tree once.
+func (s *PostgresStore) CreateCategoryTree(tx *sql.Tx) error {
+	alterQuery := `
+    ALTER TABLE category
+        ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES category(id),
+        ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0,
+        ADD COLUMN IF NOT EXISTS active_from TIMESTAMP,
+        ADD COLUMN IF NOT EXISTS active_until TIMESTAMP,
+        ADD COLUMN IF NOT EXISTS legacy_higher_level_category_id INT UNIQUE;
+
+    DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'category_parent_not_self') THEN
+            ALTER TABLE category ADD CONSTRAINT category_parent_not_self CHECK (parent_id <> id);
+        END IF;
+    END
+    $$;
+
+    CREATE INDEX IF NOT EXISTS category_parent_sort_order ON category (parent_id, sort_order);`
+
+	if _, err := tx.Exec(alterQuery); err != nil {
+		return fmt.Errorf("error adding category tree columns: %w", err)
+	}
+
+	storeQuery := `
+    CREATE TABLE IF NOT EXISTS category_store (
+        category_id INT NOT NULL REFERENCES category(id) ON DELETE CASCADE,
+        store_id INT NOT NULL REFERENCES store(id) ON DELETE CASCADE,
+        enabled BOOLEAN NOT NULL DEFAULT TRUE,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        created_by INT,
+        PRIMARY KEY (category_id, store_id)
+    )`
+
+	if _, err := tx.Exec(storeQuery); err != nil {
+		return fmt.Errorf("error creating category_store table: %w", err)
+	}
+
+	// A category has a single parent in the tree, so every higher level mapping except the one it was
+	// placed under is recorded here for managers to re-place, rather than dropped silently.
+	droppedQuery := `
+    CREATE TABLE IF NOT EXISTS category_tree_migration_dropped (
+        category_id INT NOT NULL REFERENCES category(id) ON DELETE CASCADE,
+        higher_level_category_id INT NOT NULL,
+        parent_id INT REFERENCES category(id) ON DELETE SET NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (category_id, higher_level_category_id)
+    )`
+
+	if _, err := tx.Exec(droppedQuery); err != nil {
+		return fmt.Errorf("error creating category_tree_migration_dropped table: %w", err)
+	}
+
+	// The migration runs once: afterwards the tree is the source of truth, and a category moved to
+	// the root must not be pulled back under its old higher level category on the next start.
+	markerQuery := `
+    CREATE TABLE IF NOT EXISTS category_tree_migration (
+        id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+        migrated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`
+
+	if _, err := tx.Exec(markerQuery); err != nil {
+		return fmt.Errorf("error creating category_tree_migration table: %w", err)
+	}
+
+	var migrated bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM category_tree_migration)`).Scan(&migrated); err != nil {
+		return fmt.Errorf("error checking category tree migration: %w", err)
+	}
+	if migrated {
+		return nil
+	}
+
+	// A higher level category whose name is already taken by a category gets a suffix, since
+	// category names are unique and the category may well be one of its own children.
+	migrateQuery := `
+    INSERT INTO category (name, created_at, created_by, legacy_higher_level_category_id, sort_order)
+    SELECT CASE WHEN EXISTS (SELECT 1 FROM category c WHERE LOWER(c.name) = LOWER(h.name))
+                THEN h.name || ' (all)' ELSE h.name END,
+           h.created_at, h.created_by, h.id, h.id
+    FROM higher_level_category h
+    WHERE NOT EXISTS (SELECT 1 FROM category c WHERE c.legacy_higher_level_category_id = h.id);
+
+    INSERT INTO category_image (category_id, image, position, created_at, created_by)
+    SELECT c.id, hi.image, hi.position, hi.created_at, hi.created_by
+    FROM higher_level_category_image hi
+    JOIN category c ON c.legacy_higher_level_category_id = hi.higher_level_category_id
+    ON CONFLICT (category_id, position) DO NOTHING;
+
+    UPDATE category c
+    SET parent_id = m.parent_id, sort_order = m.sort_order
+    FROM (
+        SELECT DISTINCT ON (m.category_id) m.category_id, p.id AS parent_id, m.id AS sort_order
+        FROM category_higher_level_mapping m
+        JOIN category p ON p.legacy_higher_level_category_id = m.higher_level_category_id
+        ORDER BY m.category_id, m.id
+    ) m
+    WHERE c.id = m.category_id AND c.parent_id IS NULL AND c.id <> m.parent_id;
+
+    INSERT INTO category_tree_migration_dropped (category_id, higher_level_category_id, parent_id)
+    SELECT m.category_id, m.higher_level_category_id, c.parent_id
+    FROM category_higher_level_mapping m
+    JOIN category p ON p.legacy_higher_level_category_id = m.higher_level_category_id
+    JOIN category c ON c.id = m.category_id
+    WHERE c.parent_id IS DISTINCT FROM p.id
+    ON CONFLICT DO NOTHING;
+
+    INSERT INTO category_tree_migration DEFAULT VALUES;`
+
+	if _, err := tx.Exec(migrateQuery); err != nil {
+		return fmt.Errorf("error migrating higher level categories into the category tree: %w", err)
+	}
+
+	var dropped int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM category_tree_migration_dropped`).Scan(&dropped); err != nil {
+		return fmt.Errorf("error counting dropped higher level mappings: %w", err)
+	}
+	if dropped > 0 {
+		log.Printf("category tree migration: %d higher level mappings were not kept as the parent of their category, see category_tree_migration_dropped", dropped)
+	}
+
+	return nil
+}
+
+// GetCategoryTreeMigrationDropped lists the higher level mappings the tree migration could not keep,
+// each with the parent its category was placed under instead.
+func (s *PostgresStore) GetCategoryTreeMigrationDropped() ([]*types.CategoryMappingDropped, error) {
+	query := `
+    SELECT d.category_id, c.name, d.higher_level_category_id, COALESCE(h.name, ''), d.parent_id
+    FROM category_tree_migration_dropped d
+    JOIN category c ON c.id = d.category_id
+    LEFT JOIN higher_level_category h ON h.id = d.higher_level_category_id
+    ORDER BY d.category_id, d.higher_level_category_id`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying dropped higher level mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var dropped []*types.CategoryMappingDropped
+	for rows.Next() {
+		d := &types.CategoryMappingDropped{}
+		if err := rows.Scan(&d.CategoryID, &d.CategoryName, &d.HigherLevelCategoryID, &d.HigherLevelCategoryName, &d.ParentID); err != nil {
+			return nil, err
+		}
+		dropped = append(dropped, d)
+	}
+	return dropped, rows.Err()
+}
+
+// GetCategoryTree returns the categories visible in storeID below rootID (0 for the whole tree).
+// A category hidden by its schedule or disabled in the store hides its whole subtree.
+func (s *PostgresStore) GetCategoryTree(storeID int, rootID int) ([]*types.CategoryNode, error) {
+	query := `
+    WITH RECURSIVE tree AS (
+        SELECT c.id FROM category c
+        WHERE c.parent_id IS NULL AND ` + categoryVisibleClause + `
+        UNION ALL
+        SELECT c.id FROM category c
+        JOIN tree t ON c.parent_id = t.id
+        WHERE ` + categoryVisibleClause + `
+    )
+    SELECT c.id, c.name, COALESCE(ci.image, ''), c.promotion, c.parent_id, c.sort_order, c.active_from, c.active_until
+    FROM tree t
+    JOIN category c ON c.id = t.id
+    LEFT JOIN category_image ci ON ci.category_id = c.id AND ci.position = 1
+    ORDER BY c.sort_order, c.id`
+
+	rows, err := s.reader(readCatalog).Query(query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying category tree: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*types.CategoryNode
+	for rows.Next() {
+		node := &types.CategoryNode{}
+		if err := rows.Scan(&node.ID, &node.Name, &node.Image, &node.Promotion, &node.ParentID, &node.SortOrder, &node.ActiveFrom, &node.ActiveUntil); err != nil {
+			return nil, fmt.Errorf("error scanning category tree row: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category tree rows: %w", err)
+	}
+
+	// Rows are in sibling order, so appending keeps every Children slice ordered.
+	byID := make(map[int]*types.CategoryNode, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+	roots := []*types.CategoryNode{}
+	for _, node := range nodes {
+		switch {
+		case node.ParentID == nil:
+			if rootID == 0 {
+				roots = append(roots, node)
+			}
+		case byID[*node.ParentID] != nil:
+			parent := byID[*node.ParentID]
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	if rootID == 0 {
+		return roots, nil
+	}
+	if root, ok := byID[rootID]; ok && root.Children != nil {
+		return root.Children, nil
+	}
+	return []*types.CategoryNode{}, nil
+}
+
+// GetCategoryBreadcrumbs returns the path from the root of the tree down to categoryID, named in
+// the first of langs each category is translated into.
+func (s *PostgresStore) GetCategoryBreadcrumbs(categoryID int, langs []string) ([]types.CategoryBreadcrumb, error) {
+	query := `
+    WITH RECURSIVE path AS (
+        SELECT id, name, parent_id, 0 AS depth FROM category WHERE id = $1
+        UNION ALL
+        SELECT c.id, c.name, c.parent_id, p.depth + 1
+        FROM category c
+        JOIN path p ON c.id = p.parent_id
+    )
+    SELECT id, name FROM path ORDER BY depth DESC`
+
+	rows, err := s.reader(readCatalog).Query(query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying breadcrumbs of category %d: %w", categoryID, err)
+	}
+	defer rows.Close()
+
+	breadcrumbs := []types.CategoryBreadcrumb{}
+	for rows.Next() {
+		var crumb types.CategoryBreadcrumb
+		if err := rows.Scan(&crumb.ID, &crumb.Name); err != nil {
+			return nil, fmt.Errorf("error scanning breadcrumb: %w", err)
+		}
+		breadcrumbs = append(breadcrumbs, crumb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating breadcrumbs: %w", err)
+	}
+	if len(breadcrumbs) == 0 {
+		return nil, fmt.Errorf("category with id = [%d] not found", categoryID)
+	}
+
+	texts := make([]catalogText, len(breadcrumbs))
+	for i := range breadcrumbs {
+		texts[i] = catalogText{types.TranslationEntityCategory, breadcrumbs[i].ID, "name", &breadcrumbs[i].Name}
+	}
+	if err := s.translate(langs, texts); err != nil {
+		return nil, err
+	}
+	return breadcrumbs, nil
+}
+
+// lockCategoryTree serializes structural changes, so two concurrent moves cannot form a cycle.
+func lockCategoryTree(tx *sql.Tx) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('category_tree'))`); err != nil {
+		return fmt.Errorf("error locking category tree: %w", err)
+	}
+	return nil
+}
+
+// childCategoryIDs returns the children of parentID (nil for the roots) in sibling order.
+func childCategoryIDs(tx *sql.Tx, parentID *int) ([]int, error) {
+	rows, err := tx.Query(`SELECT id FROM category WHERE parent_id IS NOT DISTINCT FROM $1 ORDER BY sort_order, id`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying child categories: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning child category: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// setSiblingOrder numbers ids 1..n in the given order.
+func setSiblingOrder(tx *sql.Tx, ids []int) error {
+	query := `
+    UPDATE category c
+    SET sort_order = o.ord
+    FROM unnest($1::int[]) WITH ORDINALITY AS o(id, ord)
+    WHERE c.id = o.id`
+
+	if _, err := tx.Exec(query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("error updating sibling order: %w", err)
+	}
+	return nil
+}
+
+// MoveCategory re-parents a category. Its descendants only reference it, so the whole subtree
+// moves with the single parent_id update.
+func (s *PostgresStore) MoveCategory(move types.CategoryMove) (*types.CategoryNode, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	node, err := moveCategory(tx, move)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateCategories()
+	return node, nil
+}
+
+// moveCategory re-parents a category inside tx, so a category can be created and placed at once.
+func moveCategory(tx *sql.Tx, move types.CategoryMove) (*types.CategoryNode, error) {
+	if err := lockCategoryTree(tx); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM category WHERE id = $1)`, move.CategoryID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking category %d: %w", move.CategoryID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("category with id = [%d] not found", move.CategoryID)
+	}
+
+	if move.ParentID != nil {
+		cycleQuery := `
+        WITH RECURSIVE subtree AS (
+            SELECT id FROM category WHERE id = $1
+            UNION ALL
+            SELECT c.id FROM category c JOIN subtree st ON c.parent_id = st.id
+        )
+        SELECT EXISTS (SELECT 1 FROM category WHERE id = $2),
+               EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+
+		var parentExists, insideSubtree bool
+		if err := tx.QueryRow(cycleQuery, move.CategoryID, *move.ParentID).Scan(&parentExists, &insideSubtree); err != nil {
+			return nil, fmt.Errorf("error checking new parent of category %d: %w", move.CategoryID, err)
+		}
+		if !parentExists {
+			return nil, fmt.Errorf("category with id = [%d] not found", *move.ParentID)
+		}
+		if insideSubtree {
+			return nil, fmt.Errorf("cannot move category %d below itself or one of its descendants", move.CategoryID)
+		}
+	}
+
+	siblings, err := childCategoryIDs(tx, move.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	ordered := make([]int, 0, len(siblings)+1)
+	for _, id := range siblings {
+		if id != move.CategoryID {
+			ordered = append(ordered, id)
+		}
+	}
+	position := move.Position
+	if position <= 0 || position > len(ordered)+1 {
+		position = len(ordered) + 1
+	}
+	ordered = append(ordered[:position-1], append([]int{move.CategoryID}, ordered[position-1:]...)...)
+
+	if _, err := tx.Exec(`UPDATE category SET parent_id = $2 WHERE id = $1`, move.CategoryID, move.ParentID); err != nil {
+		return nil, fmt.Errorf("error moving category %d: %w", move.CategoryID, err)
+	}
+	if err := setSiblingOrder(tx, ordered); err != nil {
+		return nil, err
+	}
+
+	node := &types.CategoryNode{}
+	err = tx.QueryRow(`
+        SELECT c.id, c.name, COALESCE(ci.image, ''), c.promotion, c.parent_id, c.sort_order, c.active_from, c.active_until
+        FROM category c
+        LEFT JOIN category_image ci ON ci.category_id = c.id AND ci.position = 1
+        WHERE c.id = $1`, move.CategoryID).
+		Scan(&node.ID, &node.Name, &node.Image, &node.Promotion, &node.ParentID, &node.SortOrder, &node.ActiveFrom, &node.ActiveUntil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading moved category %d: %w", move.CategoryID, err)
+	}
+	return node, nil
+}
+
+// ReorderCategories sets the order of all children of a parent at once.
+func (s *PostgresStore) ReorderCategories(reorder types.CategoryReorder) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategoryTree(tx); err != nil {
+		return err
+	}
+
+	children, err := childCategoryIDs(tx, reorder.ParentID)
+	if err != nil {
+		return err
+	}
+	current := make(map[int]bool, len(children))
+	for _, id := range children {
+		current[id] = true
+	}
+	seen := make(map[int]bool, len(reorder.CategoryIDs))
+	for _, id := range reorder.CategoryIDs {
+		if !current[id] {
+			return fmt.Errorf("category %d is not a child of the given parent", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("category %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(children) {
+		return fmt.Errorf("category_ids must list all %d children of the parent, got %d", len(children), len(seen))
+	}
+
+	if err := setSiblingOrder(tx, reorder.CategoryIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.catalog.invalidateCategories()
+	return nil
+}
+
+func (s *PostgresStore) SetCategoryStoreVisibility(visibility types.CategoryStoreVisibility) error {
+	query := `
+    INSERT INTO category_store (category_id, store_id, enabled)
+    VALUES ($1, $2, $3)
+    ON CONFLICT (category_id, store_id) DO UPDATE SET enabled = EXCLUDED.enabled`
+
+	if _, err := s.db.Exec(query, visibility.CategoryID, visibility.StoreID, visibility.Enabled); err != nil {
+		return fmt.Errorf("error setting visibility of category %d in store %d: %w", visibility.CategoryID, visibility.StoreID, err)
+	}
+	s.catalog.invalidateCategories()
+	return nil
+}
+
+// SetCategorySchedule sets the window in which a category is shown. Cached reads pick up a
+// window that opens or closes within catalogCacheTTL.
+func (s *PostgresStore) SetCategorySchedule(schedule types.CategorySchedule) error {
+	if schedule.ActiveFrom != nil && schedule.ActiveUntil != nil && !schedule.ActiveFrom.Before(*schedule.ActiveUntil) {
+		return fmt.Errorf("active_from must be before active_until")
+	}
+
+	result, err := s.db.Exec(`UPDATE category SET active_from = $2, active_until = $3 WHERE id = $1`,
+		schedule.CategoryID, schedule.ActiveFrom, schedule.ActiveUntil)
+	if err != nil {
+		return fmt.Errorf("error scheduling category %d: %w", schedule.CategoryID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("category with id = [%d] not found", schedule.CategoryID)
+	}
+	s.catalog.invalidateCategories()
+	return nil
+}
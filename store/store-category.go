@@ -3,13 +3,8 @@ package store
 import (
 	"database/sql"
 	"fmt"
-	"log"
-	"math/rand"
-	"time"
 
 	"github.com/girithc/pronto-go/types"
-
-	"github.com/lib/pq"
 )
 
 func (s *PostgresStore) Create_Category_Table(tx *sql.Tx) error {
@@ -54,7 +49,9 @@ func (s *PostgresStore) Create_Category_Image_Table(tx *sql.Tx) error {
 	return err
 }
 
-func (s *PostgresStore) Create_Category(hlc *types.Category) (*types.Category, error) {
+// Create_Category creates a category, or returns the one of the same name, and places it under
+// parentID when set. Both happen in one transaction, so a failed move leaves no stray root category.
+func (s *PostgresStore) Create_Category(hlc *types.Category, parentID *int) (*types.Category, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, err
@@ -73,6 +70,16 @@ func (s *PostgresStore) Create_Category(hlc *types.Category) (*types.Category, e
 		if err != nil {
 			return nil, err
 		}
+		if parentID == nil {
+			return existingCat, nil
+		}
+		if _, err := moveCategory(tx, types.CategoryMove{CategoryID: existingCat.ID, ParentID: parentID}); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		s.catalog.invalidateCategories()
 		return existingCat, nil
 	} else if err != sql.ErrNoRows {
 		return nil, err
@@ -101,24 +108,32 @@ func (s *PostgresStore) Create_Category(hlc *types.Category) (*types.Category, e
 		return nil, err
 	}
 
+	if parentID != nil {
+		if _, err := moveCategory(tx, types.CategoryMove{CategoryID: result.ID, ParentID: parentID}); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
+	s.catalog.invalidateCategories()
 
 	return result, nil
 }
 
-func (s *PostgresStore) Get_Categories(promotion bool) ([]*types.Category, error) {
+func (s *PostgresStore) Get_Categories(promotion bool, storeID int) ([]*types.Category, error) {
 	query := `
         SELECT c.id, c.name, c.promotion, ci.image, COALESCE(ci.position, 0) AS position, c.created_at, COALESCE(c.created_by, 0) AS created_by
         FROM category c
         LEFT JOIN category_image ci ON c.id = ci.category_id AND ci.position = 1
-		WHERE c.promotion = $1
+		WHERE c.parent_id IS NOT NULL AND c.promotion = $2
+		AND ` + categoryVisibleClause + `
     `
 
-	rows, err := s.db.Query(query, promotion)
+	rows, err := s.reader(readCatalog).Query(query, storeID, promotion)
 	if err != nil {
 		return nil, err
 	}
@@ -169,43 +184,19 @@ func (s *PostgresStore) Get_Categories(promotion bool) ([]*types.Category, error
 	return categories, nil
 }
 
-func (s *PostgresStore) Get_Category_By_Parent_ID(id int) ([]*types.Update_Category, error) {
-	rand.Seed(time.Now().UnixNano()) // Seed the random number generator
-
-	rows, err := s.db.Query("SELECT category_id FROM category_higher_level_mapping WHERE higher_level_category_id = $1", id)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer rows.Close()
-
-	var childIDs []int
-	for rows.Next() {
-		var childID int
-		if err := rows.Scan(&childID); err != nil {
-			log.Fatal(err)
-		}
-		childIDs = append(childIDs, childID)
-	}
-
-	if err := rows.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	// Simple in-place shuffle using Fisher-Yates algorithm
-	for i := range childIDs {
-		j := rand.Intn(i + 1)
-		childIDs[i], childIDs[j] = childIDs[j], childIDs[i]
-	}
-
-	categoryQuery := `
-    SELECT c.name, c.id, ci.image 
-    FROM category c 
-    LEFT JOIN category_image ci ON c.id = ci.category_id AND ci.position = 1 
-    WHERE c.id = ANY($1::integer[])`
+// Get_Category_By_Parent_ID returns the children of a category visible in storeID, in sibling order,
+// named in the first of langs they are translated into.
+func (s *PostgresStore) Get_Category_By_Parent_ID(id int, storeID int, langs []string) ([]*types.Update_Category, error) {
+	query := `
+    SELECT c.name, c.id, ci.image
+    FROM category c
+    LEFT JOIN category_image ci ON c.id = ci.category_id AND ci.position = 1
+    WHERE c.parent_id = $2 AND ` + categoryVisibleClause + `
+    ORDER BY c.sort_order, c.id`
 
-	rows, err = s.db.Query(categoryQuery, pq.Array(childIDs))
+	rows, err := s.reader(readCatalog).Query(query, storeID, id)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -229,14 +220,22 @@ func (s *PostgresStore) Get_Category_By_Parent_ID(id int) ([]*types.Update_Categ
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	texts := make([]catalogText, 0, len(categories))
+	for _, category := range categories {
+		texts = append(texts, catalogText{types.TranslationEntityCategory, category.ID, "name", &category.Name})
+	}
+	if err := s.translate(langs, texts); err != nil {
+		return nil, err
 	}
 
 	return categories, nil
 }
 
 func (s *PostgresStore) Get_Category_By_ID(id int) (*types.Category, error) {
-	row, err := s.db.Query("select * from category where id = $1", id)
+	row, err := s.db.Query("select id, name, created_at, created_by from category where id = $1", id)
 	if err != nil {
 		return nil, err
 	}
@@ -263,6 +262,8 @@ func (s *PostgresStore) Update_Category(hlc *types.Update_Category) (*types.Upda
 		return nil, err
 	}
 
+	s.catalog.invalidateCategories()
+
 	categories := []*types.Update_Category{}
 
 	for rows.Next() {
@@ -276,20 +277,42 @@ func (s *PostgresStore) Update_Category(hlc *types.Update_Category) (*types.Upda
 	return categories[0], nil
 }
 
+// Delete_Category removes a category and hands its children to its own parent.
 func (s *PostgresStore) Delete_Category(id int) error {
-	_, err := s.db.Exec("DELETE FROM category_higher_level_mapping WHERE category_id = $1", id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategoryTree(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE category SET parent_id = (SELECT parent_id FROM category WHERE id = $1) WHERE parent_id = $1", id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM category_higher_level_mapping WHERE category_id = $1", id)
 	if err != nil {
 		return err
 	}
 	// First, delete (or update) related rows in category_image
-	_, err = s.db.Exec("DELETE FROM category_image WHERE category_id = $1", id)
+	_, err = tx.Exec("DELETE FROM category_image WHERE category_id = $1", id)
 	if err != nil {
 		return err
 	}
 
 	// Now, delete the row from category
-	_, err = s.db.Exec("DELETE FROM category WHERE id = $1", id)
-	return err
+	_, err = tx.Exec("DELETE FROM category WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.catalog.invalidateCategories()
+	return nil
 }
 
 func scan_Into_Category(rows *sql.Rows) (*types.Category, error) {
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"fmt"
+	"log"
 	"math/big"
 
 	//"math/rand" // Ensure this import is included
@@ -35,8 +36,18 @@ func (s *PostgresStore) getCartItems(cartId int) ([]*types.Checkout_Cart_Item, e
 	return cartItems, nil
 }
 
+// lockItems moves the cart's quantities from stock to locked. Bundles lock their components. The
+// caller invalidates the cart's listings with invalidateCartStock once tx commits.
 func (s *PostgresStore) lockItems(cartItems []*types.Checkout_Cart_Item, tx *sql.Tx) (bool, error) {
-	for _, checkout_cart_item := range cartItems {
+	lines, touched, err := stockLines(tx, cartItems)
+	if err != nil {
+		return false, err
+	}
+	if err := lockStockRows(tx, touched); err != nil {
+		return false, err
+	}
+
+	for _, checkout_cart_item := range lines {
 		fmt.Printf("Item ID: %d Quantity: %d \n", checkout_cart_item.Item_Id, checkout_cart_item.Quantity)
 
 		res, err := tx.Exec(`UPDATE item_store SET stock_quantity = stock_quantity - $1, locked_quantity = locked_quantity + $1 WHERE item_id = $2 AND stock_quantity >= $1`, checkout_cart_item.Quantity, checkout_cart_item.Item_Id)
@@ -56,6 +67,22 @@ func (s *PostgresStore) lockItems(cartItems []*types.Checkout_Cart_Item, tx *sql
 	return true, nil
 }
 
+// invalidateCartStock drops the cached listings of a cart's items and bundle components. Call it
+// once the transaction that moved their stock has committed, so a read in between cannot cache
+// the old stock again. If the items cannot be read, every item listing goes.
+func (s *PostgresStore) invalidateCartStock(cartID int) {
+	cartItems, err := s.getCartItems(cartID)
+	if err == nil {
+		var touched []int
+		if _, touched, err = stockLines(s.db, cartItems); err == nil {
+			s.catalog.invalidateStock(0, touched...)
+			return
+		}
+	}
+	log.Printf("error reading items of cart %d, dropping all cached listings: %v", cartID, err)
+	s.catalog.invalidateAllItems()
+}
+
 func (s *PostgresStore) cartLockStock(cartId int, tx *sql.Tx) (string, error) {
 	// Insert a record into the cart_lock table
 	lockType := "lock-stock"
@@ -177,6 +204,8 @@ func (s *PostgresStore) cartLockUpdate(tx *sql.Tx, cartId int, cash bool, sign s
 	}
 }
 
+// CreateOrder turns a paid cart into a sales order and releases its locked quantities. The caller
+// invalidates the cart's listings with invalidateCartStock once tx commits.
 func (s *PostgresStore) CreateOrder(tx *sql.Tx, cart_id int, paymentType string, merchantTransactionID string) (bool, error) {
 	var storeID, customerID sql.NullInt64
 	var orderType string // Variable to store order_type from the shopping cart
@@ -270,8 +299,19 @@ func (s *PostgresStore) CreateOrder(tx *sql.Tx, cart_id int, paymentType string,
 		return false, fmt.Errorf("error creating sales_order for cart %d: %s", cart_id, err)
 	}
 
-	// Process each cart item, reducing the locked_quantity for each item
-	for _, item := range cartItems {
+	if err = writeOrderOutboxEvent(tx, OrderEventCreated, orderId); err != nil {
+		return false, err
+	}
+
+	// Release what lockItems locked: bundles locked their components, so expand them the same way
+	lines, touched, err := stockLines(tx, cartItems)
+	if err != nil {
+		return false, err
+	}
+	if err = lockStockRows(tx, touched); err != nil {
+		return false, err
+	}
+	for _, item := range lines {
 		_, err = tx.Exec(`UPDATE item_store SET locked_quantity = locked_quantity - $1 WHERE item_id = $2 AND locked_quantity >= $1`, item.Quantity, item.Item_Id)
 		if err != nil {
 			return false, fmt.Errorf("error updating stock for item %d: %s", item.Item_Id, err)
@@ -332,6 +372,14 @@ func (s *PostgresStore) LockStock(cart_id int) (IsLockStock, error) {
 		}
 	}()
 
+	// Limits may have changed, or other orders been placed today, since the items were added
+	err = checkPurchaseLimits(tx, cart_id, 0)
+	if err != nil {
+		resp.Lock = false
+		resp.Sign = ""
+		return resp, err
+	}
+
 	areItemsLocked, err := s.lockItems(cartItems, tx)
 	if err != nil {
 		resp.Lock = areItemsLocked
@@ -362,6 +410,7 @@ func (s *PostgresStore) LockStock(cart_id int) (IsLockStock, error) {
 		resp.Sign = ""
 		return resp, fmt.Errorf("error in committing transaction: %v", err)
 	}
+	s.invalidateCartStock(cart_id)
 
 	resp.Lock = true
 	resp.Sign = sign
@@ -468,6 +517,7 @@ func (s *PostgresStore) PayStockCash(cart_id int, sign string, merchantTransacti
 	if err != nil {
 		return IsPaid{false}, fmt.Errorf("error committing transaction: %s", err)
 	}
+	s.invalidateCartStock(cart_id)
 
 	_, err = s.sendOrderNotifToPacker()
 	if err != nil {
@@ -481,20 +531,6 @@ type IsPaid struct {
 	IsPaid bool `json:"isPaid"`
 }
 
-func (s *PostgresStore) MakeQuantitiesPermanent(cart_id int) error {
-	_, err := s.db.Exec(`WITH quantities AS (
-        SELECT item_id, quantity 
-        FROM cart_item 
-        WHERE cart_id = $1
-    )
-    UPDATE item_store 
-    SET locked_quantity = locked_quantity - quantities.quantity
-    FROM quantities 
-    WHERE item_store.id = quantities.item_id;
-    `, cart_id)
-	return err
-}
-
 func (s *PostgresStore) PrintItemStoreRecords(state string) error {
 	fmt.Printf("When %s", state)
 	rows, err := s.db.Query(`SELECT id, item_id, stock_quantity, locked_quantity FROM item_store`)
@@ -380,6 +380,39 @@ func (s *PostgresStore) Get_All_Customers() ([]*types.Customer, error) {
 	return customers, nil
 }
 
+// ListCustomers is the paginated form of Get_All_Customers. From/To filter on signup time.
+func (s *PostgresStore) ListCustomers(params types.ListParams) (*types.Page[*types.Customer], error) {
+	q := &listQuery{
+		columns: "c.id, c.name, c.phone, c.address, c.merchant_user_id, c.created_at",
+		from:    "customer c",
+		sorts: map[string]string{
+			"id":         "c.id",
+			"name":       "LOWER(c.name)",
+			"created_at": "c.created_at",
+		},
+		defaultSort: "id",
+		keys:        []string{"c.id"},
+	}
+
+	if params.From != nil {
+		q.filter("c.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		q.filter("c.created_at < ?", *params.To)
+	}
+
+	return runListQuery(s.db, q, params, func(rows *sql.Rows, cursorDest []interface{}) (*types.Customer, error) {
+		customer := new(types.Customer)
+		var merchantUserID sql.NullString
+		dest := []interface{}{&customer.ID, &customer.Name, &customer.Phone, &customer.Address, &merchantUserID, &customer.Created_At}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return nil, err
+		}
+		customer.MerchantUserID = merchantUserID.String
+		return customer, nil
+	})
+}
+
 func (s *PostgresStore) GetCustomerByPhone(phone string, fcm string) (*types.Customer_Login, error) {
 	fmt.Println("Started Get_Customer_By_Phone")
 
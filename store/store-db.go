@@ -0,0 +1,191 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Read domains used for replica routing. A write to a domain pins its reads to the primary for
+// DB_READ_YOUR_WRITES_WINDOW so a client never reads an older version than it just wrote.
+const (
+	readCatalog = "catalog"
+	readOrders  = "orders"
+	readExport  = "export"
+)
+
+// poolConfig holds the pool size and timeout settings of one connection pool.
+type poolConfig struct {
+	maxOpenConns     int
+	maxIdleConns     int
+	connMaxLifetime  time.Duration
+	connMaxIdleTime  time.Duration
+	connectTimeout   time.Duration
+	statementTimeout time.Duration
+}
+
+// loadPoolConfig reads <prefix>MAX_OPEN_CONNS, <prefix>MAX_IDLE_CONNS, <prefix>CONN_MAX_LIFETIME,
+// <prefix>CONN_MAX_IDLE_TIME, <prefix>CONNECT_TIMEOUT and <prefix>STATEMENT_TIMEOUT. Durations use
+// Go syntax such as 30s or 5m.
+func loadPoolConfig(prefix string) poolConfig {
+	return poolConfig{
+		maxOpenConns:     envInt(prefix+"MAX_OPEN_CONNS", 25),
+		maxIdleConns:     envInt(prefix+"MAX_IDLE_CONNS", 10),
+		connMaxLifetime:  envDuration(prefix+"CONN_MAX_LIFETIME", 30*time.Minute),
+		connMaxIdleTime:  envDuration(prefix+"CONN_MAX_IDLE_TIME", 5*time.Minute),
+		connectTimeout:   envDuration(prefix+"CONNECT_TIMEOUT", 10*time.Second),
+		statementTimeout: envDuration(prefix+"STATEMENT_TIMEOUT", 0),
+	}
+}
+
+func envInt(name string, fallback int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("ignoring invalid %s=%q: %v", name, v, err)
+		return fallback
+	}
+	return n
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("ignoring invalid %s=%q: %v", name, v, err)
+		return fallback
+	}
+	return d
+}
+
+// openPool opens a pool for connStr with the configured limits. Timeouts are passed as
+// connection parameters so they apply to every connection of the pool.
+func openPool(connStr string, cfg poolConfig) (*sql.DB, error) {
+	params := map[string]string{}
+	if cfg.connectTimeout > 0 {
+		params["connect_timeout"] = strconv.Itoa(int(cfg.connectTimeout.Seconds()))
+	}
+	if cfg.statementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(cfg.statementTimeout.Milliseconds(), 10)
+	}
+
+	db, err := sql.Open("postgres", withConnParams(connStr, params))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.maxOpenConns)
+	db.SetMaxIdleConns(cfg.maxIdleConns)
+	db.SetConnMaxLifetime(cfg.connMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.connMaxIdleTime)
+	return db, nil
+}
+
+// withConnParams adds params to a key/value or URL connection string without overriding explicit values.
+func withConnParams(connStr string, params map[string]string) string {
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		u, err := url.Parse(connStr)
+		if err != nil {
+			return connStr
+		}
+		q := u.Query()
+		for k, v := range params {
+			if q.Get(k) == "" {
+				q.Set(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	for k, v := range params {
+		if !strings.Contains(connStr, k+"=") {
+			connStr = strings.TrimSpace(connStr + " " + k + "=" + v)
+		}
+	}
+	return connStr
+}
+
+// openReplica opens DB_REPLICA_URL, or returns primary when no replica is configured.
+func openReplica(primary *sql.DB) *sql.DB {
+	replicaConnStr := os.Getenv("DB_REPLICA_URL")
+	if replicaConnStr == "" {
+		return primary
+	}
+	replica, err := openPool(replicaConnStr, loadPoolConfig("DB_REPLICA_"))
+	if err != nil {
+		log.Printf("replica unavailable, reading from primary: %v", err)
+		return primary
+	}
+	return replica
+}
+
+// readRouter remembers the last write to each read domain.
+type readRouter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	lastWrite map[string]time.Time
+}
+
+func newReadRouter() *readRouter {
+	return &readRouter{
+		window:    envDuration("DB_READ_YOUR_WRITES_WINDOW", 2*time.Second),
+		lastWrite: make(map[string]time.Time),
+	}
+}
+
+func (r *readRouter) markWrite(domains ...string) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	for _, domain := range domains {
+		r.lastWrite[domain] = now
+	}
+	r.mu.Unlock()
+}
+
+func (r *readRouter) recentlyWritten(domain string) bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Since(r.lastWrite[domain]) < r.window
+}
+
+// reader returns the pool for a read in domain: the replica (db2), unless the domain was written
+// recently. Checkout, locks and payments keep using s.db, the primary.
+func (s *PostgresStore) reader(domain string) *sql.DB {
+	if s.db2 == nil || s.db2 == s.db || s.router.recentlyWritten(domain) {
+		return s.db
+	}
+	return s.db2
+}
+
+func (s *PostgresStore) Close() error {
+	if s.db2 != nil && s.db2 != s.db {
+		if err := s.db2.Close(); err != nil {
+			return fmt.Errorf("error closing replica pool: %w", err)
+		}
+	}
+	return s.db.Close()
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -646,10 +646,15 @@ func (s *PostgresStore) DeliveryPartnerDispatchOrder(phone string, order_id int)
 	}
 
 	// Update the order_status to 'dispatched' in sales_order
-	_, err = tx.Exec("UPDATE sales_order SET order_status = 'dispatched' WHERE id = $1 AND delivery_partner_id = $2 AND order_status != 'completed'", order_id, deliveryPartnerIDFromDB)
+	dispatched, err := tx.Exec("UPDATE sales_order SET order_status = 'dispatched' WHERE id = $1 AND delivery_partner_id = $2 AND order_status != 'completed'", order_id, deliveryPartnerIDFromDB)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update order status to 'dispatched' for order ID %d: %w", order_id, err)
 	}
+	if n, _ := dispatched.RowsAffected(); n > 0 {
+		if err = writeOrderOutboxEvent(tx, OrderEventDispatched, order_id); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update the pickup_time and set active to false in packer_shelf for the associated sales_order_id
 	_, err = tx.Exec("UPDATE packer_shelf SET pickup_time = CURRENT_TIMESTAMP, active = false WHERE sales_order_id = $1", order_id)
@@ -685,7 +690,7 @@ func (s *PostgresStore) PackerDispatchOrderHistory(storeId int) ([]DispatchOrder
     ORDER BY ps.pickup_time DESC
     `
 
-	rows, err := s.db.Query(query, storeId)
+	rows, err := s.reader(readOrders).Query(query, storeId)
 	if err != nil {
 		return nil, fmt.Errorf("error querying dispatch order history: %w", err)
 	}
@@ -792,7 +797,7 @@ func (s *PostgresStore) DeliveryPartnerCompleteOrderDelivery(phone string, order
 	}
 
 	// Update the order_status to 'completed' in sales_order
-	_, err = tx.Exec(`
+	completed, err := tx.Exec(`
         UPDATE sales_order
         SET order_status = 'completed'
         WHERE id = $1 AND delivery_partner_id = $2 AND order_status != 'completed';
@@ -800,6 +805,11 @@ func (s *PostgresStore) DeliveryPartnerCompleteOrderDelivery(phone string, order
 	if err != nil {
 		return nil, err
 	}
+	if n, _ := completed.RowsAffected(); n > 0 {
+		if err = writeOrderOutboxEvent(tx, OrderEventCompleted, order_id); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if a delivery_order record already exists
 	var exists bool
@@ -957,6 +967,50 @@ func (s *PostgresStore) Get_All_Delivery_Partners() ([]*types.Delivery_Partner,
 	return partners, nil
 }
 
+// ListDeliveryPartners is the paginated form of Get_All_Delivery_Partners.
+// Status is either "available" or "unavailable".
+func (s *PostgresStore) ListDeliveryPartners(params types.ListParams) (*types.Page[*types.Delivery_Partner], error) {
+	q := &listQuery{
+		columns: "dp.id, dp.name, COALESCE(dp.fcm, ''), dp.store_id, dp.phone, dp.address, dp.created_at, COALESCE(dp.available, false)",
+		from:    "delivery_partner dp",
+		sorts: map[string]string{
+			"id":         "dp.id",
+			"name":       "LOWER(dp.name)",
+			"created_at": "dp.created_at",
+		},
+		defaultSort: "id",
+		keys:        []string{"dp.id"},
+	}
+
+	if params.StoreID != 0 {
+		q.filter("dp.store_id = ?", params.StoreID)
+	}
+	switch params.Status {
+	case "":
+	case "available":
+		q.filter("dp.available = ?", true)
+	case "unavailable":
+		q.filter("dp.available = ?", false)
+	default:
+		return nil, fmt.Errorf("unsupported status %q", params.Status)
+	}
+	if params.From != nil {
+		q.filter("dp.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		q.filter("dp.created_at < ?", *params.To)
+	}
+
+	return runListQuery(s.db, q, params, func(rows *sql.Rows, cursorDest []interface{}) (*types.Delivery_Partner, error) {
+		partner := &types.Delivery_Partner{}
+		dest := []interface{}{&partner.ID, &partner.Name, &partner.FCM_Token, &partner.Store_ID, &partner.Phone, &partner.Address, &partner.Created_At, &partner.Available}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return nil, err
+		}
+		return partner, nil
+	})
+}
+
 // 3. Create a function to retrieve a delivery partner by phone
 func (s *PostgresStore) Get_Delivery_Partner_By_Phone(phone string) (*types.Delivery_Partner, error) {
 	rows, err := s.db.Query("SELECT id, name, fcm_token, store_id, phone, address, created_at, available FROM delivery_partner where phone = $1", phone)
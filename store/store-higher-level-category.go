@@ -50,14 +50,19 @@ func (s *PostgresStore) CreateHigherLevelCategoryImageTable(tx *sql.Tx) error {
 	return err
 }
 
-func (s *PostgresStore) Get_Higher_Level_Categories() ([]*types.Higher_Level_Category, error) {
+// Get_Higher_Level_Categories returns the roots of the category tree that group other categories
+// and are visible in storeID.
+func (s *PostgresStore) Get_Higher_Level_Categories(storeID int) ([]*types.Higher_Level_Category, error) {
 	query := `
-    SELECT c.id, c.name, ci.image, ci.position, c.created_at, c.created_by 
-    FROM higher_level_category c
-    LEFT JOIN higher_level_category_image ci ON c.id = ci.higher_level_category_id AND ci.position = 1
-    ORDER BY c.id ASC` // Added ORDER BY clause here
-
-	rows, err := s.db.Query(query)
+    SELECT c.id, c.name, ci.image, ci.position, c.created_at, c.created_by
+    FROM category c
+    LEFT JOIN category_image ci ON c.id = ci.category_id AND ci.position = 1
+    WHERE c.parent_id IS NULL
+    AND EXISTS (SELECT 1 FROM category ch WHERE ch.parent_id = c.id)
+    AND ` + categoryVisibleClause + `
+    ORDER BY c.sort_order, c.id`
+
+	rows, err := s.reader(readCatalog).Query(query, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +112,7 @@ func (s *PostgresStore) Get_Higher_Level_Categories() ([]*types.Higher_Level_Cat
 }
 
 func (s *PostgresStore) Get_Higher_Level_Category_By_ID(id int) (*types.Higher_Level_Category, error) {
-	row, err := s.db.Query("select * from higher_level_category where id = $1", id)
+	row, err := s.db.Query("select id, name, created_at, COALESCE(created_by, 0) from category where id = $1 and parent_id is null", id)
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +125,9 @@ func (s *PostgresStore) Get_Higher_Level_Category_By_ID(id int) (*types.Higher_L
 }
 
 func (s *PostgresStore) Update_Higher_Level_Category(hlc *types.Update_Higher_Level_Category) (*types.Update_Higher_Level_Category, error) {
-	query := `update higher_level_category
+	query := `update category
 	set name = $1
-	where id = $2 
+	where id = $2 and parent_id is null
 	returning name, id`
 
 	rows, err := s.db.Query(
@@ -134,6 +139,8 @@ func (s *PostgresStore) Update_Higher_Level_Category(hlc *types.Update_Higher_Le
 		return nil, err
 	}
 
+	s.catalog.invalidateCategories()
+
 	higher_level_categories := []*types.Update_Higher_Level_Category{}
 
 	for rows.Next() {
@@ -147,18 +154,12 @@ func (s *PostgresStore) Update_Higher_Level_Category(hlc *types.Update_Higher_Le
 	return higher_level_categories[0], nil
 }
 
+// Delete_Higher_Level_Category removes a top level category; its children become top level categories.
 func (s *PostgresStore) Delete_Higher_Level_Category(id int) error {
-	_, err := s.db.Exec("DELETE FROM category_higher_level_mapping WHERE higher_level_category_id = $1", id)
-	if err != nil {
-		return err
-	}
-
-	_, err = s.db.Exec("DELETE FROM higher_level_category_image WHERE higher_level_category_id = $1", id)
-	if err != nil {
+	if _, err := s.Get_Higher_Level_Category_By_ID(id); err != nil {
 		return err
 	}
-	_, err = s.db.Exec("DELETE FROM higher_level_category WHERE id = $1", id)
-	return err
+	return s.Delete_Category(id)
 }
 
 func scan_Into_Higher_Level_Category(rows *sql.Rows) (*types.Higher_Level_Category, error) {
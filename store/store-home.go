@@ -0,0 +1,800 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+const (
+	homeDefaultMaxItems = 12
+	homeMaxItems        = 50
+	// homeCollectionPageSize bounds the full listing behind a collection's see-all link.
+	homeCollectionPageSize = 100
+)
+
+func (s *PostgresStore) CreateHomeTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS home_section (
+        id SERIAL PRIMARY KEY,
+        store_id INT REFERENCES store(id) ON DELETE CASCADE,
+        kind TEXT NOT NULL CHECK (kind IN ('banners', 'collection', 'categories')),
+        title TEXT NOT NULL DEFAULT '',
+        position INT NOT NULL DEFAULT 0,
+        enabled BOOLEAN NOT NULL DEFAULT TRUE,
+        active_from TIMESTAMP,
+        active_until TIMESTAMP,
+        rule TEXT CHECK (rule IN ('scheme_discount', 'store_discount', 'new_arrivals', 'category', 'brand')),
+        rule_param INT NOT NULL DEFAULT 0,
+        max_items INT NOT NULL DEFAULT 12 CHECK (max_items > 0),
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CHECK (active_from IS NULL OR active_until IS NULL OR active_from < active_until),
+        CHECK (rule IS NULL OR kind = 'collection')
+    );
+
+    CREATE TABLE IF NOT EXISTS home_section_store (
+        section_id INT NOT NULL REFERENCES home_section(id) ON DELETE CASCADE,
+        store_id INT NOT NULL REFERENCES store(id) ON DELETE CASCADE,
+        position INT NOT NULL,
+        PRIMARY KEY (section_id, store_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS home_banner (
+        id SERIAL PRIMARY KEY,
+        section_id INT NOT NULL REFERENCES home_section(id) ON DELETE CASCADE,
+        title TEXT NOT NULL DEFAULT '',
+        image_url TEXT NOT NULL,
+        link_type TEXT NOT NULL DEFAULT 'none' CHECK (link_type IN ('none', 'category', 'item', 'brand', 'collection', 'search', 'url')),
+        link_target TEXT NOT NULL DEFAULT '',
+        position INT NOT NULL DEFAULT 0,
+        active_from TIMESTAMP,
+        active_until TIMESTAMP,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CHECK (active_from IS NULL OR active_until IS NULL OR active_from < active_until)
+    );
+
+    CREATE INDEX IF NOT EXISTS home_banner_section ON home_banner(section_id);
+
+    CREATE TABLE IF NOT EXISTS home_collection_item (
+        section_id INT NOT NULL REFERENCES home_section(id) ON DELETE CASCADE,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        position INT NOT NULL,
+        PRIMARY KEY (section_id, item_id)
+    );`
+
+	_, err := tx.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error creating home tables: %w", err)
+	}
+	return nil
+}
+
+// CachedHome returns the home screen of a store. Cached screens pick up a section or banner whose
+// window opens or closes within catalogCacheTTL.
+func (s *PostgresStore) CachedHome(storeID int, langs []string) (*types.HomeScreen, string, error) {
+	key := fmt.Sprintf("%sstore=%d", catalogKeyHome, storeID) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.(*types.HomeScreen), entry.etag, nil
+	}
+	gen := s.catalog.generation()
+
+	home, err := s.GetHome(storeID)
+	if err != nil {
+		return nil, "", err
+	}
+	itemIDs := make(map[int]bool)
+	for i := range home.Sections {
+		section := &home.Sections[i]
+		if err := s.translateItemListing(langs, section.Items); err != nil {
+			return nil, "", err
+		}
+		if err := s.translateCategories(langs, section.Categories); err != nil {
+			return nil, "", err
+		}
+		for _, item := range section.Items {
+			itemIDs[item.ID] = true
+			for _, variant := range item.Variants {
+				itemIDs[variant.ItemID] = true
+			}
+		}
+	}
+	etag, err := catalogETag(home)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.catalog.put(key, gen, &catalogEntry{value: home, etag: etag, storeID: storeID, itemIDs: itemIDs})
+	return home, etag, nil
+}
+
+// homeSection is a live section as the home screen reads it.
+type homeSection struct {
+	id        int
+	kind      string
+	title     string
+	rule      string
+	ruleParam int
+	maxItems  int
+}
+
+// GetHome assembles the live sections of a store in display order: the store's own order first,
+// then the sections' positions. Sections with nothing to show are left out.
+func (s *PostgresStore) GetHome(storeID int) (*types.HomeScreen, error) {
+	tx, err := s.reader(readCatalog).Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT hs.id, hs.kind, hs.title, COALESCE(hs.rule, ''), hs.rule_param, hs.max_items
+        FROM home_section hs
+        LEFT JOIN home_section_store hss ON hss.section_id = hs.id AND hss.store_id = $1
+        WHERE hs.enabled
+        AND (hs.store_id IS NULL OR hs.store_id = $1)
+        AND (hs.active_from IS NULL OR hs.active_from <= CURRENT_TIMESTAMP)
+        AND (hs.active_until IS NULL OR hs.active_until > CURRENT_TIMESTAMP)
+        ORDER BY hss.position IS NULL, COALESCE(hss.position, hs.position), hs.id`, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying home sections of store %d: %w", storeID, err)
+	}
+	var sections []homeSection
+	var bannerSections []int
+	for rows.Next() {
+		var section homeSection
+		if err := rows.Scan(&section.id, &section.kind, &section.title, &section.rule, &section.ruleParam, &section.maxItems); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning home section: %w", err)
+		}
+		sections = append(sections, section)
+		if section.kind == types.HomeSectionBanners {
+			bannerSections = append(bannerSections, section.id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating home sections: %w", err)
+	}
+
+	banners, err := liveHomeBanners(tx, bannerSections)
+	if err != nil {
+		return nil, err
+	}
+
+	home := &types.HomeScreen{StoreID: storeID, Sections: []types.HomeSection{}}
+	for _, section := range sections {
+		out := types.HomeSection{ID: section.id, Kind: section.kind, Title: section.title}
+		switch section.kind {
+		case types.HomeSectionBanners:
+			out.Banners = banners[section.id]
+			if len(out.Banners) == 0 {
+				continue
+			}
+		case types.HomeSectionCollection:
+			out.Items, err = homeCollectionItems(tx, section, storeID, section.maxItems)
+			if err != nil {
+				return nil, err
+			}
+			if len(out.Items) == 0 {
+				continue
+			}
+			out.SeeAll = &types.HomeLink{Type: types.HomeLinkCollection, Target: strconv.Itoa(section.id)}
+		case types.HomeSectionCategories:
+			out.Categories, err = homeCategories(tx, storeID)
+			if err != nil {
+				return nil, err
+			}
+			if len(out.Categories) == 0 {
+				continue
+			}
+		}
+		home.Sections = append(home.Sections, out)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return home, nil
+}
+
+// homeCategories returns the promotion categories visible in storeID, in sibling order.
+func homeCategories(tx *sql.Tx, storeID int) ([]*types.Category, error) {
+	rows, err := tx.Query(`
+        SELECT c.id, c.name, c.promotion, COALESCE(ci.image, ''), COALESCE(ci.position, 0), c.created_at, COALESCE(c.created_by, 0)
+        FROM category c
+        LEFT JOIN category_image ci ON c.id = ci.category_id AND ci.position = 1
+        WHERE c.promotion AND `+categoryVisibleClause+`
+        ORDER BY c.sort_order, c.id`, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying promotion categories of store %d: %w", storeID, err)
+	}
+	defer rows.Close()
+
+	var categories []*types.Category
+	for rows.Next() {
+		category := &types.Category{}
+		if err := rows.Scan(&category.ID, &category.Name, &category.Promotion, &category.Image, &category.Position,
+			&category.Created_At, &category.Created_By); err != nil {
+			return nil, fmt.Errorf("error scanning promotion category: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// GetHomeCollection returns the full listing of a live collection at a store, the target of its
+// see-all link, translated into langs.
+func (s *PostgresStore) GetHomeCollection(sectionID int, storeID int, langs []string) (*types.HomeSection, error) {
+	tx, err := s.reader(readCatalog).Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	section := homeSection{id: sectionID}
+	err = tx.QueryRow(`
+        SELECT hs.kind, hs.title, COALESCE(hs.rule, ''), hs.rule_param
+        FROM home_section hs
+        WHERE hs.id = $1 AND hs.enabled
+        AND (hs.store_id IS NULL OR hs.store_id = $2)
+        AND (hs.active_from IS NULL OR hs.active_from <= CURRENT_TIMESTAMP)
+        AND (hs.active_until IS NULL OR hs.active_until > CURRENT_TIMESTAMP)`,
+		sectionID, storeID).Scan(&section.kind, &section.title, &section.rule, &section.ruleParam)
+	if err == sql.ErrNoRows || (err == nil && section.kind != types.HomeSectionCollection) {
+		return nil, fmt.Errorf("collection with id = [%d] not found", sectionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying collection %d: %w", sectionID, err)
+	}
+
+	out := &types.HomeSection{ID: section.id, Kind: section.kind, Title: section.title}
+	out.Items, err = homeCollectionItems(tx, section, storeID, homeCollectionPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	if err := s.translateItemListing(langs, out.Items); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// liveHomeBanners returns the banners of sectionIDs that are in their window, by section, in order.
+func liveHomeBanners(tx *sql.Tx, sectionIDs []int) (map[int][]types.HomeBanner, error) {
+	banners := make(map[int][]types.HomeBanner)
+	if len(sectionIDs) == 0 {
+		return banners, nil
+	}
+	rows, err := tx.Query(`
+        SELECT id, section_id, title, image_url, link_type, link_target
+        FROM home_banner
+        WHERE section_id = ANY($1)
+        AND (active_from IS NULL OR active_from <= CURRENT_TIMESTAMP)
+        AND (active_until IS NULL OR active_until > CURRENT_TIMESTAMP)
+        ORDER BY position, id`, pq.Array(sectionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error querying home banners: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var banner types.HomeBanner
+		var sectionID int
+		if err := rows.Scan(&banner.ID, &sectionID, &banner.Title, &banner.ImageURL, &banner.Link.Type, &banner.Link.Target); err != nil {
+			return nil, fmt.Errorf("error scanning home banner: %w", err)
+		}
+		banners[sectionID] = append(banners[sectionID], banner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating home banners: %w", err)
+	}
+	return banners, nil
+}
+
+// homeCollectionItems lists up to limit items of a collection that are in stock at the store. A
+// manual collection keeps its set order; rule-based ones are ordered by the rule.
+func homeCollectionItems(tx *sql.Tx, section homeSection, storeID int, limit int) ([]*types.Get_Items_By_CategoryID_And_StoreID, error) {
+	var filter, order string
+	args := []interface{}{storeID, limit}
+	switch section.rule {
+	case "":
+		filter = `i.id IN (SELECT hci.item_id FROM home_collection_item hci WHERE hci.section_id = $3)`
+		order = `(SELECT hci.position FROM home_collection_item hci WHERE hci.section_id = $3 AND hci.item_id = i.id)`
+		args = append(args, section.id)
+	case types.HomeRuleSchemeDiscount:
+		filter = `EXISTS (
+            SELECT 1 FROM item_scheme sch
+            WHERE (sch.item_id = i.id OR (sch.item_id IS NULL AND sch.brand_id = i.brand_id))
+            AND (sch.start_date IS NULL OR sch.start_date <= CURRENT_DATE)
+            AND (sch.end_date IS NULL OR sch.end_date >= CURRENT_DATE))`
+		order = `istore.stock_quantity DESC`
+	case types.HomeRuleStoreDiscount:
+		filter = `istore.discount > 0`
+		order = `istore.discount DESC`
+	case types.HomeRuleNewArrivals:
+		filter = `i.created_at >= CURRENT_TIMESTAMP - INTERVAL '30 days'`
+		order = `i.created_at DESC`
+	case types.HomeRuleCategory:
+		filter = `i.id IN (
+            SELECT ic.item_id FROM item_category ic
+            WHERE ic.category_id IN (
+                WITH RECURSIVE sub AS (
+                    SELECT id FROM category WHERE id = $3
+                    UNION
+                    SELECT c.id FROM category c JOIN sub ON c.parent_id = sub.id
+                )
+                SELECT id FROM sub))`
+		order = `istore.stock_quantity DESC`
+		args = append(args, section.ruleParam)
+	case types.HomeRuleBrand:
+		filter = `i.brand_id = $3`
+		order = `istore.stock_quantity DESC`
+		args = append(args, section.ruleParam)
+	default:
+		return nil, fmt.Errorf("collection %d has unknown rule %q", section.id, section.rule)
+	}
+
+	query := storeItemCardsQuery("istore.stock_quantity > 0 AND "+filter, order+", i.id LIMIT $2")
+	items, err := queryItemCards(tx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying items of collection %d: %w", section.id, err)
+	}
+	return items, nil
+}
+
+// GetHomeSections lists the sections shown in a store, or every section when StoreID is 0, whether
+// live or not, in the store's display order.
+func (s *PostgresStore) GetHomeSections(req types.HomeSectionRequest) ([]types.HomeSectionConfig, error) {
+	rows, err := s.db.Query(`
+        SELECT hs.id, COALESCE(hs.store_id, 0), hs.kind, hs.title, hs.position, hs.enabled,
+               hs.active_from, hs.active_until, COALESCE(hs.rule, ''), hs.rule_param, hs.max_items, hss.position
+        FROM home_section hs
+        LEFT JOIN home_section_store hss ON hss.section_id = hs.id AND hss.store_id = $1
+        WHERE ($1 = 0 OR hs.store_id IS NULL OR hs.store_id = $1)
+        AND ($2 = 0 OR hs.id = $2)
+        ORDER BY hss.position IS NULL, COALESCE(hss.position, hs.position), hs.id`, req.StoreID, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying home sections: %w", err)
+	}
+	sections := []types.HomeSectionConfig{}
+	index := make(map[int]int)
+	for rows.Next() {
+		var section types.HomeSectionConfig
+		var storePosition sql.NullInt64
+		err := rows.Scan(&section.ID, &section.StoreID, &section.Kind, &section.Title, &section.Position, &section.Enabled,
+			&section.ActiveFrom, &section.ActiveUntil, &section.Rule, &section.RuleParam, &section.MaxItems, &storePosition)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning home section: %w", err)
+		}
+		if storePosition.Valid {
+			position := int(storePosition.Int64)
+			section.StorePosition = &position
+		}
+		section.Banners = []types.SetHomeBanner{}
+		section.ItemIDs = []int{}
+		index[section.ID] = len(sections)
+		sections = append(sections, section)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating home sections: %w", err)
+	}
+	if req.ID != 0 && len(sections) == 0 {
+		return nil, fmt.Errorf("home section with id = [%d] not found", req.ID)
+	}
+
+	ids := make([]int, 0, len(sections))
+	for _, section := range sections {
+		ids = append(ids, section.ID)
+	}
+
+	rows, err = s.db.Query(`
+        SELECT id, section_id, title, image_url, link_type, link_target, position, active_from, active_until
+        FROM home_banner
+        WHERE section_id = ANY($1)
+        ORDER BY position, id`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error querying home banners: %w", err)
+	}
+	for rows.Next() {
+		var banner types.SetHomeBanner
+		err := rows.Scan(&banner.ID, &banner.SectionID, &banner.Title, &banner.ImageURL, &banner.Link.Type, &banner.Link.Target,
+			&banner.Position, &banner.ActiveFrom, &banner.ActiveUntil)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning home banner: %w", err)
+		}
+		section := &sections[index[banner.SectionID]]
+		section.Banners = append(section.Banners, banner)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating home banners: %w", err)
+	}
+
+	rows, err = s.db.Query(`
+        SELECT section_id, item_id FROM home_collection_item
+        WHERE section_id = ANY($1)
+        ORDER BY section_id, position`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error querying collection items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sectionID, itemID int
+		if err := rows.Scan(&sectionID, &itemID); err != nil {
+			return nil, fmt.Errorf("error scanning collection item: %w", err)
+		}
+		section := &sections[index[sectionID]]
+		section.ItemIDs = append(section.ItemIDs, itemID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating collection items: %w", err)
+	}
+	return sections, nil
+}
+
+// SetHomeSection creates or updates a section and returns it as managers see it.
+func (s *PostgresStore) SetHomeSection(section types.SetHomeSection) (*types.HomeSectionConfig, error) {
+	switch section.Kind {
+	case types.HomeSectionBanners, types.HomeSectionCategories:
+		if section.Rule != "" {
+			return nil, fmt.Errorf("only collections take a rule")
+		}
+	case types.HomeSectionCollection:
+		if strings.TrimSpace(section.Title) == "" {
+			return nil, fmt.Errorf("a collection needs a title")
+		}
+	default:
+		return nil, fmt.Errorf("unknown section kind %q", section.Kind)
+	}
+	if section.ActiveFrom != nil && section.ActiveUntil != nil && !section.ActiveFrom.Before(*section.ActiveUntil) {
+		return nil, fmt.Errorf("active_from must be before active_until")
+	}
+	if section.MaxItems == 0 {
+		section.MaxItems = homeDefaultMaxItems
+	}
+	if section.MaxItems < 0 || section.MaxItems > homeMaxItems {
+		return nil, fmt.Errorf("max_items must be between 1 and %d", homeMaxItems)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	switch section.Rule {
+	case "", types.HomeRuleSchemeDiscount, types.HomeRuleStoreDiscount, types.HomeRuleNewArrivals:
+		section.RuleParam = 0
+	case types.HomeRuleCategory:
+		if err := homeTargetExists(tx, "category", section.RuleParam); err != nil {
+			return nil, err
+		}
+	case types.HomeRuleBrand:
+		if err := homeTargetExists(tx, "brand", section.RuleParam); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown collection rule %q", section.Rule)
+	}
+
+	var storeID, rule interface{}
+	if section.StoreID != 0 {
+		storeID = section.StoreID
+	}
+	if section.Rule != "" {
+		rule = section.Rule
+	}
+
+	if section.ID == 0 {
+		err = tx.QueryRow(`
+            INSERT INTO home_section (store_id, kind, title, position, enabled, active_from, active_until, rule, rule_param, max_items)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+            RETURNING id`,
+			storeID, section.Kind, section.Title, section.Position, section.Enabled,
+			section.ActiveFrom, section.ActiveUntil, rule, section.RuleParam, section.MaxItems).Scan(&section.ID)
+	} else {
+		err = tx.QueryRow(`
+            UPDATE home_section
+            SET store_id = $2, kind = $3, title = $4, position = $5, enabled = $6, active_from = $7,
+                active_until = $8, rule = $9, rule_param = $10, max_items = $11, updated_at = CURRENT_TIMESTAMP
+            WHERE id = $1
+            RETURNING id`,
+			section.ID, storeID, section.Kind, section.Title, section.Position, section.Enabled,
+			section.ActiveFrom, section.ActiveUntil, rule, section.RuleParam, section.MaxItems).Scan(&section.ID)
+	}
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("home section with id = [%d] not found", section.ID)
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("store %d does not exist", section.StoreID)
+		}
+		return nil, fmt.Errorf("error saving home section: %w", err)
+	}
+
+	// Content that no longer fits the section's kind would linger unseen, so it goes.
+	if section.Kind != types.HomeSectionBanners {
+		if _, err := tx.Exec(`DELETE FROM home_banner WHERE section_id = $1`, section.ID); err != nil {
+			return nil, fmt.Errorf("error clearing banners of section %d: %w", section.ID, err)
+		}
+	}
+	if section.Kind != types.HomeSectionCollection || section.Rule != "" {
+		if _, err := tx.Exec(`DELETE FROM home_collection_item WHERE section_id = $1`, section.ID); err != nil {
+			return nil, fmt.Errorf("error clearing items of section %d: %w", section.ID, err)
+		}
+	}
+	if section.StoreID != 0 {
+		if _, err := tx.Exec(`DELETE FROM home_section_store WHERE section_id = $1 AND store_id <> $2`, section.ID, section.StoreID); err != nil {
+			return nil, fmt.Errorf("error clearing store order of section %d: %w", section.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return s.homeSectionConfig(section.ID)
+}
+
+func (s *PostgresStore) DeleteHomeSection(id int) error {
+	res, err := s.db.Exec(`DELETE FROM home_section WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting home section %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("home section with id = [%d] not found", id)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return nil
+}
+
+// SetHomeBanner creates or updates a banner after checking its deep link resolves.
+func (s *PostgresStore) SetHomeBanner(banner types.SetHomeBanner) (*types.HomeSectionConfig, error) {
+	if strings.TrimSpace(banner.ImageURL) == "" {
+		return nil, fmt.Errorf("a banner needs an image_url")
+	}
+	if banner.ActiveFrom != nil && banner.ActiveUntil != nil && !banner.ActiveFrom.Before(*banner.ActiveUntil) {
+		return nil, fmt.Errorf("active_from must be before active_until")
+	}
+	if banner.Link.Type == "" {
+		banner.Link.Type = types.HomeLinkNone
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var kind string
+	err = tx.QueryRow(`SELECT kind FROM home_section WHERE id = $1`, banner.SectionID).Scan(&kind)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("home section with id = [%d] not found", banner.SectionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying home section %d: %w", banner.SectionID, err)
+	}
+	if kind != types.HomeSectionBanners {
+		return nil, fmt.Errorf("section %d is a %s section, banners go in a banners section", banner.SectionID, kind)
+	}
+	if err := validateHomeLink(tx, banner.Link); err != nil {
+		return nil, err
+	}
+
+	if banner.ID == 0 {
+		err = tx.QueryRow(`
+            INSERT INTO home_banner (section_id, title, image_url, link_type, link_target, position, active_from, active_until)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            RETURNING id`,
+			banner.SectionID, banner.Title, banner.ImageURL, banner.Link.Type, banner.Link.Target,
+			banner.Position, banner.ActiveFrom, banner.ActiveUntil).Scan(&banner.ID)
+	} else {
+		err = tx.QueryRow(`
+            UPDATE home_banner
+            SET section_id = $2, title = $3, image_url = $4, link_type = $5, link_target = $6,
+                position = $7, active_from = $8, active_until = $9
+            WHERE id = $1
+            RETURNING id`,
+			banner.ID, banner.SectionID, banner.Title, banner.ImageURL, banner.Link.Type, banner.Link.Target,
+			banner.Position, banner.ActiveFrom, banner.ActiveUntil).Scan(&banner.ID)
+	}
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("home banner with id = [%d] not found", banner.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error saving home banner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return s.homeSectionConfig(banner.SectionID)
+}
+
+func (s *PostgresStore) DeleteHomeBanner(id int) error {
+	res, err := s.db.Exec(`DELETE FROM home_banner WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting home banner %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("home banner with id = [%d] not found", id)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return nil
+}
+
+// SetHomeCollectionItems replaces the items of a manual collection.
+func (s *PostgresStore) SetHomeCollectionItems(req types.SetHomeCollectionItems) (*types.HomeSectionConfig, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var kind, rule string
+	err = tx.QueryRow(`SELECT kind, COALESCE(rule, '') FROM home_section WHERE id = $1 FOR UPDATE`, req.SectionID).Scan(&kind, &rule)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("home section with id = [%d] not found", req.SectionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying home section %d: %w", req.SectionID, err)
+	}
+	if kind != types.HomeSectionCollection || rule != "" {
+		return nil, fmt.Errorf("section %d is not a manual collection", req.SectionID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM home_collection_item WHERE section_id = $1`, req.SectionID); err != nil {
+		return nil, fmt.Errorf("error clearing items of section %d: %w", req.SectionID, err)
+	}
+	seen := make(map[int]bool, len(req.ItemIDs))
+	for position, itemID := range req.ItemIDs {
+		if seen[itemID] {
+			return nil, fmt.Errorf("item %d is listed twice", itemID)
+		}
+		seen[itemID] = true
+		_, err := tx.Exec(`INSERT INTO home_collection_item (section_id, item_id, position) VALUES ($1, $2, $3)`,
+			req.SectionID, itemID, position+1)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return nil, fmt.Errorf("item with id = [%d] not found", itemID)
+			}
+			return nil, fmt.Errorf("error adding item %d to section %d: %w", itemID, req.SectionID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return s.homeSectionConfig(req.SectionID)
+}
+
+// SetHomeOrder replaces the section order of a store and returns its sections in the new order.
+func (s *PostgresStore) SetHomeOrder(order types.SetHomeOrder) ([]types.HomeSectionConfig, error) {
+	if order.StoreID == 0 {
+		return nil, fmt.Errorf("store_id is required")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM home_section_store WHERE store_id = $1`, order.StoreID); err != nil {
+		return nil, fmt.Errorf("error clearing section order of store %d: %w", order.StoreID, err)
+	}
+	seen := make(map[int]bool, len(order.SectionIDs))
+	for position, sectionID := range order.SectionIDs {
+		if seen[sectionID] {
+			return nil, fmt.Errorf("section %d is listed twice", sectionID)
+		}
+		seen[sectionID] = true
+
+		var sectionStore sql.NullInt64
+		err := tx.QueryRow(`SELECT store_id FROM home_section WHERE id = $1`, sectionID).Scan(&sectionStore)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("home section with id = [%d] not found", sectionID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error querying home section %d: %w", sectionID, err)
+		}
+		if sectionStore.Valid && int(sectionStore.Int64) != order.StoreID {
+			return nil, fmt.Errorf("section %d belongs to store %d", sectionID, sectionStore.Int64)
+		}
+
+		_, err = tx.Exec(`INSERT INTO home_section_store (section_id, store_id, position) VALUES ($1, $2, $3)`,
+			sectionID, order.StoreID, position+1)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return nil, fmt.Errorf("store %d does not exist", order.StoreID)
+			}
+			return nil, fmt.Errorf("error ordering section %d in store %d: %w", sectionID, order.StoreID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidatePrefix(catalogKeyHome)
+	return s.GetHomeSections(types.HomeSectionRequest{StoreID: order.StoreID})
+}
+
+func (s *PostgresStore) homeSectionConfig(id int) (*types.HomeSectionConfig, error) {
+	sections, err := s.GetHomeSections(types.HomeSectionRequest{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	return &sections[0], nil
+}
+
+// validateHomeLink checks that a deep link's target exists, so the apps never open a dead link.
+func validateHomeLink(q queryer, link types.HomeLink) error {
+	switch link.Type {
+	case types.HomeLinkNone:
+		if link.Target != "" {
+			return fmt.Errorf("a link of type none takes no target")
+		}
+		return nil
+	case types.HomeLinkSearch:
+		if strings.TrimSpace(link.Target) == "" {
+			return fmt.Errorf("a search link needs the search text")
+		}
+		return nil
+	case types.HomeLinkURL:
+		if !strings.HasPrefix(link.Target, "https://") {
+			return fmt.Errorf("a url link needs an https address")
+		}
+		return nil
+	}
+
+	var table string
+	switch link.Type {
+	case types.HomeLinkCategory, types.HomeLinkItem, types.HomeLinkBrand:
+		table = link.Type
+	case types.HomeLinkCollection:
+		table = "home_section"
+	default:
+		return fmt.Errorf("unknown link type %q", link.Type)
+	}
+	id, err := strconv.Atoi(link.Target)
+	if err != nil {
+		return fmt.Errorf("a %s link needs an id target, got %q", link.Type, link.Target)
+	}
+	if link.Type != types.HomeLinkCollection {
+		return homeTargetExists(q, table, id)
+	}
+
+	var kind string
+	err = q.QueryRow(`SELECT kind FROM home_section WHERE id = $1`, id).Scan(&kind)
+	if err == sql.ErrNoRows || (err == nil && kind != types.HomeSectionCollection) {
+		return fmt.Errorf("collection with id = [%d] not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error querying collection %d: %w", id, err)
+	}
+	return nil
+}
+
+// homeTargetExists checks that a row of table exists. table is always a name chosen by the caller, never input.
+func homeTargetExists(q queryer, table string, id int) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error querying %s %d: %w", table, id, err)
+	}
+	if !exists {
+		return fmt.Errorf("%s with id = [%d] not found", table, id)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// TestValidateHomeLink covers the links that are checked without a database.
+func TestValidateHomeLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		link    types.HomeLink
+		wantErr bool
+	}{
+		{"none", types.HomeLink{Type: types.HomeLinkNone}, false},
+		{"none with target", types.HomeLink{Type: types.HomeLinkNone, Target: "5"}, true},
+		{"search", types.HomeLink{Type: types.HomeLinkSearch, Target: "atta"}, false},
+		{"blank search", types.HomeLink{Type: types.HomeLinkSearch, Target: "  "}, true},
+		{"https url", types.HomeLink{Type: types.HomeLinkURL, Target: "https://example.com/sale"}, false},
+		{"http url", types.HomeLink{Type: types.HomeLinkURL, Target: "http://example.com"}, true},
+		{"unknown type", types.HomeLink{Type: "page", Target: "1"}, true},
+		{"category without id", types.HomeLink{Type: types.HomeLinkCategory, Target: "fruits"}, true},
+		{"collection without id", types.HomeLink{Type: types.HomeLinkCollection}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHomeLink(nil, tt.link)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateHomeLink(%+v) error = %v, wantErr %v", tt.link, err, tt.wantErr)
+			}
+		})
+	}
+}
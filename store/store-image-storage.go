@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gcs "cloud.google.com/go/storage"
+	"firebase.google.com/go/storage"
+)
+
+// imageStorage stores uploaded images under a key and returns their public URL.
+type imageStorage interface {
+	put(ctx context.Context, key string, contentType string, data []byte) (string, error)
+	remove(ctx context.Context, key string) error
+}
+
+// firebaseImageStorage keeps images in the Firebase Storage bucket IMAGE_BUCKET, readable by everyone.
+type firebaseImageStorage struct {
+	client *storage.Client
+	bucket string
+}
+
+func newFirebaseImageStorage(client *storage.Client) *firebaseImageStorage {
+	bucket := os.Getenv("IMAGE_BUCKET")
+	if bucket == "" {
+		bucket = "seismic-ground-410711.appspot.com"
+	}
+	return &firebaseImageStorage{client: client, bucket: bucket}
+}
+
+func (f *firebaseImageStorage) put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	bucket, err := f.client.Bucket(f.bucket)
+	if err != nil {
+		return "", fmt.Errorf("error getting bucket: %w", err)
+	}
+
+	obj := bucket.Object(key)
+	w := obj.NewWriter(ctx)
+	w.ContentType = contentType
+	w.CacheControl = "public, max-age=31536000, immutable"
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return "", fmt.Errorf("error uploading %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error uploading %s: %w", key, err)
+	}
+
+	if err := obj.ACL().Set(ctx, gcs.AllUsers, gcs.RoleReader); err != nil {
+		return "", fmt.Errorf("error making %s public: %w", key, err)
+	}
+
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", f.bucket, key), nil
+}
+
+func (f *firebaseImageStorage) remove(ctx context.Context, key string) error {
+	bucket, err := f.client.Bucket(f.bucket)
+	if err != nil {
+		return fmt.Errorf("error getting bucket: %w", err)
+	}
+	if err := bucket.Object(key).Delete(ctx); err != nil && err != gcs.ErrObjectNotExist {
+		return fmt.Errorf("error deleting %s: %w", key, err)
+	}
+	return nil
+}
+
+// localImageStorage writes images below IMAGE_STORAGE_DIR (default "uploads") for local development.
+// The API serves that directory at IMAGE_BASE_URL (default "/uploads").
+type localImageStorage struct {
+	dir     string
+	baseURL string
+}
+
+func newLocalImageStorage() *localImageStorage {
+	dir := os.Getenv("IMAGE_STORAGE_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+	baseURL := os.Getenv("IMAGE_BASE_URL")
+	if baseURL == "" {
+		baseURL = "/uploads"
+	}
+	return &localImageStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (l *localImageStorage) put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("error creating image directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", key, err)
+	}
+	return l.baseURL + "/" + key, nil
+}
+
+func (l *localImageStorage) remove(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(l.dir, filepath.FromSlash(key)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting %s: %w", key, err)
+	}
+	return nil
+}
+
+// LocalImageDir returns the directory to serve uploaded images from, or "" when images are stored remotely.
+func (s *PostgresStore) LocalImageDir() string {
+	if local, ok := s.images.(*localImageStorage); ok {
+		return local.dir
+	}
+	return ""
+}
+
+// LocalImageBaseURL is the path LocalImageDir is served at.
+func (s *PostgresStore) LocalImageBaseURL() string {
+	if local, ok := s.images.(*localImageStorage); ok {
+		return local.baseURL
+	}
+	return ""
+}
@@ -29,7 +29,9 @@ type InvoiceItem struct {
 }
 
 func (s *PostgresStore) genInvoice(orderID int) (string, []InvoiceItem, error) {
-	query := `SELECT ci.quantity, i.name, ci.sold_price, if.mrp_price, t.sgst, t.cgst, t.cess, if.gst_on_mrp, if.cess_on_mrp, so.order_date, so.invoice_id, c.phone
+	// Loose items are charged for their packed weight rather than the ordered quantity
+	query := `SELECT ci.quantity, i.name, ci.sold_price, if.mrp_price, t.sgst, t.cgst, t.cess, if.gst_on_mrp, if.cess_on_mrp, so.order_date, so.invoice_id, c.phone,
+                     COALESCE(oiw.actual_amount, ci.sold_price * ci.quantity), COALESCE(oiw.actual_grams, 0), COALESCE(oiw.estimated_grams, 0)
               FROM cart_item ci
               JOIN item_store istore ON ci.item_id = istore.item_id
               JOIN item i ON istore.item_id = i.id
@@ -37,6 +39,7 @@ func (s *PostgresStore) genInvoice(orderID int) (string, []InvoiceItem, error) {
               JOIN item_financial if ON i.id = if.item_id
               JOIN tax t ON if.tax_id = t.id
               JOIN customer c ON so.customer_id = c.id
+              LEFT JOIN order_item_weight oiw ON oiw.sales_order_id = so.id AND oiw.item_id = ci.item_id
               WHERE so.id = $1`
 	rows, err := s.db.Query(query, orderID)
 	if err != nil {
@@ -62,8 +65,9 @@ func (s *PostgresStore) genInvoice(orderID int) (string, []InvoiceItem, error) {
 	for rows.Next() {
 		var qty int
 		var name string
-		var soldPrice, mrpPrice, cgstRate, sgstRate, cessRate, gstOnMrp, cessOnMrp float64
-		if err := rows.Scan(&qty, &name, &soldPrice, &mrpPrice, &sgstRate, &cgstRate, &cessRate, &gstOnMrp, &cessOnMrp, &orderDate, &invoiceID, &customerPhone); err != nil {
+		var soldPrice, mrpPrice, cgstRate, sgstRate, cessRate, gstOnMrp, cessOnMrp, lineTotal float64
+		var actualGrams, estimatedGrams int
+		if err := rows.Scan(&qty, &name, &soldPrice, &mrpPrice, &sgstRate, &cgstRate, &cessRate, &gstOnMrp, &cessOnMrp, &orderDate, &invoiceID, &customerPhone, &lineTotal, &actualGrams, &estimatedGrams); err != nil {
 			return "", nil, fmt.Errorf("error scanning order items and customer details: %v", err)
 		}
 
@@ -77,13 +81,20 @@ func (s *PostgresStore) genInvoice(orderID int) (string, []InvoiceItem, error) {
 		sgstTotal := sgstRate / 10000.0
 		cessFraction := cessRate / 10000.0
 
+		// units is the ordered quantity, or for a loose item the packed weight in ordered units
+		units := float64(qty)
+		if actualGrams > 0 && estimatedGrams > 0 {
+			units = float64(qty) * float64(actualGrams) / float64(estimatedGrams)
+			name = fmt.Sprintf("%s (%.3f kg)", name, float64(actualGrams)/1000)
+		}
+
 		rate := mrpPrice - gstOnMrp - cessOnMrp
-		cgstAmount := (soldPrice / (1 + gstTotal + cessFraction)) * float64(qty) * cgstTotal
-		sgstAmount := (soldPrice / (1 + gstTotal + cessFraction)) * float64(qty) * sgstTotal
-		cessAmount := (soldPrice / (1 + gstTotal + cessFraction)) * float64(qty) * cessFraction
+		cgstAmount := (lineTotal / (1 + gstTotal + cessFraction)) * cgstTotal
+		sgstAmount := (lineTotal / (1 + gstTotal + cessFraction)) * sgstTotal
+		cessAmount := (lineTotal / (1 + gstTotal + cessFraction)) * cessFraction
 
-		taxableAmount := (soldPrice / (1 + gstTotal + cessFraction)) * float64(qty)
-		discount := (rate * float64(qty)) - taxableAmount
+		taxableAmount := lineTotal / (1 + gstTotal + cessFraction)
+		discount := (rate * units) - taxableAmount
 		if discount <= 0 {
 			discount = 0
 		}
@@ -100,7 +111,7 @@ func (s *PostgresStore) genInvoice(orderID int) (string, []InvoiceItem, error) {
 			SGSTAmt:         sgstAmount,
 			Cess:            fmt.Sprintf("%.2f%%", cessFraction*100),
 			CessAmt:         cessAmount,
-			TotalAmt:        soldPrice * float64(qty),
+			TotalAmt:        lineTotal,
 		})
 	}
 
@@ -0,0 +1,390 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// CreateItemAttributeTables stores the label information of items in typed columns, so search can
+// filter on them, and the per-category schema of which attributes apply and which are required.
+func (s *PostgresStore) CreateItemAttributeTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS item_attribute (
+        item_id INT PRIMARY KEY REFERENCES item(id) ON DELETE CASCADE,
+        veg_mark VARCHAR(10) CHECK (veg_mark IN ('veg', 'non_veg', 'egg')),
+        ingredients TEXT,
+        allergens TEXT[] NOT NULL DEFAULT '{}',
+        nutrition JSONB,
+        shelf_life_days INT CHECK (shelf_life_days > 0),
+        country_of_origin CHAR(2),
+        manufacturer_name VARCHAR(200),
+        manufacturer_address TEXT,
+        manufacturer_license VARCHAR(50),
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS item_attribute_veg_mark ON item_attribute (veg_mark);
+    CREATE INDEX IF NOT EXISTS item_attribute_allergens ON item_attribute USING GIN (allergens);
+
+    CREATE TABLE IF NOT EXISTS category_attribute (
+        category_id INT NOT NULL REFERENCES category(id) ON DELETE CASCADE,
+        attribute VARCHAR(30) NOT NULL,
+        required BOOLEAN NOT NULL DEFAULT FALSE,
+        PRIMARY KEY (category_id, attribute)
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating item attribute tables: %w", err)
+	}
+	return nil
+}
+
+func knownItemAttribute(name string) bool {
+	for _, n := range types.ItemAttributeNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeItemAttributes trims and validates attrs in place.
+func normalizeItemAttributes(attrs *types.ItemAttributes) error {
+	attrs.VegMark = strings.ToLower(strings.TrimSpace(attrs.VegMark))
+	switch attrs.VegMark {
+	case "", types.VegMarkVeg, types.VegMarkNonVeg, types.VegMarkEgg:
+	default:
+		return fmt.Errorf("veg_mark must be %q, %q or %q, got %q", types.VegMarkVeg, types.VegMarkNonVeg, types.VegMarkEgg, attrs.VegMark)
+	}
+
+	attrs.Ingredients = strings.TrimSpace(attrs.Ingredients)
+
+	seen := make(map[string]bool, len(attrs.Allergens))
+	allergens := make([]string, 0, len(attrs.Allergens))
+	for _, a := range attrs.Allergens {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		allergens = append(allergens, a)
+	}
+	sort.Strings(allergens)
+	attrs.Allergens = allergens
+
+	if n := attrs.Nutrition; n != nil {
+		if n.Per == "" {
+			n.Per = "100g"
+		}
+		if n.Per != "100g" && n.Per != "100ml" {
+			return fmt.Errorf("nutrition must be declared per \"100g\" or \"100ml\", got %q", n.Per)
+		}
+		values := map[string]*float64{
+			"energy_kcal": n.EnergyKcal, "protein_g": n.ProteinG, "carbohydrate_g": n.CarbohydrateG,
+			"sugar_g": n.SugarG, "fat_g": n.FatG, "saturated_fat_g": n.SaturatedFatG, "trans_fat_g": n.TransFatG,
+			"fibre_g": n.FibreG, "sodium_mg": n.SodiumMg, "cholesterol_mg": n.CholesterolMg,
+		}
+		for name, v := range values {
+			if v != nil && *v < 0 {
+				return fmt.Errorf("nutrition %s cannot be negative", name)
+			}
+		}
+	}
+
+	if attrs.ShelfLifeDays != nil && *attrs.ShelfLifeDays <= 0 {
+		return fmt.Errorf("shelf_life_days must be positive")
+	}
+
+	attrs.CountryOfOrigin = strings.ToUpper(strings.TrimSpace(attrs.CountryOfOrigin))
+	if c := attrs.CountryOfOrigin; c != "" && (len(c) != 2 || c[0] < 'A' || c[0] > 'Z' || c[1] < 'A' || c[1] > 'Z') {
+		return fmt.Errorf("country_of_origin must be a two-letter ISO country code, got %q", c)
+	}
+
+	if m := attrs.Manufacturer; m != nil {
+		m.Name = strings.TrimSpace(m.Name)
+		m.Address = strings.TrimSpace(m.Address)
+		m.License = strings.TrimSpace(m.License)
+		if m.Name == "" {
+			return fmt.Errorf("manufacturer name is required")
+		}
+	}
+	return nil
+}
+
+// presentItemAttributes lists the schema names of the attributes that are filled in.
+func presentItemAttributes(attrs *types.ItemAttributes) map[string]bool {
+	present := map[string]bool{}
+	if attrs == nil {
+		return present
+	}
+	present[types.AttributeVegMark] = attrs.VegMark != ""
+	present[types.AttributeIngredients] = attrs.Ingredients != ""
+	present[types.AttributeAllergens] = len(attrs.Allergens) > 0
+	present[types.AttributeNutrition] = attrs.Nutrition != nil
+	present[types.AttributeShelfLifeDays] = attrs.ShelfLifeDays != nil
+	present[types.AttributeCountryOfOrigin] = attrs.CountryOfOrigin != ""
+	present[types.AttributeManufacturer] = attrs.Manufacturer != nil
+	return present
+}
+
+// itemAttributeSchema merges the schemas of the item's categories and their ancestors.
+// An attribute is required if any of them requires it.
+func itemAttributeSchema(q queryer, itemID int) ([]types.CategoryAttribute, error) {
+	query := `
+    WITH RECURSIVE lineage AS (
+        SELECT c.id, c.parent_id FROM category c
+        JOIN item_category ic ON ic.category_id = c.id
+        WHERE ic.item_id = $1
+        UNION
+        SELECT p.id, p.parent_id FROM category p
+        JOIN lineage l ON p.id = l.parent_id
+    )
+    SELECT ca.attribute, bool_or(ca.required)
+    FROM category_attribute ca
+    JOIN lineage l ON l.id = ca.category_id
+    GROUP BY ca.attribute
+    ORDER BY ca.attribute`
+
+	rows, err := q.Query(query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying attribute schema of item %d: %w", itemID, err)
+	}
+	defer rows.Close()
+
+	schema := []types.CategoryAttribute{}
+	for rows.Next() {
+		var a types.CategoryAttribute
+		if err := rows.Scan(&a.Attribute, &a.Required); err != nil {
+			return nil, fmt.Errorf("error scanning attribute schema: %w", err)
+		}
+		schema = append(schema, a)
+	}
+	return schema, rows.Err()
+}
+
+func missingItemAttributes(schema []types.CategoryAttribute, attrs *types.ItemAttributes) []string {
+	present := presentItemAttributes(attrs)
+	missing := []string{}
+	for _, a := range schema {
+		if a.Required && !present[a.Attribute] {
+			missing = append(missing, a.Attribute)
+		}
+	}
+	return missing
+}
+
+// itemAttributesByID loads the attributes of the given items. Items without attributes are absent from the map.
+func itemAttributesByID(q queryer, ids []int) (map[int]*types.ItemAttributes, error) {
+	result := make(map[int]*types.ItemAttributes, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `
+    SELECT item_id, COALESCE(veg_mark, ''), COALESCE(ingredients, ''), allergens, nutrition, shelf_life_days,
+           COALESCE(country_of_origin, ''), manufacturer_name, COALESCE(manufacturer_address, ''),
+           COALESCE(manufacturer_license, ''), updated_at
+    FROM item_attribute
+    WHERE item_id = ANY($1)`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error querying item attributes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attrs := &types.ItemAttributes{}
+		var allergens pq.StringArray
+		var nutrition []byte
+		var shelfLife sql.NullInt64
+		var manufacturerName sql.NullString
+		var manufacturer types.Manufacturer
+
+		err := rows.Scan(&attrs.ItemID, &attrs.VegMark, &attrs.Ingredients, &allergens, &nutrition, &shelfLife,
+			&attrs.CountryOfOrigin, &manufacturerName, &manufacturer.Address, &manufacturer.License, &attrs.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning item attributes: %w", err)
+		}
+
+		attrs.Allergens = []string(allergens)
+		if nutrition != nil {
+			attrs.Nutrition = &types.NutritionFacts{}
+			if err := json.Unmarshal(nutrition, attrs.Nutrition); err != nil {
+				return nil, fmt.Errorf("error decoding nutrition of item %d: %w", attrs.ItemID, err)
+			}
+		}
+		if shelfLife.Valid {
+			days := int(shelfLife.Int64)
+			attrs.ShelfLifeDays = &days
+		}
+		if manufacturerName.Valid {
+			manufacturer.Name = manufacturerName.String
+			attrs.Manufacturer = &manufacturer
+		}
+		result[attrs.ItemID] = attrs
+	}
+	return result, rows.Err()
+}
+
+func (s *PostgresStore) GetItemAttributeDetails(itemID int) (*types.ItemAttributeDetails, error) {
+	byID, err := itemAttributesByID(s.db, []int{itemID})
+	if err != nil {
+		return nil, err
+	}
+	schema, err := itemAttributeSchema(s.db, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := byID[itemID]
+	if attrs == nil {
+		attrs = &types.ItemAttributes{ItemID: itemID, Allergens: []string{}}
+	}
+	return &types.ItemAttributeDetails{
+		Attributes: attrs,
+		Schema:     schema,
+		Missing:    missingItemAttributes(schema, attrs),
+	}, nil
+}
+
+// SetItemAttributes replaces all attributes of an item. When the item's categories define a schema,
+// attributes outside it are rejected and required ones must be present.
+func (s *PostgresStore) SetItemAttributes(attrs types.ItemAttributes) (*types.ItemAttributeDetails, error) {
+	if err := normalizeItemAttributes(&attrs); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM item WHERE id = $1)`, attrs.ItemID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking item %d: %w", attrs.ItemID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("item with id = [%d] not found", attrs.ItemID)
+	}
+
+	schema, err := itemAttributeSchema(tx, attrs.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if len(schema) > 0 {
+		allowed := make(map[string]bool, len(schema))
+		for _, a := range schema {
+			allowed[a.Attribute] = true
+		}
+		for name, present := range presentItemAttributes(&attrs) {
+			if present && !allowed[name] {
+				return nil, fmt.Errorf("attribute %s does not apply to the categories of item %d", name, attrs.ItemID)
+			}
+		}
+		if missing := missingItemAttributes(schema, &attrs); len(missing) > 0 {
+			return nil, fmt.Errorf("item %d is missing required attributes: %s", attrs.ItemID, strings.Join(missing, ", "))
+		}
+	}
+
+	var nutrition []byte
+	if attrs.Nutrition != nil {
+		if nutrition, err = json.Marshal(attrs.Nutrition); err != nil {
+			return nil, err
+		}
+	}
+	var manufacturerName, manufacturerAddress, manufacturerLicense sql.NullString
+	if m := attrs.Manufacturer; m != nil {
+		manufacturerName = sql.NullString{String: m.Name, Valid: true}
+		manufacturerAddress = sql.NullString{String: m.Address, Valid: m.Address != ""}
+		manufacturerLicense = sql.NullString{String: m.License, Valid: m.License != ""}
+	}
+
+	query := `
+    INSERT INTO item_attribute (item_id, veg_mark, ingredients, allergens, nutrition, shelf_life_days,
+        country_of_origin, manufacturer_name, manufacturer_address, manufacturer_license, updated_at)
+    VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), $8, $9, $10, CURRENT_TIMESTAMP)
+    ON CONFLICT (item_id) DO UPDATE SET
+        veg_mark = EXCLUDED.veg_mark,
+        ingredients = EXCLUDED.ingredients,
+        allergens = EXCLUDED.allergens,
+        nutrition = EXCLUDED.nutrition,
+        shelf_life_days = EXCLUDED.shelf_life_days,
+        country_of_origin = EXCLUDED.country_of_origin,
+        manufacturer_name = EXCLUDED.manufacturer_name,
+        manufacturer_address = EXCLUDED.manufacturer_address,
+        manufacturer_license = EXCLUDED.manufacturer_license,
+        updated_at = EXCLUDED.updated_at`
+
+	_, err = tx.Exec(query, attrs.ItemID, attrs.VegMark, attrs.Ingredients, pq.Array(attrs.Allergens), nutrition,
+		attrs.ShelfLifeDays, attrs.CountryOfOrigin, manufacturerName, manufacturerAddress, manufacturerLicense)
+	if err != nil {
+		return nil, fmt.Errorf("error saving attributes of item %d: %w", attrs.ItemID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateItems(0, attrs.ItemID)
+	return s.GetItemAttributeDetails(attrs.ItemID)
+}
+
+// SetCategoryAttributeSchema replaces the attributes defined directly on a category.
+func (s *PostgresStore) SetCategoryAttributeSchema(schema types.CategoryAttributeSchema) (*types.CategoryAttributeSchema, error) {
+	seen := make(map[string]bool, len(schema.Attributes))
+	names := make([]string, 0, len(schema.Attributes))
+	required := make([]bool, 0, len(schema.Attributes))
+	for _, a := range schema.Attributes {
+		if !knownItemAttribute(a.Attribute) {
+			return nil, fmt.Errorf("unknown attribute %q, expected one of %s", a.Attribute, strings.Join(types.ItemAttributeNames, ", "))
+		}
+		if seen[a.Attribute] {
+			return nil, fmt.Errorf("attribute %s is listed more than once", a.Attribute)
+		}
+		seen[a.Attribute] = true
+		names = append(names, a.Attribute)
+		required = append(required, a.Required)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM category WHERE id = $1)`, schema.CategoryID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking category %d: %w", schema.CategoryID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("category with id = [%d] not found", schema.CategoryID)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM category_attribute WHERE category_id = $1`, schema.CategoryID); err != nil {
+		return nil, fmt.Errorf("error clearing attribute schema of category %d: %w", schema.CategoryID, err)
+	}
+
+	query := `
+    INSERT INTO category_attribute (category_id, attribute, required)
+    SELECT $1, a.attribute, a.required
+    FROM unnest($2::text[], $3::boolean[]) AS a(attribute, required)`
+
+	if _, err := tx.Exec(query, schema.CategoryID, pq.Array(names), pq.Array(required)); err != nil {
+		return nil, fmt.Errorf("error saving attribute schema of category %d: %w", schema.CategoryID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if schema.Attributes == nil {
+		schema.Attributes = []types.CategoryAttribute{}
+	}
+	return &schema, nil
+}
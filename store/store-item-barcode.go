@@ -0,0 +1,243 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// CreateItemBarcodeTable stores any number of barcodes per item. gtin is the code left-padded to
+// 14 digits, so a UPC-A and the EAN-13 with a leading zero are recognised as the same product.
+// Existing item.barcode values are copied in as the items' primary codes.
+func (s *PostgresStore) CreateItemBarcodeTable(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS item_barcode (
+        id SERIAL PRIMARY KEY,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        barcode VARCHAR(15) NOT NULL,
+        gtin VARCHAR(15) NOT NULL UNIQUE,
+        format VARCHAR(10) NOT NULL,
+        is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        created_by INT
+    );
+
+    CREATE INDEX IF NOT EXISTS item_barcode_item_id ON item_barcode (item_id);
+    CREATE UNIQUE INDEX IF NOT EXISTS item_barcode_one_primary ON item_barcode (item_id) WHERE is_primary;`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating item_barcode table: %w", err)
+	}
+
+	migrateQuery := `
+    INSERT INTO item_barcode (item_id, barcode, gtin, format, is_primary)
+    SELECT id, barcode,
+           CASE WHEN barcode ~ '^[0-9]{8,14}$' THEN LPAD(barcode, 14, '0') ELSE barcode END,
+           'legacy', TRUE
+    FROM item
+    WHERE barcode IS NOT NULL AND barcode <> ''
+      AND NOT EXISTS (SELECT 1 FROM item_barcode ib WHERE ib.item_id = item.id AND ib.is_primary)
+    ON CONFLICT (gtin) DO NOTHING`
+
+	if _, err := tx.Exec(migrateQuery); err != nil {
+		return fmt.Errorf("error migrating item barcodes: %w", err)
+	}
+	return nil
+}
+
+// validateGTIN checks the length and check digit of an EAN-8, UPC-A or EAN-13 code and returns its format.
+func validateGTIN(code string) (string, error) {
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("barcode %q must contain digits only", code)
+		}
+	}
+
+	var format string
+	switch len(code) {
+	case 8:
+		format = types.BarcodeEAN8
+	case 12:
+		format = types.BarcodeUPCA
+	case 13:
+		format = types.BarcodeEAN13
+	default:
+		return "", fmt.Errorf("barcode %q must have 8 (EAN-8), 12 (UPC-A) or 13 (EAN-13) digits", code)
+	}
+
+	// Weights alternate 3, 1, 3, ... starting from the digit left of the check digit.
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		d := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	if check := (10 - sum%10) % 10; check != int(code[len(code)-1]-'0') {
+		return "", fmt.Errorf("barcode %q has an invalid check digit, expected %d", code, check)
+	}
+	return format, nil
+}
+
+// gtinKey is the lookup key of a scanned code, matching item_barcode.gtin.
+func gtinKey(code string) string {
+	code = strings.TrimSpace(code)
+	if len(code) < 8 || len(code) > 14 {
+		return code
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return code
+		}
+	}
+	return strings.Repeat("0", 14-len(code)) + code
+}
+
+// addItemBarcode validates code and attaches it to itemID. A code already on another item is rejected.
+// The first code of an item, or one added with primary, becomes the primary code in item.barcode.
+func addItemBarcode(tx *sql.Tx, itemID int, code string, primary bool) (*types.ItemBarcode, error) {
+	code = strings.TrimSpace(code)
+	format, err := validateGTIN(code)
+	if err != nil {
+		return nil, err
+	}
+	key := gtinKey(code)
+
+	var ownerID int
+	var ownerName string
+	err = tx.QueryRow(`
+        SELECT ib.item_id, i.name FROM item_barcode ib JOIN item i ON i.id = ib.item_id
+        WHERE ib.gtin = $1`, key).Scan(&ownerID, &ownerName)
+	switch {
+	case err == nil && ownerID != itemID:
+		return nil, fmt.Errorf("barcode %s already belongs to item %d (%s)", code, ownerID, ownerName)
+	case err != nil && err != sql.ErrNoRows:
+		return nil, fmt.Errorf("error checking barcode %s: %w", code, err)
+	}
+
+	var hasPrimary bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM item_barcode WHERE item_id = $1 AND is_primary)`, itemID).Scan(&hasPrimary); err != nil {
+		return nil, fmt.Errorf("error checking primary barcode of item %d: %w", itemID, err)
+	}
+	primary = primary || !hasPrimary
+
+	if primary {
+		if _, err := tx.Exec(`UPDATE item_barcode SET is_primary = FALSE WHERE item_id = $1 AND is_primary`, itemID); err != nil {
+			return nil, fmt.Errorf("error clearing primary barcode of item %d: %w", itemID, err)
+		}
+	}
+
+	barcode := &types.ItemBarcode{}
+	err = tx.QueryRow(`
+        INSERT INTO item_barcode (item_id, barcode, gtin, format, is_primary)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (gtin) DO UPDATE SET is_primary = item_barcode.is_primary OR EXCLUDED.is_primary
+        RETURNING id, item_id, barcode, format, is_primary, created_at`,
+		itemID, code, key, format, primary).
+		Scan(&barcode.ID, &barcode.ItemID, &barcode.Barcode, &barcode.Format, &barcode.Primary, &barcode.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error adding barcode %s to item %d: %w", code, itemID, err)
+	}
+
+	if barcode.Primary {
+		if err := syncPrimaryBarcode(tx, itemID); err != nil {
+			return nil, err
+		}
+	}
+	return barcode, nil
+}
+
+// syncPrimaryBarcode mirrors the primary code into item.barcode, which listings still read.
+func syncPrimaryBarcode(tx *sql.Tx, itemID int) error {
+	query := `
+    UPDATE item SET barcode = (SELECT barcode FROM item_barcode WHERE item_id = $1 AND is_primary)
+    WHERE id = $1`
+
+	if _, err := tx.Exec(query, itemID); err != nil {
+		return fmt.Errorf("error updating barcode of item %d: %w", itemID, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) AddItemBarcode(req types.AddItemBarcode) (*types.ItemBarcode, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM item WHERE id = $1 FOR UPDATE`, req.ItemID); err != nil {
+		return nil, fmt.Errorf("error locking item %d: %w", req.ItemID, err)
+	}
+
+	barcode, err := addItemBarcode(tx, req.ItemID, req.Barcode, req.Primary)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateItems(0, req.ItemID)
+	return barcode, nil
+}
+
+// RemoveItemBarcode detaches a code from an item. Removing the primary code promotes the oldest remaining one.
+func (s *PostgresStore) RemoveItemBarcode(req types.RemoveItemBarcode) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasPrimary bool
+	err = tx.QueryRow(`DELETE FROM item_barcode WHERE item_id = $1 AND gtin = $2 RETURNING is_primary`, req.ItemID, gtinKey(req.Barcode)).Scan(&wasPrimary)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("barcode %s does not belong to item %d", req.Barcode, req.ItemID)
+	}
+	if err != nil {
+		return fmt.Errorf("error removing barcode %s: %w", req.Barcode, err)
+	}
+
+	if wasPrimary {
+		promoteQuery := `
+        UPDATE item_barcode SET is_primary = TRUE
+        WHERE id = (SELECT id FROM item_barcode WHERE item_id = $1 ORDER BY created_at, id LIMIT 1)`
+
+		if _, err := tx.Exec(promoteQuery, req.ItemID); err != nil {
+			return fmt.Errorf("error promoting barcode of item %d: %w", req.ItemID, err)
+		}
+		if err := syncPrimaryBarcode(tx, req.ItemID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.catalog.invalidateItems(0, req.ItemID)
+	return nil
+}
+
+func (s *PostgresStore) GetItemBarcodes(itemID int) ([]types.ItemBarcode, error) {
+	rows, err := s.db.Query(`
+        SELECT id, item_id, barcode, format, is_primary, created_at
+        FROM item_barcode WHERE item_id = $1
+        ORDER BY is_primary DESC, created_at, id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying barcodes of item %d: %w", itemID, err)
+	}
+	defer rows.Close()
+
+	barcodes := []types.ItemBarcode{}
+	for rows.Next() {
+		var b types.ItemBarcode
+		if err := rows.Scan(&b.ID, &b.ItemID, &b.Barcode, &b.Format, &b.Primary, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning barcode: %w", err)
+		}
+		barcodes = append(barcodes, b)
+	}
+	return barcodes, rows.Err()
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+func TestValidateGTIN(t *testing.T) {
+	tests := []struct {
+		code    string
+		format  string
+		wantErr bool
+	}{
+		{"96385074", types.BarcodeEAN8, false},
+		{"036000291452", types.BarcodeUPCA, false},
+		{"4006381333931", types.BarcodeEAN13, false},
+		{"8901030865275", types.BarcodeEAN13, false},
+		{"4006381333932", "", true},
+		{"96385075", "", true},
+		{"1234567", "", true},
+		{"12345678901234", "", true},
+		{"40063813339a1", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			format, err := validateGTIN(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateGTIN(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if format != tt.format {
+				t.Errorf("validateGTIN(%q) = %q, want %q", tt.code, format, tt.format)
+			}
+		})
+	}
+}
+
+func TestGtinKey(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"96385074", "00000096385074"},
+		{"036000291452", "00036000291452"},
+		{" 4006381333931 ", "04006381333931"},
+		{"04006381333931", "04006381333931"},
+		{"1234567", "1234567"},
+		{"123456789012345", "123456789012345"},
+		{"ABC12345", "ABC12345"},
+	}
+	for _, tt := range tests {
+		if got := gtinKey(tt.code); got != tt.want {
+			t.Errorf("gtinKey(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
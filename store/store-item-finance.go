@@ -92,54 +92,81 @@ func (s *PostgresStore) ManagerGetItemFinancialByItemId(itemID int) (*ItemFinanc
 	return &details, nil
 }
 
-func (s *PostgresStore) ManagerEditItemFinancialByItemId(itemFinance types.ItemFinance) (*ItemFinancialDetails, error) {
-	var existingID int
-	checkExistenceQuery := `SELECT item_id FROM item_financial WHERE item_id = $1`
-	err := s.db.QueryRow(checkExistenceQuery, itemFinance.ItemID).Scan(&existingID)
-
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("error checking for existing item financial record: %w", err)
-	}
+// itemTax holds the GST and cess split of an item's prices as stored in item_financial.
+// Rates are percentages; the tax table stores them multiplied by 100.
+type itemTax struct {
+	gstRate        float64
+	cessRate       float64
+	buyPriceIncTax float64
+	gstOnBuy       float64
+	cessOnBuy      float64
+	gstOnMRP       float64
+	cessOnMRP      float64
+}
 
+// computeItemTax splits a total tax percentage into GST, capped at 28%, and cess on the excess.
+// buyPrice is tax exclusive and mrpPrice tax inclusive.
+func computeItemTax(totalGST, buyPrice, mrpPrice float64) itemTax {
 	// Calculate GST and Cess rates
 	var gstRate, cessRate float64
 
-	if itemFinance.GST > 28 {
-		gstRate = 0.28                          // 28% as a decimal
-		cessRate = (itemFinance.GST - 28) / 100 // Excess over 28% as a decimal
+	if totalGST > 28 {
+		gstRate = 0.28                   // 28% as a decimal
+		cessRate = (totalGST - 28) / 100 // Excess over 28% as a decimal
 	} else {
-		gstRate = itemFinance.GST / 100 // Convert to decimal
+		gstRate = totalGST / 100 // Convert to decimal
 		cessRate = 0
 	}
 
-	buyPriceIncTax := itemFinance.BuyPrice * (1 + (gstRate + cessRate))
-	//mrpPriceIncTax := itemFinance.MRPPrice
-
-	buyPriceExclTax := itemFinance.BuyPrice
-	mrpPriceExclTax := itemFinance.MRPPrice / (1 + (gstRate + cessRate))
-
-	// Calculate exclusive Buy Price and MRP
-	//buyPriceExclTax := itemFinance.BuyPrice / (1 + gstRate + cessRate)
-	//mrpExclTax := itemFinance.MRPPrice / (1 + gstRate + cessRate)
+	mrpPriceExclTax := mrpPrice / (1 + (gstRate + cessRate))
 
 	// Calculate GST and Cess on exclusive prices
-	gstOnBuyPrice := buyPriceExclTax * gstRate
-	cessOnBuyPrice := buyPriceExclTax * cessRate
+	return itemTax{
+		gstRate:        gstRate * 100,
+		cessRate:       cessRate * 100,
+		buyPriceIncTax: buyPrice * (1 + (gstRate + cessRate)),
+		gstOnBuy:       buyPrice * gstRate,
+		cessOnBuy:      buyPrice * cessRate,
+		gstOnMRP:       mrpPriceExclTax * gstRate,
+		cessOnMRP:      mrpPriceExclTax * cessRate,
+	}
+}
 
-	gstOnMRP := mrpPriceExclTax * gstRate
-	cessOnMRP := mrpPriceExclTax * cessRate
+// ManagerEditItemFinancialByItemId sets an item's prices, tax, margin and scheme. A change to the
+// MRP or buy price of an existing record is applied as an immediate price change, so carts are
+// re-priced and the price history records it.
+func (s *PostgresStore) ManagerEditItemFinancialByItemId(itemFinance types.ItemFinance) (*ItemFinancialDetails, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	gstRate = gstRate * 100   // Convert to percentage
-	cessRate = cessRate * 100 // Convert to percentage
+	var existingID int
+	var currentMRP, currentBuy float64
+	checkExistenceQuery := `
+        SELECT item_id, COALESCE(mrp_price, 0), COALESCE(buy_price - gst_on_buy - cess_on_buy, 0)
+        FROM item_financial WHERE item_id = $1 FOR UPDATE`
+	err = tx.QueryRow(checkExistenceQuery, itemFinance.ItemID).Scan(&existingID, &currentMRP, &currentBuy)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error checking for existing item financial record: %w", err)
+	}
+
+	tax := computeItemTax(itemFinance.GST, itemFinance.BuyPrice, itemFinance.MRPPrice)
+	gstRate, cessRate := tax.gstRate, tax.cessRate
+	buyPriceIncTax := tax.buyPriceIncTax
+	gstOnBuyPrice, cessOnBuyPrice := tax.gstOnBuy, tax.cessOnBuy
+	gstOnMRP, cessOnMRP := tax.gstOnMRP, tax.cessOnMRP
 
 	var taxID int
 	// Query to fetch the matching tax_id from the tax table
 	getTaxIDQuery := `SELECT id FROM tax WHERE gst = $1 AND cess = $2`
-	err = s.db.QueryRow(getTaxIDQuery, gstRate*100, cessRate*100).Scan(&taxID) // Assuming tax table stores rates as percentages
+	err = tx.QueryRow(getTaxIDQuery, gstRate*100, cessRate*100).Scan(&taxID) // Assuming tax table stores rates as percentages
 	if err != nil {
 		return nil, fmt.Errorf("error fetching tax ID from tax table: %w", err)
 	}
 
+	carts := &repricedCarts{}
 	if existingID == 0 {
 		// Record does not exist, insert a new record
 		insertQuery := `
@@ -147,28 +174,45 @@ func (s *PostgresStore) ManagerEditItemFinancialByItemId(itemFinance types.ItemF
             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
             RETURNING item_id
         `
-		err = s.db.QueryRow(insertQuery, itemFinance.ItemID, buyPriceIncTax, itemFinance.MRPPrice, gstOnBuyPrice, cessOnBuyPrice, gstOnMRP, cessOnMRP, itemFinance.Margin, taxID).Scan(&existingID)
+		err = tx.QueryRow(insertQuery, itemFinance.ItemID, buyPriceIncTax, itemFinance.MRPPrice, gstOnBuyPrice, cessOnBuyPrice, gstOnMRP, cessOnMRP, itemFinance.Margin, taxID).Scan(&existingID)
 		if err != nil {
 			return nil, fmt.Errorf("error updating existing item financial record with GST: %.2f%%, Cess: %.2f%%: %w", gstRate*100, cessRate*100, err)
 		}
 
 	} else {
-		// Record exists, update the existing record
-		updateQuery := `
-            UPDATE item_financial
-            SET buy_price = $2, mrp_price = $3, gst_on_buy = $4, cess_on_buy = $5, gst_on_mrp = $6, cess_on_mrp = $7, margin = $8, tax_id = $9
-            WHERE item_id = $1
-        `
-		_, err = s.db.Exec(updateQuery, itemFinance.ItemID, buyPriceIncTax, itemFinance.MRPPrice, gstOnBuyPrice, cessOnBuyPrice, gstOnMRP, cessOnMRP, itemFinance.Margin, taxID)
+		// Record exists: the tax is set first so a price change splits the new prices at the new rate
+		_, err = tx.Exec(`UPDATE item_financial SET margin = $2, tax_id = $3 WHERE item_id = $1`, itemFinance.ItemID, itemFinance.Margin, taxID)
 		if err != nil {
 			return nil, fmt.Errorf("error updating existing item financial record:  %w", err)
 		}
+
+		change := types.SchedulePriceChange{ItemID: itemFinance.ItemID, Reason: "financial edit"}
+		if itemFinance.MRPPrice != currentMRP {
+			change.MRPPrice = &itemFinance.MRPPrice
+		}
+		if itemFinance.BuyPrice != currentBuy {
+			change.BuyPrice = &itemFinance.BuyPrice
+		}
+		if change.MRPPrice != nil || change.BuyPrice != nil {
+			if _, carts, err = applyPriceChangeNow(tx, change); err != nil {
+				return nil, err
+			}
+		} else {
+			// Same prices, but the tax may have changed their split
+			_, err = tx.Exec(`
+                UPDATE item_financial
+                SET gst_on_buy = $2, cess_on_buy = $3, gst_on_mrp = $4, cess_on_mrp = $5
+                WHERE item_id = $1`, itemFinance.ItemID, gstOnBuyPrice, cessOnBuyPrice, gstOnMRP, cessOnMRP)
+			if err != nil {
+				return nil, fmt.Errorf("error updating existing item financial record:  %w", err)
+			}
+		}
 	}
 
 	var schemeID int
 	// Check if a record exists in item_scheme for the given item_id
 	checkSchemeExistenceQuery := `SELECT id FROM item_scheme WHERE item_id = $1`
-	err = s.db.QueryRow(checkSchemeExistenceQuery, itemFinance.ItemID).Scan(&schemeID)
+	err = tx.QueryRow(checkSchemeExistenceQuery, itemFinance.ItemID).Scan(&schemeID)
 
 	if err != nil && err != sql.ErrNoRows {
 		// Handle unexpected errors
@@ -182,7 +226,7 @@ func (s *PostgresStore) ManagerEditItemFinancialByItemId(itemFinance types.ItemF
             VALUES ($1, $2, $3, $4, $5)
             RETURNING id
         `
-		err = s.db.QueryRow(insertSchemeQuery, itemFinance.ItemID, itemFinance.Discount, itemFinance.MinimumQuantity, itemFinance.StartDate, itemFinance.EndDate).Scan(&schemeID)
+		err = tx.QueryRow(insertSchemeQuery, itemFinance.ItemID, itemFinance.Discount, itemFinance.MinimumQuantity, itemFinance.StartDate, itemFinance.EndDate).Scan(&schemeID)
 		if err != nil {
 			return nil, fmt.Errorf("error inserting new item scheme record: %w", err)
 		}
@@ -193,12 +237,18 @@ func (s *PostgresStore) ManagerEditItemFinancialByItemId(itemFinance types.ItemF
             SET discount = $2, minimum_quantity = $3, start_date = $4, end_date = $5
             WHERE item_id = $1
         `
-		_, err = s.db.Exec(updateSchemeQuery, itemFinance.ItemID, itemFinance.Discount, itemFinance.MinimumQuantity, itemFinance.StartDate, itemFinance.EndDate)
+		_, err = tx.Exec(updateSchemeQuery, itemFinance.ItemID, itemFinance.Discount, itemFinance.MinimumQuantity, itemFinance.StartDate, itemFinance.EndDate)
 		if err != nil {
 			return nil, fmt.Errorf("error updating existing item scheme record: %w", err)
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidateAllItems()
+	s.refreshRepricedCarts(carts)
+
 	// Retrieve the updated item financial details along with item scheme details
 	return s.ManagerGetItemFinancialByItemId(itemFinance.ItemID)
 }
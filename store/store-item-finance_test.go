@@ -0,0 +1,46 @@
+package store
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeItemTax(t *testing.T) {
+	tests := []struct {
+		name              string
+		totalGST          float64
+		buyPrice, mrp     float64
+		gstRate, cessRate float64
+		buyIncTax         float64
+		gstOnBuy          float64
+		cessOnBuy         float64
+		gstOnMRP          float64
+		cessOnMRP         float64
+	}{
+		{"exempt", 0, 100, 150, 0, 0, 100, 0, 0, 0, 0},
+		{"five percent", 5, 100, 210, 5, 0, 105, 5, 0, 10, 0},
+		{"eighteen percent", 18, 100, 118, 18, 0, 118, 18, 0, 18, 0},
+		{"cess above 28", 40, 100, 140, 28, 12, 140, 28, 12, 28, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeItemTax(tt.totalGST, tt.buyPrice, tt.mrp)
+			for _, c := range []struct {
+				field     string
+				got, want float64
+			}{
+				{"gstRate", got.gstRate, tt.gstRate},
+				{"cessRate", got.cessRate, tt.cessRate},
+				{"buyPriceIncTax", got.buyPriceIncTax, tt.buyIncTax},
+				{"gstOnBuy", got.gstOnBuy, tt.gstOnBuy},
+				{"cessOnBuy", got.cessOnBuy, tt.cessOnBuy},
+				{"gstOnMRP", got.gstOnMRP, tt.gstOnMRP},
+				{"cessOnMRP", got.cessOnMRP, tt.cessOnMRP},
+			} {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
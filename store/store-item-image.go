@@ -0,0 +1,335 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"log"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// itemImageMaxPixels rejects images that are small on disk but huge once decoded.
+	itemImageMaxPixels   = 40_000_000
+	itemImageMedium      = 600
+	itemImageThumbnail   = 200
+	itemImageJPEGQuality = 85
+)
+
+// decodeItemImage accepts JPEG and PNG only and returns the image with its format.
+func decodeItemImage(data []byte) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("image is not a valid JPEG or PNG: %w", err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, "", fmt.Errorf("unsupported image format %q, use JPEG or PNG", format)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > itemImageMaxPixels {
+		return nil, "", fmt.Errorf("image of %dx%d pixels is too large", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("error decoding %s image: %w", format, err)
+	}
+	return img, format, nil
+}
+
+// flattenImage draws img onto a white canvas, so transparent PNGs encode cleanly as JPEG.
+func flattenImage(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// downscale fits src into a maxSide square with a box filter. Images that already fit are returned as is.
+func downscale(src *image.RGBA, maxSide int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw <= maxSide && sh <= maxSide {
+		return src
+	}
+	dw, dh := maxSide, sh*maxSide/sw
+	if sh > sw {
+		dw, dh = sw*maxSide/sh, maxSide
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, (y+1)*sh/dh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, (x+1)*sw/dw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+			d := dst.Pix[y*dst.Stride+x*4 : y*dst.Stride+x*4+4]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: itemImageJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("error encoding jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UploadItemImage validates an uploaded JPEG or PNG, stores it with a medium and a thumbnail
+// variant and appends it to the item's images.
+func (s *PostgresStore) UploadItemImage(itemID int, data []byte) (*types.ItemImage, error) {
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM item WHERE id = $1)`, itemID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking item %d: %w", itemID, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("item with id = [%d] not found", itemID)
+	}
+
+	img, format, err := decodeItemImage(data)
+	if err != nil {
+		return nil, err
+	}
+	flat := flattenImage(img)
+	medium := downscale(flat, itemImageMedium)
+	thumbnail := downscale(medium, itemImageThumbnail)
+
+	mediumData, err := encodeJPEG(medium)
+	if err != nil {
+		return nil, err
+	}
+	thumbnailData, err := encodeJPEG(thumbnail)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	prefix := fmt.Sprintf("items/%d/%s", itemID, uuid.NewString())
+	originalKey, contentType := prefix+"/original.jpg", "image/jpeg"
+	if format == "png" {
+		originalKey, contentType = prefix+"/original.png", "image/png"
+	}
+
+	uploads := []struct {
+		key         string
+		contentType string
+		data        []byte
+	}{
+		{originalKey, contentType, data},
+		{prefix + "/medium.jpg", "image/jpeg", mediumData},
+		{prefix + "/thumbnail.jpg", "image/jpeg", thumbnailData},
+	}
+	urls := make([]string, 0, len(uploads))
+	for _, u := range uploads {
+		url, err := s.images.put(ctx, u.key, u.contentType, u.data)
+		if err != nil {
+			s.removeItemImageFiles(prefix)
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	itemImage, err := s.insertUploadedItemImage(itemID, prefix, urls, flat.Bounds().Dx(), medium.Bounds().Dx(), thumbnail.Bounds().Dx())
+	if err != nil {
+		s.removeItemImageFiles(prefix)
+		return nil, err
+	}
+
+	s.catalog.invalidateItems(0, itemID)
+	return itemImage, nil
+}
+
+func (s *PostgresStore) insertUploadedItemImage(itemID int, prefix string, urls []string, width, mediumWidth, thumbnailWidth int) (*types.ItemImage, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the item serializes concurrent uploads, which would otherwise pick the same position.
+	if _, err := tx.Exec(`SELECT id FROM item WHERE id = $1 FOR UPDATE`, itemID); err != nil {
+		return nil, fmt.Errorf("error locking item %d: %w", itemID, err)
+	}
+
+	query := `
+	INSERT INTO item_image (item_id, image_url, order_position, storage_prefix, width, medium_url, medium_width, thumbnail_url, thumbnail_width)
+	VALUES ($1, $2, (SELECT COALESCE(MAX(order_position), 0) + 1 FROM item_image WHERE item_id = $1), $3, $4, $5, $6, $7, $8)
+	RETURNING id, item_id, image_url, order_position, width, medium_url, thumbnail_url, srcset`
+
+	itemImage := &types.ItemImage{}
+	err = tx.QueryRow(query, itemID, urls[0], prefix, width, urls[1], mediumWidth, urls[2], thumbnailWidth).Scan(
+		&itemImage.ID, &itemImage.ItemID, &itemImage.ImageURL, &itemImage.OrderPosition,
+		&itemImage.Width, &itemImage.MediumURL, &itemImage.ThumbnailURL, &itemImage.Srcset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting into item_image: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return itemImage, nil
+}
+
+// removeItemImageFiles deletes every variant stored under prefix. Failures only leave orphaned files, so they are logged.
+func (s *PostgresStore) removeItemImageFiles(prefix string) {
+	ctx := context.Background()
+	for _, name := range []string{"original.jpg", "original.png", "medium.jpg", "thumbnail.jpg"} {
+		if err := s.images.remove(ctx, prefix+"/"+name); err != nil {
+			log.Printf("item images: %v", err)
+		}
+	}
+}
+
+// renumberItemImages sets order_position 1..n in the order of ids. Positions are moved out of the way
+// first because the (item_id, order_position) unique constraint is checked row by row.
+func renumberItemImages(tx *sql.Tx, itemID int, ids []int) error {
+	if _, err := tx.Exec(`UPDATE item_image SET order_position = -order_position WHERE item_id = $1`, itemID); err != nil {
+		return fmt.Errorf("error reordering images of item %d: %w", itemID, err)
+	}
+
+	query := `
+	UPDATE item_image ii
+	SET order_position = o.ord
+	FROM unnest($2::int[]) WITH ORDINALITY AS o(id, ord)
+	WHERE ii.id = o.id AND ii.item_id = $1`
+
+	if _, err := tx.Exec(query, itemID, pq.Array(ids)); err != nil {
+		return fmt.Errorf("error reordering images of item %d: %w", itemID, err)
+	}
+	return nil
+}
+
+func itemImageIDs(tx *sql.Tx, itemID int) ([]int, error) {
+	rows, err := tx.Query(`SELECT id FROM item_image WHERE item_id = $1 ORDER BY order_position FOR UPDATE`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying images of item %d: %w", itemID, err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning item image: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ReorderItemImages sets the order of all images of an item at once; the first image is the item's main image.
+func (s *PostgresStore) ReorderItemImages(reorder types.ItemImageReorder) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := itemImageIDs(tx, reorder.ItemID)
+	if err != nil {
+		return err
+	}
+	known := make(map[int]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+	seen := make(map[int]bool, len(reorder.ImageIDs))
+	for _, id := range reorder.ImageIDs {
+		if !known[id] {
+			return fmt.Errorf("image %d does not belong to item %d", id, reorder.ItemID)
+		}
+		if seen[id] {
+			return fmt.Errorf("image %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(current) {
+		return fmt.Errorf("image_ids must list all %d images of the item, got %d", len(current), len(seen))
+	}
+
+	if err := renumberItemImages(tx, reorder.ItemID, reorder.ImageIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.catalog.invalidateItems(0, reorder.ItemID)
+	return nil
+}
+
+// DeleteItemImage removes an image, closes the gap in the positions and deletes its stored files.
+func (s *PostgresStore) DeleteItemImage(itemID int, imageID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := itemImageIDs(tx, itemID)
+	if err != nil {
+		return err
+	}
+
+	var prefix sql.NullString
+	err = tx.QueryRow(`DELETE FROM item_image WHERE id = $1 AND item_id = $2 RETURNING storage_prefix`, imageID, itemID).Scan(&prefix)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("image %d does not belong to item %d", imageID, itemID)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting image %d: %w", imageID, err)
+	}
+
+	remaining := make([]int, 0, len(current))
+	for _, id := range current {
+		if id != imageID {
+			remaining = append(remaining, id)
+		}
+	}
+	if err := renumberItemImages(tx, itemID, remaining); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.catalog.invalidateItems(0, itemID)
+	if prefix.Valid {
+		s.removeItemImageFiles(prefix.String)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// itemStatusTransitions lists the statuses an item may move to from each status. A discontinued
+// item comes back as a draft, so it passes the activation gates again.
+var itemStatusTransitions = map[string][]string{
+	types.ItemDraft:        {types.ItemActive, types.ItemDiscontinued},
+	types.ItemActive:       {types.ItemPaused, types.ItemDiscontinued},
+	types.ItemPaused:       {types.ItemActive, types.ItemDiscontinued},
+	types.ItemDiscontinued: {types.ItemDraft},
+}
+
+// itemStatusHistoryLimit bounds the status changes returned with an item's lifecycle.
+const itemStatusHistoryLimit = 50
+
+// CreateItemLifecycle adds the lifecycle status to item and a log of its changes. Items that exist
+// already are active; new items, whether added by a manager, created or imported, start as drafts.
+func (s *PostgresStore) CreateItemLifecycle(tx *sql.Tx) error {
+	query := `
+    ALTER TABLE item
+        ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+            CHECK (status IN ('draft', 'active', 'paused', 'discontinued')),
+        ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+    CREATE INDEX IF NOT EXISTS item_status ON item (status) WHERE status <> 'active';
+
+    CREATE TABLE IF NOT EXISTS item_status_change (
+        id SERIAL PRIMARY KEY,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        from_status TEXT NOT NULL,
+        to_status TEXT NOT NULL,
+        reason TEXT NOT NULL DEFAULT '',
+        changed_by INT,
+        changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS item_status_change_item ON item_status_change (item_id, changed_at);`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating item lifecycle: %w", err)
+	}
+	return nil
+}
+
+// itemHasTaxClause is true when item i, joined to its item_financial row as ifin, has a tax_id.
+// Invoices join the tax through item_financial, so an ItemTax row alone does not count. The
+// activation gate and the catalog health report share it so they agree on which items miss a tax.
+const itemHasTaxClause = `(ifin.tax_id IS NOT NULL)`
+
+// itemActivationGaps returns what the item lacks before it can be activated: an MRP, a tax, an
+// image, a barcode, and a shelf in a store that lists it. Bundles are picked from their components'
+// shelves and need no shelf of their own.
+func itemActivationGaps(q queryer, itemID int) ([]string, error) {
+	var price, tax, image, barcode, shelf bool
+	err := q.QueryRow(`
+        SELECT COALESCE(ifin.mrp_price, 0) > 0,
+               `+itemHasTaxClause+`,
+               EXISTS (SELECT 1 FROM item_image ii WHERE ii.item_id = i.id),
+               COALESCE(i.barcode, '') <> '' OR EXISTS (SELECT 1 FROM item_barcode ib WHERE ib.item_id = i.id),
+               EXISTS (SELECT 1 FROM bundle_component bc WHERE bc.bundle_item_id = i.id) OR EXISTS (
+                   SELECT 1 FROM shelf sh
+                   JOIN item_store istore ON istore.item_id = sh.item_id AND istore.store_id = sh.store_id
+                   WHERE sh.item_id = i.id AND istore.listed
+               )
+        FROM item i
+        LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+        WHERE i.id = $1`, itemID).Scan(&price, &tax, &image, &barcode, &shelf)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item %d does not exist", itemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking item %d: %w", itemID, err)
+	}
+
+	missing := []string{}
+	for _, gate := range []struct {
+		ok   bool
+		name string
+	}{{price, "price"}, {tax, "tax"}, {image, "image"}, {barcode, "barcode"}, {shelf, "shelf"}} {
+		if !gate.ok {
+			missing = append(missing, gate.name)
+		}
+	}
+	return missing, nil
+}
+
+func (s *PostgresStore) GetItemLifecycle(itemID int) (*types.ItemLifecycle, error) {
+	lifecycle := &types.ItemLifecycle{ItemID: itemID}
+	err := s.db.QueryRow(`SELECT status FROM item WHERE id = $1`, itemID).Scan(&lifecycle.Status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item %d does not exist", itemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying item status: %w", err)
+	}
+
+	if lifecycle.Missing, err = itemActivationGaps(s.db, itemID); err != nil {
+		return nil, err
+	}
+	lifecycle.Ready = len(lifecycle.Missing) == 0
+
+	rows, err := s.db.Query(`
+        SELECT from_status, to_status, reason, COALESCE(changed_by, 0), changed_at
+        FROM item_status_change
+        WHERE item_id = $1
+        ORDER BY changed_at DESC, id DESC
+        LIMIT $2`, itemID, itemStatusHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying item status changes: %w", err)
+	}
+	defer rows.Close()
+
+	lifecycle.History = []types.ItemStatusChange{}
+	for rows.Next() {
+		var change types.ItemStatusChange
+		var changedAt time.Time
+		if err := rows.Scan(&change.FromStatus, &change.ToStatus, &change.Reason, &change.ChangedBy, &changedAt); err != nil {
+			return nil, fmt.Errorf("error scanning item status change: %w", err)
+		}
+		change.ChangedAt = changedAt.Format(time.RFC3339)
+		lifecycle.History = append(lifecycle.History, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+// SetItemStatus moves an item to another lifecycle status. Activation is refused while the item
+// misses any of the activation gates. An item that stops being active is taken out of the open
+// carts that are not checking out.
+func (s *PostgresStore) SetItemStatus(req types.SetItemStatus) (*types.ItemLifecycle, error) {
+	if _, ok := itemStatusTransitions[req.Status]; !ok {
+		return nil, fmt.Errorf("unknown item status %q", req.Status)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRow(`SELECT status FROM item WHERE id = $1 FOR UPDATE`, req.ItemID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item %d does not exist", req.ItemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying item status: %w", err)
+	}
+	if current == req.Status {
+		tx.Rollback()
+		return s.GetItemLifecycle(req.ItemID)
+	}
+
+	allowed := false
+	for _, next := range itemStatusTransitions[current] {
+		allowed = allowed || next == req.Status
+	}
+	if !allowed {
+		return nil, fmt.Errorf("item %d cannot move from %s to %s", req.ItemID, current, req.Status)
+	}
+
+	if req.Status == types.ItemActive {
+		missing, err := itemActivationGaps(tx, req.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("item %d cannot be activated, it is missing: %s", req.ItemID, strings.Join(missing, ", "))
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE item SET status = $2, status_changed_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		req.ItemID, req.Status); err != nil {
+		return nil, fmt.Errorf("error updating item status: %w", err)
+	}
+	_, err = tx.Exec(`
+        INSERT INTO item_status_change (item_id, from_status, to_status, reason, changed_by)
+        VALUES ($1, $2, $3, $4, NULLIF($5, 0))`, req.ItemID, current, req.Status, req.Reason, req.ChangedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error recording item status change: %w", err)
+	}
+
+	carts := &repricedCarts{}
+	if current == types.ItemActive {
+		if carts, err = removeItemFromOpenCarts(tx, req.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	// activation can add the item to listings that did not contain it
+	s.catalog.invalidateAllItems()
+	s.catalog.invalidatePrefix(catalogKeyAutocomplete)
+	s.refreshRepricedCarts(carts)
+
+	lifecycle, err := s.GetItemLifecycle(req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle.RemovedFromCarts = len(carts.plain) + len(carts.promo)
+	return lifecycle, nil
+}
+
+// removeItemFromOpenCarts deletes the item from active carts that are not checking out and returns
+// the carts whose totals need recomputing.
+func removeItemFromOpenCarts(tx *sql.Tx, itemID int) (*repricedCarts, error) {
+	rows, err := tx.Query(`
+        DELETE FROM cart_item ci
+        USING shopping_cart sc
+        WHERE sc.id = ci.cart_id AND sc.active AND ci.item_id = $1
+          AND NOT EXISTS (SELECT 1 FROM cart_lock cl WHERE cl.cart_id = sc.id AND cl.completed = 'started')
+        RETURNING ci.cart_id, COALESCE(sc.promo_code, '') <> ''`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("error removing item %d from carts: %w", itemID, err)
+	}
+	defer rows.Close()
+
+	carts := &repricedCarts{}
+	for rows.Next() {
+		var cartID int
+		var promo bool
+		if err := rows.Scan(&cartID, &promo); err != nil {
+			return nil, err
+		}
+		if promo {
+			carts.promo = append(carts.promo, cartID)
+		} else {
+			carts.plain = append(carts.plain, cartID)
+		}
+	}
+	return carts, rows.Err()
+}
@@ -0,0 +1,575 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// priceSchedulerInterval is how often RunPriceScheduler looks for due price changes.
+const priceSchedulerInterval = time.Minute
+
+// priceHistoryLimit bounds the rows returned by GetItemPriceHistory.
+const priceHistoryLimit = 200
+
+// CreateItemPriceTables creates the scheduled price changes and the price history. Triggers on
+// item_financial and item_store record every price write, whichever code path made it; a write
+// tags its rows by setting pronto.price_source (and pronto.price_change_id) for its transaction.
+func (s *PostgresStore) CreateItemPriceTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS item_price_change (
+        id SERIAL PRIMARY KEY,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        store_id INT REFERENCES store(id) ON DELETE CASCADE,
+        mrp_price DECIMAL(10, 2) CHECK (mrp_price >= 0),
+        buy_price DECIMAL(10, 2) CHECK (buy_price >= 0),
+        store_price DECIMAL(10, 2) CHECK (store_price >= 0),
+        effective_at TIMESTAMP NOT NULL,
+        status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'applied', 'cancelled', 'failed')),
+        error TEXT,
+        reason TEXT NOT NULL DEFAULT '',
+        applied_at TIMESTAMP,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        created_by INT,
+        CHECK (mrp_price IS NOT NULL OR buy_price IS NOT NULL OR store_price IS NOT NULL)
+    );
+
+    CREATE INDEX IF NOT EXISTS item_price_change_due ON item_price_change (effective_at) WHERE status = 'scheduled';
+    CREATE INDEX IF NOT EXISTS item_price_change_item ON item_price_change (item_id, effective_at);
+
+    CREATE TABLE IF NOT EXISTS item_price_history (
+        id BIGSERIAL PRIMARY KEY,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        store_id INT REFERENCES store(id) ON DELETE CASCADE,
+        mrp_price DECIMAL(10, 2),
+        buy_price DECIMAL(10, 2),
+        store_price INT,
+        discount INT,
+        source TEXT NOT NULL,
+        change_id INT REFERENCES item_price_change(id) ON DELETE SET NULL,
+        changed_at TIMESTAMP NOT NULL DEFAULT clock_timestamp()
+    );
+
+    CREATE INDEX IF NOT EXISTS item_price_history_item ON item_price_history (item_id, changed_at);
+
+    CREATE OR REPLACE FUNCTION record_item_price_history() RETURNS TRIGGER AS $$
+    DECLARE
+        v_source TEXT := COALESCE(NULLIF(current_setting('pronto.price_source', true), ''), 'edit');
+        v_change_id INT := NULLIF(current_setting('pronto.price_change_id', true), '')::int;
+    BEGIN
+        IF TG_TABLE_NAME = 'item_financial' THEN
+            IF TG_OP = 'UPDATE' AND NEW.mrp_price IS NOT DISTINCT FROM OLD.mrp_price
+                AND NEW.buy_price IS NOT DISTINCT FROM OLD.buy_price THEN
+                RETURN NEW;
+            END IF;
+            INSERT INTO item_price_history (item_id, mrp_price, buy_price, source, change_id)
+            VALUES (NEW.item_id, NEW.mrp_price, NEW.buy_price, v_source, v_change_id);
+        ELSE
+            IF TG_OP = 'UPDATE' AND NEW.store_price IS NOT DISTINCT FROM OLD.store_price
+                AND NEW.discount IS NOT DISTINCT FROM OLD.discount THEN
+                RETURN NEW;
+            END IF;
+            INSERT INTO item_price_history (item_id, store_id, store_price, discount, source, change_id)
+            VALUES (NEW.item_id, NEW.store_id, NEW.store_price, NEW.discount, v_source, v_change_id);
+        END IF;
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS item_financial_price_history ON item_financial;
+    CREATE TRIGGER item_financial_price_history AFTER INSERT OR UPDATE OF mrp_price, buy_price ON item_financial
+    FOR EACH ROW EXECUTE FUNCTION record_item_price_history();
+
+    DROP TRIGGER IF EXISTS item_store_price_history ON item_store;
+    CREATE TRIGGER item_store_price_history AFTER INSERT OR UPDATE OF store_price, discount ON item_store
+    FOR EACH ROW EXECUTE FUNCTION record_item_price_history();
+
+    INSERT INTO item_price_history (item_id, mrp_price, buy_price, source)
+    SELECT ifin.item_id, ifin.mrp_price, ifin.buy_price, 'initial'
+    FROM item_financial ifin
+    WHERE NOT EXISTS (SELECT 1 FROM item_price_history h WHERE h.item_id = ifin.item_id AND h.store_id IS NULL);
+
+    INSERT INTO item_price_history (item_id, store_id, store_price, discount, source)
+    SELECT istore.item_id, istore.store_id, istore.store_price, istore.discount, 'initial'
+    FROM item_store istore
+    WHERE istore.item_id IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM item_price_history h WHERE h.item_id = istore.item_id AND h.store_id = istore.store_id
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating item price tables: %w", err)
+	}
+	return nil
+}
+
+// setPriceSource tags the price history rows written by tx with source.
+func setPriceSource(tx *sql.Tx, source string) error {
+	if _, err := tx.Exec(`SELECT set_config('pronto.price_source', $1, true)`, source); err != nil {
+		return fmt.Errorf("error tagging price source: %w", err)
+	}
+	return nil
+}
+
+func scanItemPriceChange(row interface{ Scan(...interface{}) error }) (*types.ItemPriceChange, error) {
+	var change types.ItemPriceChange
+	var storeID, createdBy sql.NullInt64
+	var mrp, buy, storePrice sql.NullFloat64
+	var errText sql.NullString
+	var appliedAt sql.NullTime
+	var effectiveAt, createdAt time.Time
+
+	err := row.Scan(&change.ID, &change.ItemID, &storeID, &mrp, &buy, &storePrice, &effectiveAt, &change.Status,
+		&errText, &change.Reason, &appliedAt, &createdAt, &createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	change.StoreID = int(storeID.Int64)
+	change.CreatedBy = int(createdBy.Int64)
+	change.Error = errText.String
+	change.EffectiveAt = effectiveAt.Format(time.RFC3339)
+	change.CreatedAt = createdAt.Format(time.RFC3339)
+	if mrp.Valid {
+		change.MRPPrice = &mrp.Float64
+	}
+	if buy.Valid {
+		change.BuyPrice = &buy.Float64
+	}
+	if storePrice.Valid {
+		change.StorePrice = &storePrice.Float64
+	}
+	if appliedAt.Valid {
+		applied := appliedAt.Time.Format(time.RFC3339)
+		change.AppliedAt = &applied
+	}
+	return &change, nil
+}
+
+const itemPriceChangeColumns = `id, item_id, store_id, mrp_price, buy_price, store_price, effective_at, status,
+        error, reason, applied_at, created_at, created_by`
+
+// SchedulePriceChange records a future price change. Changes already due are applied before returning.
+func (s *PostgresStore) SchedulePriceChange(req types.SchedulePriceChange) (*types.ItemPriceChange, error) {
+	if req.MRPPrice == nil && req.BuyPrice == nil && req.StorePrice == nil {
+		return nil, fmt.Errorf("a price change needs an mrp_price, buy_price or store_price")
+	}
+	for _, price := range []*float64{req.MRPPrice, req.BuyPrice, req.StorePrice} {
+		if price != nil && *price < 0 {
+			return nil, fmt.Errorf("prices cannot be negative")
+		}
+	}
+	if req.MRPPrice != nil && req.StorePrice != nil && *req.StorePrice > *req.MRPPrice {
+		return nil, fmt.Errorf("store_price %.2f is above mrp_price %.2f", *req.StorePrice, *req.MRPPrice)
+	}
+
+	effectiveAt := time.Now()
+	if req.EffectiveAt != "" {
+		t, err := time.Parse(time.RFC3339, req.EffectiveAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effective_at provided: %w", err)
+		}
+		effectiveAt = t
+	}
+
+	var sold bool
+	err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM item_store WHERE item_id = $1 AND ($2 = 0 OR store_id = $2))`,
+		req.ItemID, req.StoreID).Scan(&sold)
+	if err != nil {
+		return nil, fmt.Errorf("error checking item %d: %w", req.ItemID, err)
+	}
+	if !sold {
+		return nil, fmt.Errorf("item %d is not sold in store %d", req.ItemID, req.StoreID)
+	}
+
+	// effective_at is a TIMESTAMP in the database's time zone, like every other timestamp here
+	row := s.db.QueryRow(`
+        INSERT INTO item_price_change (item_id, store_id, mrp_price, buy_price, store_price, effective_at, reason, created_by)
+        VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6::timestamptz, $7, NULLIF($8, 0))
+        RETURNING `+itemPriceChangeColumns,
+		req.ItemID, req.StoreID, req.MRPPrice, req.BuyPrice, req.StorePrice, effectiveAt, req.Reason, req.CreatedBy)
+	change, err := scanItemPriceChange(row)
+	if err != nil {
+		return nil, fmt.Errorf("error scheduling price change: %w", err)
+	}
+
+	if !effectiveAt.After(time.Now()) {
+		if _, err := s.ApplyDuePriceChanges(); err != nil {
+			return nil, err
+		}
+		return s.getItemPriceChange(change.ID)
+	}
+	return change, nil
+}
+
+func (s *PostgresStore) getItemPriceChange(id int) (*types.ItemPriceChange, error) {
+	row := s.db.QueryRow(`SELECT `+itemPriceChangeColumns+` FROM item_price_change WHERE id = $1`, id)
+	change, err := scanItemPriceChange(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("price change %d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying price change %d: %w", id, err)
+	}
+	return change, nil
+}
+
+// CancelPriceChange cancels a change that has not been applied yet.
+func (s *PostgresStore) CancelPriceChange(id int) (*types.ItemPriceChange, error) {
+	res, err := s.db.Exec(`UPDATE item_price_change SET status = 'cancelled' WHERE id = $1 AND status = 'scheduled'`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error cancelling price change %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		change, err := s.getItemPriceChange(id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("price change %d is already %s", id, change.Status)
+	}
+	return s.getItemPriceChange(id)
+}
+
+// ApplyDuePriceChanges applies every scheduled change whose time has come, oldest first, and returns
+// how many were applied. A change that cannot be applied is marked failed with its error. Several
+// instances may run it at once; each change is applied once.
+func (s *PostgresStore) ApplyDuePriceChanges() (int, error) {
+	applied := 0
+	for {
+		change, carts, err := s.applyNextPriceChange()
+		if err != nil {
+			return applied, err
+		}
+		if change == nil {
+			return applied, nil
+		}
+		if change.Status == types.PriceChangeApplied {
+			applied++
+			s.catalog.invalidateAllItems()
+			s.refreshRepricedCarts(carts)
+		}
+	}
+}
+
+// repricedCarts lists the carts whose items were re-priced by a change. Carts with a promo code get
+// the promo applied again, since it is computed from the MRP.
+type repricedCarts struct {
+	plain []int
+	promo []int
+}
+
+func (s *PostgresStore) applyNextPriceChange() (*types.ItemPriceChange, *repricedCarts, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(`
+        SELECT ` + itemPriceChangeColumns + `
+        FROM item_price_change
+        WHERE status = 'scheduled' AND effective_at <= CURRENT_TIMESTAMP
+        ORDER BY effective_at, id
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED`)
+	change, err := scanItemPriceChange(row)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying due price changes: %w", err)
+	}
+
+	if err := setPriceSource(tx, "schedule"); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`SELECT set_config('pronto.price_change_id', $1, true)`, fmt.Sprint(change.ID)); err != nil {
+		return nil, nil, fmt.Errorf("error tagging price change %d: %w", change.ID, err)
+	}
+
+	if _, err := tx.Exec(`SAVEPOINT price_change`); err != nil {
+		return nil, nil, err
+	}
+	carts, applyErr := applyPriceChange(tx, change)
+	if applyErr != nil {
+		if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT price_change`); err != nil {
+			return nil, nil, err
+		}
+		log.Printf("price change %d failed: %v", change.ID, applyErr)
+		change.Status = types.PriceChangeFailed
+		_, err = tx.Exec(`UPDATE item_price_change SET status = 'failed', error = $2 WHERE id = $1`, change.ID, applyErr.Error())
+	} else {
+		change.Status = types.PriceChangeApplied
+		_, err = tx.Exec(`UPDATE item_price_change SET status = 'applied', applied_at = CURRENT_TIMESTAMP WHERE id = $1`, change.ID)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating price change %d: %w", change.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return change, carts, nil
+}
+
+// applyPriceChangeNow records req as a change effective now and applies it inside tx, for edits
+// that change a price together with other item data. A change that cannot be applied fails tx
+// rather than being marked failed.
+func applyPriceChangeNow(tx *sql.Tx, req types.SchedulePriceChange) (*types.ItemPriceChange, *repricedCarts, error) {
+	row := tx.QueryRow(`
+        INSERT INTO item_price_change (item_id, store_id, mrp_price, buy_price, store_price, effective_at, reason, created_by)
+        VALUES ($1, NULLIF($2, 0), $3, $4, $5, CURRENT_TIMESTAMP, $6, NULLIF($7, 0))
+        RETURNING `+itemPriceChangeColumns,
+		req.ItemID, req.StoreID, req.MRPPrice, req.BuyPrice, req.StorePrice, req.Reason, req.CreatedBy)
+	change, err := scanItemPriceChange(row)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error recording price change: %w", err)
+	}
+
+	if err := setPriceSource(tx, "schedule"); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`SELECT set_config('pronto.price_change_id', $1, true)`, fmt.Sprint(change.ID)); err != nil {
+		return nil, nil, fmt.Errorf("error tagging price change %d: %w", change.ID, err)
+	}
+
+	carts, err := applyPriceChange(tx, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`UPDATE item_price_change SET status = 'applied', applied_at = CURRENT_TIMESTAMP WHERE id = $1`, change.ID); err != nil {
+		return nil, nil, fmt.Errorf("error updating price change %d: %w", change.ID, err)
+	}
+	change.Status = types.PriceChangeApplied
+	return change, carts, nil
+}
+
+// applyPriceChange writes the prices of change and re-prices the active carts holding the item, except
+// carts in checkout, which keep the price the customer is paying.
+func applyPriceChange(tx *sql.Tx, change *types.ItemPriceChange) (*repricedCarts, error) {
+	if change.MRPPrice != nil || change.BuyPrice != nil {
+		var mrp, buy, totalTax float64
+		err := tx.QueryRow(`
+            SELECT COALESCE(ifin.mrp_price, 0), COALESCE(ifin.buy_price - ifin.gst_on_buy - ifin.cess_on_buy, 0),
+                   COALESCE(t.gst + t.cess, 0) / 100.0
+            FROM item_financial ifin
+            LEFT JOIN tax t ON t.id = ifin.tax_id
+            WHERE ifin.item_id = $1
+            FOR UPDATE OF ifin`, change.ItemID).Scan(&mrp, &buy, &totalTax)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("item %d has no financial record", change.ItemID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error querying item financial: %w", err)
+		}
+
+		if change.MRPPrice != nil {
+			mrp = *change.MRPPrice
+		}
+		if change.BuyPrice != nil {
+			buy = *change.BuyPrice
+		}
+		tax := computeItemTax(totalTax, buy, mrp)
+
+		_, err = tx.Exec(`
+            UPDATE item_financial
+            SET mrp_price = $2, buy_price = $3, gst_on_buy = $4, cess_on_buy = $5, gst_on_mrp = $6, cess_on_mrp = $7
+            WHERE item_id = $1`,
+			change.ItemID, mrp, tax.buyPriceIncTax, tax.gstOnBuy, tax.cessOnBuy, tax.gstOnMRP, tax.cessOnMRP)
+		if err != nil {
+			return nil, fmt.Errorf("error updating item financial: %w", err)
+		}
+	}
+
+	if change.StorePrice != nil {
+		storePrice := math.Round(*change.StorePrice)
+		rows, err := tx.Query(`
+            UPDATE item_store istore
+            SET store_price = $2, discount = ROUND(ifin.mrp_price - $2), price_override = NULL
+            FROM item_financial ifin
+            WHERE ifin.item_id = istore.item_id AND istore.item_id = $1 AND ($3 = 0 OR istore.store_id = $3)
+            RETURNING istore.discount`, change.ItemID, storePrice, change.StoreID)
+		if err != nil {
+			return nil, fmt.Errorf("error updating store price: %w", err)
+		}
+		updated := 0
+		for rows.Next() {
+			var discount int
+			if err := rows.Scan(&discount); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if discount < 0 {
+				rows.Close()
+				return nil, fmt.Errorf("store_price %.0f is above the mrp of item %d", storePrice, change.ItemID)
+			}
+			updated++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		if updated == 0 {
+			return nil, fmt.Errorf("item %d is not sold in store %d", change.ItemID, change.StoreID)
+		}
+	}
+
+	// an MRP change moves the selling price of every store, a store price only that store's
+	storeID := change.StoreID
+	if change.MRPPrice != nil {
+		storeID = 0
+	}
+
+	carts := &repricedCarts{}
+	rows, err := tx.Query(`
+        UPDATE cart_item ci
+        SET sold_price = p.price
+        FROM shopping_cart sc, store_item_price p
+        WHERE sc.id = ci.cart_id AND sc.active AND COALESCE(sc.promo_code, '') = ''
+          AND p.item_id = ci.item_id AND p.store_id = sc.store_id
+          AND ci.item_id = $1 AND ($2 = 0 OR sc.store_id = $2)
+          AND NOT EXISTS (SELECT 1 FROM cart_lock cl WHERE cl.cart_id = sc.id AND cl.completed = 'started')
+        RETURNING ci.cart_id`, change.ItemID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error re-pricing carts: %w", err)
+	}
+	if carts.plain, err = scanIDs(rows); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(`
+        SELECT DISTINCT sc.id
+        FROM shopping_cart sc
+        JOIN cart_item ci ON ci.cart_id = sc.id
+        WHERE sc.active AND COALESCE(sc.promo_code, '') <> ''
+          AND ci.item_id = $1 AND ($2 = 0 OR sc.store_id = $2)
+          AND NOT EXISTS (SELECT 1 FROM cart_lock cl WHERE cl.cart_id = sc.id AND cl.completed = 'started')`,
+		change.ItemID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying promo carts: %w", err)
+	}
+	if carts.promo, err = scanIDs(rows); err != nil {
+		return nil, err
+	}
+	return carts, nil
+}
+
+func scanIDs(rows *sql.Rows) ([]int, error) {
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// refreshRepricedCarts recomputes the totals of re-priced carts. Failures are logged; the cart is
+// recomputed again on its next change.
+func (s *PostgresStore) refreshRepricedCarts(carts *repricedCarts) {
+	for _, cartID := range carts.promo {
+		if err := s.ApplyExistingPromo(cartID); err != nil {
+			log.Printf("error re-applying promo to cart %d: %v", cartID, err)
+		}
+	}
+	for _, cartID := range append(carts.plain, carts.promo...) {
+		if err := s.CalculateCartTotal(cartID); err != nil {
+			log.Printf("error recalculating cart %d: %v", cartID, err)
+		}
+	}
+}
+
+// RunPriceScheduler applies due price changes every priceSchedulerInterval until ctx is done.
+func (s *PostgresStore) RunPriceScheduler(ctx context.Context) error {
+	ticker := time.NewTicker(priceSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.ApplyDuePriceChanges(); err != nil {
+			log.Printf("price scheduler: %v", err)
+		} else if n > 0 {
+			log.Printf("price scheduler: applied %d price changes", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetItemPriceHistory returns the price changes and recorded prices of an item.
+func (s *PostgresStore) GetItemPriceHistory(req types.ItemPriceHistoryRequest) (*types.ItemPriceHistory, error) {
+	history := &types.ItemPriceHistory{
+		ItemID:  req.ItemID,
+		Changes: []types.ItemPriceChange{},
+		History: []types.ItemPriceHistoryEntry{},
+	}
+
+	rows, err := s.db.Query(`
+        SELECT `+itemPriceChangeColumns+`
+        FROM item_price_change
+        WHERE item_id = $1 AND ($2 = 0 OR store_id IS NULL OR store_id = $2)
+        ORDER BY effective_at DESC, id DESC
+        LIMIT $3`, req.ItemID, req.StoreID, priceHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying price changes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		change, err := scanItemPriceChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning price change: %w", err)
+		}
+		history.Changes = append(history.Changes, *change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query(`
+        SELECT id, COALESCE(store_id, 0), mrp_price, buy_price, store_price, discount, source, COALESCE(change_id, 0), changed_at
+        FROM item_price_history
+        WHERE item_id = $1 AND ($2 = 0 OR store_id IS NULL OR store_id = $2)
+        ORDER BY changed_at DESC, id DESC
+        LIMIT $3`, req.ItemID, req.StoreID, priceHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying price history: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var entry types.ItemPriceHistoryEntry
+		var mrp, buy sql.NullFloat64
+		var storePrice, discount sql.NullInt64
+		var changedAt time.Time
+		if err := rows.Scan(&entry.ID, &entry.StoreID, &mrp, &buy, &storePrice, &discount, &entry.Source, &entry.ChangeID, &changedAt); err != nil {
+			return nil, fmt.Errorf("error scanning price history: %w", err)
+		}
+		if mrp.Valid {
+			entry.MRPPrice = &mrp.Float64
+		}
+		if buy.Valid {
+			entry.BuyPrice = &buy.Float64
+		}
+		if storePrice.Valid {
+			v := int(storePrice.Int64)
+			entry.StorePrice = &v
+		}
+		if discount.Valid {
+			v := int(discount.Int64)
+			entry.Discount = &v
+		}
+		entry.ChangedAt = changedAt.Format(time.RFC3339)
+		history.History = append(history.History, entry)
+	}
+	return history, rows.Err()
+}
@@ -27,6 +27,7 @@ func (s *PostgresStore) RemoveLockQuantities(cart_id int) ([]itemStoreRecord, er
 			return nil, err
 		}
 		records = append(records, record)
+		s.catalog.invalidateStock(record.StoreID, record.ItemID)
 	}
 
 	err = rows.Err()
@@ -64,6 +65,7 @@ func (s *PostgresStore) UnlockQuantities(cart_id int) ([]itemStoreRecord, error)
 			return nil, err
 		}
 		records = append(records, record)
+		s.catalog.invalidateStock(record.StoreID, record.ItemID)
 	}
 
 	err = rows.Err()
@@ -115,6 +115,29 @@ func (s *PostgresStore) CreateItemImageTable(tx *sql.Tx) error {
 		return fmt.Errorf("error creating item_image table: %w", err)
 	}
 
+	// Uploaded images also carry resized variants; srcset is built from them and falls back to the
+	// bare URL for images added by link.
+	alterQuery := `
+	ALTER TABLE item_image
+		ADD COLUMN IF NOT EXISTS storage_prefix TEXT,
+		ADD COLUMN IF NOT EXISTS width INT,
+		ADD COLUMN IF NOT EXISTS medium_url TEXT,
+		ADD COLUMN IF NOT EXISTS medium_width INT,
+		ADD COLUMN IF NOT EXISTS thumbnail_url TEXT,
+		ADD COLUMN IF NOT EXISTS thumbnail_width INT,
+		ADD COLUMN IF NOT EXISTS srcset TEXT GENERATED ALWAYS AS (
+			CASE WHEN medium_url IS NULL OR thumbnail_url IS NULL OR width IS NULL THEN image_url
+			ELSE thumbnail_url || ' ' || thumbnail_width::text || 'w, '
+				|| medium_url || ' ' || medium_width::text || 'w, '
+				|| image_url || ' ' || width::text || 'w'
+			END
+		) STORED`
+
+	_, err = tx.Exec(alterQuery)
+	if err != nil {
+		return fmt.Errorf("error adding item_image variant columns: %w", err)
+	}
+
 	return err
 }
 
@@ -143,6 +166,7 @@ func (s *PostgresStore) CreateItemStoreTable(tx *sql.Tx) error {
 }
 
 func (s *PostgresStore) CreateItem(p *types.Item) (*types.Item, error) {
+	defer s.catalog.invalidateAllItems()
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
@@ -219,8 +243,8 @@ func (s *PostgresStore) CreateItem(p *types.Item) (*types.Item, error) {
 	}
 
 	// Insert into item table
-	query := `INSERT INTO item (name, brand_id, description, quantity, unit_of_quantity, created_by) 
-              VALUES ($1, $2, $3, $4, $5, $6) 
+	query := `INSERT INTO item (name, brand_id, description, quantity, unit_of_quantity, created_by, status) 
+              VALUES ($1, $2, $3, $4, $5, $6, 'draft') 
               RETURNING id, created_at`
 	err = tx.QueryRow(query, newItem.Name, brandID, newItem.Description, newItem.Quantity, newItem.Unit_Of_Quantity, newItem.Created_By).Scan(&newItem.ID, &newItem.Created_At)
 	if err != nil {
@@ -267,7 +291,7 @@ func (s *PostgresStore) CreateItem(p *types.Item) (*types.Item, error) {
 }
 
 func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
-	tx, err := s.db.Begin()
+	tx, err := s.reader(readCatalog).Begin()
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
@@ -284,7 +308,8 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 	), image_agg AS (
 		SELECT 
 			ii.item_id,
-			array_agg(ii.image_url) AS images
+			array_agg(ii.image_url ORDER BY ii.order_position) AS images,
+			array_agg(ii.srcset ORDER BY ii.order_position) AS srcsets
 		FROM item_image ii
 		GROUP BY ii.item_id
 	)
@@ -293,16 +318,18 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 		istore.mrp_price, istore.discount, istore.store_price, s.name, 
 		istore.stock_quantity, istore.locked_quantity,
 		COALESCE(ca.categories, ARRAY['No Category']::text[]) as categories,
-		COALESCE(ia.images, ARRAY[]::text[]) as images
+		COALESCE(ia.images, ARRAY[]::text[]) as images,
+		COALESCE(ia.srcsets, ARRAY[]::text[]) as srcsets
 	FROM item i
 	LEFT JOIN brand b ON i.brand_id = b.id
 	LEFT JOIN item_store istore ON i.id = istore.item_id
 	LEFT JOIN store s ON istore.store_id = s.id
 	LEFT JOIN category_agg ca ON i.id = ca.item_id
 	LEFT JOIN image_agg ia ON i.id = ia.item_id
+	WHERE i.status = 'active'
 	GROUP BY i.id, i.name, b.name, istore.mrp_price, istore.discount, 
 			 istore.store_price, s.name, istore.stock_quantity, 
-			 istore.locked_quantity, ca.categories, ia.images
+			 istore.locked_quantity, ca.categories, ia.images, ia.srcsets
 	ORDER BY i.id
 	
     `
@@ -320,6 +347,7 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 		var store string
 		var categories pq.StringArray
 		var images pq.StringArray
+		var srcsets pq.StringArray
 
 		err := rows.Scan(
 			&item.ID,
@@ -336,6 +364,7 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 			&item.Locked_Quantity,
 			&categories,
 			&images,
+			&srcsets,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into item: %w", err)
@@ -348,6 +377,9 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 		item.Images = make([]string, len(images))
 		copy(item.Images, images)
 
+		item.Srcset = make([]string, len(srcsets))
+		copy(item.Srcset, srcsets)
+
 		item.Stores = append(item.Stores, store)
 
 		items = append(items, item)
@@ -365,55 +397,183 @@ func (s *PostgresStore) GetItems() ([]*types.Get_Item, error) {
 	return items, nil
 }
 
+// ListItems is the paginated form of GetItems. Each row is an item at one store.
+func (s *PostgresStore) ListItems(params types.ListParams) (*types.Page[*types.Get_Item], error) {
+	q := &listQuery{
+		with: `category_agg AS (
+			SELECT ic.item_id, array_agg(COALESCE(c.name, 'No Category')) AS categories
+			FROM item_category ic
+			LEFT JOIN category c ON ic.category_id = c.id
+			GROUP BY ic.item_id
+		), image_agg AS (
+			SELECT ii.item_id, array_agg(ii.image_url ORDER BY ii.order_position) AS images,
+				array_agg(ii.srcset ORDER BY ii.order_position) AS srcsets
+			FROM item_image ii
+			GROUP BY ii.item_id
+		)`,
+		columns: `i.id, i.name, COALESCE(i.description, ''), i.quantity, i.unit_of_quantity, COALESCE(b.name, ''),
+			COALESCE(ifin.mrp_price, 0), COALESCE(istore.discount, 0), COALESCE(store_selling_price(ifin.mrp_price, istore.discount, istore.price_override), istore.store_price, 0),
+			COALESCE(s.name, ''), COALESCE(istore.stock_quantity, 0), COALESCE(istore.locked_quantity, 0),
+			COALESCE(ca.categories, ARRAY['No Category']::text[]), COALESCE(ia.images, ARRAY[]::text[]),
+			COALESCE(ia.srcsets, ARRAY[]::text[]),
+			i.created_at, COALESCE(i.created_by, 0)`,
+		from: `item i
+			LEFT JOIN brand b ON i.brand_id = b.id
+			LEFT JOIN item_store istore ON i.id = istore.item_id
+			LEFT JOIN item_financial ifin ON i.id = ifin.item_id
+			LEFT JOIN store s ON istore.store_id = s.id
+			LEFT JOIN category_agg ca ON i.id = ca.item_id
+			LEFT JOIN image_agg ia ON i.id = ia.item_id`,
+		sorts: map[string]string{
+			"id":         "i.id",
+			"name":       "LOWER(i.name)",
+			"created_at": "i.created_at",
+			"stock":      "COALESCE(istore.stock_quantity, 0)",
+			"price":      "COALESCE(store_selling_price(ifin.mrp_price, istore.discount, istore.price_override), istore.store_price, 0)",
+		},
+		defaultSort: "id",
+		keys:        []string{"i.id", "COALESCE(istore.id, 0)"},
+	}
+
+	// customers page through this list, so only active items are in it whatever status is asked for
+	q.filter("i.status = ?", types.ItemActive)
+	if params.StoreID != 0 {
+		q.filter("istore.store_id = ?", params.StoreID)
+	}
+	if params.BrandID != 0 {
+		q.filter("i.brand_id = ?", params.BrandID)
+	}
+	if params.CategoryID != 0 {
+		q.filter("EXISTS (SELECT 1 FROM item_category fc WHERE fc.item_id = i.id AND fc.category_id = ?)", params.CategoryID)
+	}
+	if params.From != nil {
+		q.filter("i.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		q.filter("i.created_at < ?", *params.To)
+	}
+
+	return runListQuery(s.reader(readCatalog), q, params, func(rows *sql.Rows, cursorDest []interface{}) (*types.Get_Item, error) {
+		item := &types.Get_Item{}
+		var store string
+		var categories pq.StringArray
+		var images pq.StringArray
+		var srcsets pq.StringArray
+
+		dest := []interface{}{
+			&item.ID, &item.Name, &item.Description, &item.Quantity, &item.Unit_Of_Quantity, &item.Brand,
+			&item.MRP_Price, &item.Discount, &item.Store_Price,
+			&store, &item.Stock_Quantity, &item.Locked_Quantity,
+			&categories, &images, &srcsets,
+			&item.Created_At, &item.Created_By,
+		}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return nil, err
+		}
+
+		item.Categories = categories
+		item.Images = images
+		item.Srcset = srcsets
+		if store != "" {
+			item.Stores = []string{store}
+		}
+		return item, nil
+	})
+}
+
 func (s *PostgresStore) Get_Items_By_CategoryID_And_StoreID(category_id int, store_id int) ([]*types.Get_Items_By_CategoryID_And_StoreID, error) {
 	fmt.Println("Entered Get_Items_By_CategoryID_And_StoreID")
 
-	tx, err := s.db.Begin()
+	tx, err := s.reader(readCatalog).Begin()
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
-	query := `
-    SELECT 
-        i.id, 
-        i.name, 
+	query := storeItemCardsQuery("EXISTS (SELECT 1 FROM item_category ic WHERE ic.item_id = i.id AND ic.category_id = $2)",
+		"istore.stock_quantity DESC")
+
+	cards, err := queryItemCards(tx, query, store_id, category_id)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error querying items by category and store: %w", err)
+	}
+
+	// the cards name the category they are listed under rather than the item's first one
+	var categoryName string
+	err = tx.QueryRow(`SELECT name FROM category WHERE id = $1`, category_id).Scan(&categoryName)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return nil, fmt.Errorf("error querying category %d: %w", category_id, err)
+	}
+	for _, card := range cards {
+		card.Category = categoryName
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return cards, nil
+}
+
+// storeItemCardsQuery lists the active items listed at store $1 that match filter, in order, with the
+// columns queryItemCards scans. filter and order refer to item i, item_store istore and
+// item_financial ifin; filter takes its own parameters from $2 on.
+func storeItemCardsQuery(filter string, order string) string {
+	return `
+    SELECT
+        i.id,
+        i.name,
         i.quantity,
         i.unit_of_quantity,
         b.name AS brand_name,
-        ifin.mrp_price, 
-        istore.discount,  
-        (ifin.mrp_price - istore.discount) AS store_price, 
+        ifin.mrp_price,
+        istore.discount,
+        store_selling_price(ifin.mrp_price, istore.discount, istore.price_override) AS store_price,
         s.name AS store_name,
-        c.name AS category_name,
+        COALESCE((
+            SELECT c.name FROM item_category ic
+            JOIN category c ON c.id = ic.category_id
+            WHERE ic.item_id = i.id
+            ORDER BY c.id
+            LIMIT 1
+        ), '') AS category_name,
         istore.stock_quantity,
         istore.locked_quantity,
-        array_agg(COALESCE(ii.image_url, 'default_image')) FILTER (WHERE ii.image_url IS NOT NULL) AS images, 
+        array_agg(COALESCE(ii.image_url, 'default_image') ORDER BY ii.order_position) FILTER (WHERE ii.image_url IS NOT NULL) AS images,
+        array_agg(ii.srcset ORDER BY ii.order_position) FILTER (WHERE ii.image_url IS NOT NULL) AS srcsets,
         i.created_at,
-        COALESCE(i.created_by, 0) AS created_by
+        COALESCE(i.created_by, 0) AS created_by,
+        COALESCE(i.product_group_id, 0) AS product_group_id,
+        COALESCE(pg.default_item_id, 0) AS default_item_id
     FROM item i
-    JOIN item_category ic ON i.id = ic.item_id
     JOIN item_store istore ON i.id = istore.item_id
-    JOIN item_financial ifin ON i.id = ifin.item_id 
+    JOIN item_financial ifin ON i.id = ifin.item_id
     JOIN brand b ON i.brand_id = b.id
     JOIN store s ON istore.store_id = s.id
-    JOIN category c ON ic.category_id = c.id
     LEFT JOIN item_image ii ON i.id = ii.item_id
-    WHERE ic.category_id = $1 AND istore.store_id = $2
-    GROUP BY i.id, b.name, s.name, c.name, ifin.mrp_price, istore.discount, istore.stock_quantity, istore.locked_quantity
-    ORDER BY istore.stock_quantity DESC
-	`
+    LEFT JOIN product_group pg ON i.product_group_id = pg.id
+    WHERE istore.store_id = $1 AND istore.listed AND i.status = 'active' AND (` + filter + `)
+    GROUP BY i.id, b.name, s.name, ifin.mrp_price, istore.discount, istore.price_override, istore.stock_quantity, istore.locked_quantity, pg.default_item_id
+    ORDER BY ` + order
+}
 
-	rows, err := tx.Query(query, category_id, store_id)
+// queryItemCards runs a store item listing query and groups sizes of one product into cards. The
+// query selects the columns of Get_Items_By_CategoryID_And_StoreID, in its order.
+func queryItemCards(tx *sql.Tx, query string, args ...interface{}) ([]*types.Get_Items_By_CategoryID_And_StoreID, error) {
+	rows, err := tx.Query(query, args...)
 	if err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("error querying items by category and store: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	items := []*types.Get_Items_By_CategoryID_And_StoreID{}
+	var defaultIDs []int
 	for rows.Next() {
 		item := &types.Get_Items_By_CategoryID_And_StoreID{}
+		var defaultID int
 		var images []string
+		var srcsets []string
 		err := rows.Scan(
 			&item.ID,
 			&item.Name,
@@ -428,26 +588,77 @@ func (s *PostgresStore) Get_Items_By_CategoryID_And_StoreID(category_id int, sto
 			&item.Stock_Quantity,
 			&item.Locked_Quantity,
 			pq.Array(&images),
+			pq.Array(&srcsets),
 			&item.Created_At,
 			&item.Created_By,
+			&item.Product_Group_ID,
+			&defaultID,
 		)
 		if err != nil {
-			tx.Rollback()
 			return nil, fmt.Errorf("error scanning row: %w", err)
 		}
 		item.Images = images
+		item.Srcset = srcsets
 		items = append(items, item)
+		defaultIDs = append(defaultIDs, defaultID)
 	}
 
-	err = tx.Commit()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	attributes, err := itemAttributesByID(tx, ids)
 	if err != nil {
-		return nil, fmt.Errorf("error committing transaction: %w", err)
+		return nil, err
+	}
+	groupIDs := make([]int, len(items))
+	for i, item := range items {
+		item.Attributes = attributes[item.ID]
+		groupIDs[i] = item.Product_Group_ID
+	}
+
+	// Sizes of one product share a card showing the default size, with the others in a size selector
+	cards := make([]*types.Get_Items_By_CategoryID_And_StoreID, 0, len(items))
+	for _, card := range variantCards(ids, groupIDs, defaultIDs) {
+		item := items[card.shown]
+		for _, v := range card.variants {
+			variant := items[v]
+			image := ""
+			if len(variant.Images) > 0 {
+				image = variant.Images[0]
+			}
+			item.Variants = append(item.Variants, types.ItemVariant{
+				ItemID:           variant.ID,
+				Name:             variant.Name,
+				Quantity:         variant.Quantity,
+				Unit_Of_Quantity: variant.Unit_Of_Quantity,
+				MRP_Price:        variant.MRP_Price,
+				Discount:         variant.Discount,
+				Store_Price:      variant.Store_Price,
+				Stock_Quantity:   variant.Stock_Quantity,
+				Locked_Quantity:  variant.Locked_Quantity,
+				Image:            image,
+				Default:          variant.ID == defaultIDs[v],
+			})
+		}
+		sortVariants(item.Variants)
+		cards = append(cards, item)
 	}
 
-	return items, nil
+	return cards, nil
 }
 
 func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error) {
+	return s.getItemByID(id, false)
+}
+
+// getItemByID reads an item with its barcodes, categories, images, stores and components. With
+// activeOnly an item that is not active is reported as not found, as customers must not see it.
+func (s *PostgresStore) getItemByID(id int, activeOnly bool) (*types.Get_Item_Barcode, error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
@@ -459,12 +670,14 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 	// Get basic item data, brand name, and description
 	query := `SELECT i.id, i.name, i.quantity, i.unit_of_quantity, b.name, i.description, i.created_at, i.created_by, i.barcode FROM item i
               LEFT JOIN brand b ON i.brand_id = b.id
-              WHERE i.id = $1`
-	row := tx.QueryRow(query, id)
+              WHERE i.id = $1 AND (NOT $2 OR i.status = 'active')`
+	row := tx.QueryRow(query, id, activeOnly)
 	var barcode sql.NullString
 
 	if err := row.Scan(&item.ID, &item.Name, &item.Quantity, &item.Unit_Of_Quantity, &item.Brand, &item.Description, &item.Created_At, &item.Created_By, &barcode); err != nil {
-		// Handle error
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("item with id = [%d] not found", id)
+		}
 		return nil, err
 	}
 
@@ -476,6 +689,23 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 		item.Barcode = ""
 	}
 
+	// Get all barcodes, primary first
+	query = `SELECT barcode FROM item_barcode WHERE item_id = $1 ORDER BY is_primary DESC, created_at, id`
+	barcodeRows, err := tx.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer barcodeRows.Close()
+
+	item.Barcodes = []string{}
+	for barcodeRows.Next() {
+		var code string
+		if err := barcodeRows.Scan(&code); err != nil {
+			return nil, err
+		}
+		item.Barcodes = append(item.Barcodes, code)
+	}
+
 	// Get categories
 	query = `SELECT c.name FROM item_category ic
              JOIN category c ON ic.category_id = c.id
@@ -495,7 +725,7 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 	}
 
 	// Get images
-	query = `SELECT image_url FROM item_image WHERE item_id = $1 ORDER BY order_position`
+	query = `SELECT image_url, srcset FROM item_image WHERE item_id = $1 ORDER BY order_position`
 	rows, err = tx.Query(query, id)
 	if err != nil {
 		return nil, err
@@ -503,11 +733,12 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 	defer rows.Close()
 
 	for rows.Next() {
-		var imageUrl string
-		if err := rows.Scan(&imageUrl); err != nil {
+		var imageUrl, srcset string
+		if err := rows.Scan(&imageUrl, &srcset); err != nil {
 			return nil, err
 		}
 		item.Images = append(item.Images, imageUrl)
+		item.Srcset = append(item.Srcset, srcset)
 	}
 
 	// Get store-related details. Assuming an item can be in multiple stores.
@@ -537,6 +768,21 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 		item.Locked_Quantity = lockedQuantity
 	}
 
+	// Get label attributes
+	attributes, err := itemAttributesByID(tx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	item.Attributes = attributes[id]
+
+	// Get bundle components
+	if item.Components, err = bundleComponents(tx, id); err != nil {
+		return nil, err
+	}
+	if len(item.Components) == 0 {
+		item.Components = nil
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -545,6 +791,7 @@ func (s *PostgresStore) Get_Item_By_ID(id int) (*types.Get_Item_Barcode, error)
 }
 
 func (s *PostgresStore) Update_Item(item *types.Update_Item) (*types.Update_Item, error) {
+	defer s.catalog.invalidateAllItems()
 	tx, err := s.db.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("error starting transaction: %w", err)
@@ -591,6 +838,7 @@ func (s *PostgresStore) Update_Item(item *types.Update_Item) (*types.Update_Item
 }
 
 func (s *PostgresStore) Delete_Item(item_id int) error {
+	defer s.catalog.invalidateAllItems()
 	// Begin a transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -635,6 +883,7 @@ func (s *PostgresStore) Delete_Item(item_id int) error {
 }
 
 func (s *PostgresStore) AddStockToItem() ([]*types.Get_Item, error) {
+	defer s.catalog.invalidateAllItems()
 	// Start a new transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -713,6 +962,7 @@ type StockUpdateInfo struct {
 }
 
 func (s *PostgresStore) AddStockToItemByStore(item_id int, store_id int, stock int) (StockUpdateInfo, error) {
+	defer s.catalog.invalidateItems(store_id, item_id)
 	// Initialize an empty StockUpdateInfo struct
 	print("Enter AddStockToItemByStore")
 	stockInfo := StockUpdateInfo{}
@@ -781,11 +1031,11 @@ func (s *PostgresStore) GetItemAdd(barcode string, storeId int) (*ItemAdd, error
         JOIN item_store istore ON i.id = istore.item_id
         JOIN item_image ii ON i.id = ii.item_id
         JOIN brand b ON i.brand_id = b.id
-        WHERE i.barcode = $1 AND istore.store_id = $2
+        WHERE i.id = (SELECT item_id FROM item_barcode WHERE gtin = $1) AND istore.store_id = $2
         GROUP BY i.id, b.name, istore.store_id, istore.stock_quantity
     `
 
-	row := s.db.QueryRow(query, barcode, storeId)
+	row := s.db.QueryRow(query, gtinKey(barcode), storeId)
 	var imageURLs []string
 	err := row.Scan(&item.ID, &item.Name, &item.Brand, &item.Quantity, &item.Barcode,
 		&item.Unit, &item.StoreID, &item.StockQuantity, pq.Array(&imageURLs))
@@ -803,35 +1053,35 @@ func (s *PostgresStore) GetItemAdd(barcode string, storeId int) (*ItemAdd, error
 	return &item, nil
 }
 
+// AddBarcodeToItem attaches barcode to the item as its primary code. It returns false when the item does not exist.
 func (s *PostgresStore) AddBarcodeToItem(barcode string, item_id int) (bool, error) {
-	// Prepare the SQL query to update the item
-
-	query := `UPDATE item SET barcode = $1 WHERE id = $2`
-
-	// Execute the query with the provided barcode and item_id
-	result, err := s.db.Exec(query, barcode, item_id)
+	tx, err := s.db.Begin()
 	if err != nil {
-		// If there is an error executing the query, return false and the error
-		return false, fmt.Errorf("error updating item barcode: %w", err)
+		return false, err
 	}
+	defer tx.Rollback()
 
-	// Check how many rows were affected
-	rowsAffected, err := result.RowsAffected()
+	var found int
+	err = tx.QueryRow(`SELECT id FROM item WHERE id = $1 FOR UPDATE`, item_id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
-		// If there is an error checking the rows affected, return false and the error
-		return false, fmt.Errorf("error getting rows affected: %w", err)
+		return false, fmt.Errorf("error locking item %d: %w", item_id, err)
 	}
 
-	// If no rows were affected, return false and no error
-	if rowsAffected == 0 {
-		return false, nil
+	if _, err := addItemBarcode(tx, item_id, barcode, true); err != nil {
+		return false, err
 	}
-
-	// If the update was successful, return true and no error
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	s.catalog.invalidateItems(0, item_id)
 	return true, nil
 }
 
 func (s *PostgresStore) AddStockUpdateItem(add_stock int, item_id int) (bool, error) {
+	defer s.catalog.invalidateItems(0, item_id)
 	// Prepare the SQL query to update the item
 	query := `UPDATE item_store SET stock_quantity = stock_quantity + $1 WHERE item_id = $2`
 
@@ -882,12 +1132,12 @@ func (s *PostgresStore) GetItemFromBarcode(barcode string) (*ItemData, error) {
 		SELECT i.id, i.name, istore.mrp_price, i.unit_of_quantity, i.quantity
 		FROM item i
 		INNER JOIN item_store istore ON i.id = istore.item_id
-		WHERE i.barcode = $1
+		WHERE i.id = (SELECT item_id FROM item_barcode WHERE gtin = $1) AND i.status = 'active'
 	`
 
 	// Query the item table
 	var itemData ItemData
-	err := s.db.QueryRow(itemQuery, barcode).Scan(&itemData.ID, &itemData.Name, &itemData.MRPPrice, &itemData.UnitOfQuantity, &itemData.Quantity)
+	err := s.db.QueryRow(itemQuery, gtinKey(barcode)).Scan(&itemData.ID, &itemData.Name, &itemData.MRPPrice, &itemData.UnitOfQuantity, &itemData.Quantity)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no item found with barcode %s", barcode)
@@ -899,12 +1149,12 @@ func (s *PostgresStore) GetItemFromBarcode(barcode string) (*ItemData, error) {
 	imageQuery := `
 		SELECT image_url
 		FROM item_image
-		WHERE item_id = (SELECT id FROM item WHERE barcode = $1)
+		WHERE item_id = $1
 		ORDER BY order_position
 	`
 
 	// Query the item_image table
-	rows, err := s.db.Query(imageQuery, barcode)
+	rows, err := s.db.Query(imageQuery, itemData.ID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying item_image table: %w", err)
 	}
@@ -933,13 +1183,13 @@ func (s *PostgresStore) GetItemFromBarcodeInOrder(barcode string, salesOrderId i
     JOIN item_store istore ON i.id = istore.item_id
     LEFT JOIN item_image ii ON i.id = ii.item_id
     JOIN packer p ON so.packer_id = p.id
-    WHERE so.id = $1 AND p.phone = $2 AND i.barcode = $3
+    WHERE so.id = $1 AND p.phone = $2 AND i.id = (SELECT item_id FROM item_barcode WHERE gtin = $3)
     GROUP BY i.id, i.name, istore.mrp_price, i.unit_of_quantity, i.quantity, ci.quantity	
     `
 
 	var itemData ItemDataQuantity
 	var images pq.StringArray
-	err := s.db.QueryRow(itemQuery, salesOrderId, packerPhone, barcode).Scan(&itemData.ID, &itemData.Name, &itemData.MRPPrice, &itemData.UnitOfQuantity, &itemData.Quantity, &itemData.StockQuantity, &images)
+	err := s.db.QueryRow(itemQuery, salesOrderId, packerPhone, gtinKey(barcode)).Scan(&itemData.ID, &itemData.Name, &itemData.MRPPrice, &itemData.UnitOfQuantity, &itemData.Quantity, &itemData.StockQuantity, &images)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no item found with barcode %s in sales order %d for packer with phone %s", barcode, salesOrderId, packerPhone)
@@ -953,6 +1203,7 @@ func (s *PostgresStore) GetItemFromBarcodeInOrder(barcode string, salesOrderId i
 }
 
 func (s *PostgresStore) CreateItemAddQuick(params types.ItemAddQuick) (ItemAddQuickResponse, error) {
+	defer s.catalog.invalidateAllItems()
 	// Start a transaction
 	response := ItemAddQuickResponse{Success: false} // Initialize with false success
 
@@ -979,14 +1230,21 @@ func (s *PostgresStore) CreateItemAddQuick(params types.ItemAddQuick) (ItemAddQu
 	}
 
 	// Insert into item table
-	itemQuery := `INSERT INTO item (name, brand_id, quantity, barcode, unit_of_quantity, description, created_by) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	itemQuery := `INSERT INTO item (name, brand_id, quantity, unit_of_quantity, description, created_by, status) VALUES ($1, $2, $3, $4, $5, $6, 'draft') RETURNING id`
 	var itemId int
-	err = tx.QueryRow(itemQuery, params.Name, brandId, params.Quantity, params.Barcode, params.Unit, params.Description, params.CreatedBy).Scan(&itemId)
+	err = tx.QueryRow(itemQuery, params.Name, brandId, params.Quantity, params.Unit, params.Description, params.CreatedBy).Scan(&itemId)
 	if err != nil {
 		tx.Rollback()
 		return response, fmt.Errorf("error inserting item: %w", err)
 	}
 
+	if params.Barcode != "" {
+		if _, err := addItemBarcode(tx, itemId, params.Barcode, true); err != nil {
+			tx.Rollback()
+			return response, err
+		}
+	}
+
 	// Insert into item_category table
 	categoryQuery := `INSERT INTO item_category (item_id, category_id, created_by) VALUES ($1, $2, $3)`
 	_, err = tx.Exec(categoryQuery, itemId, params.CategoryId, params.CreatedBy)
@@ -1038,6 +1296,7 @@ type ManagerItem struct {
 	CategoryIDs    []int    `json:"category_ids"`
 	CategoryNames  []string `json:"category_names"`
 	ImageURLs      []string `json:"image_urls"`
+	Status         string   `json:"status"`
 }
 
 func (s *PostgresStore) GetManagerItems() ([]ManagerItem, error) {
@@ -1057,7 +1316,8 @@ SELECT
     COALESCE(i.created_by, 0) as created_by,
     COALESCE(ARRAY_AGG(DISTINCT ic.category_id) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::INT[]) AS category_ids,
     COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::TEXT[]) AS category_names,
-    COALESCE(ARRAY_AGG(DISTINCT ii.image_url) FILTER (WHERE ii.image_url IS NOT NULL), ARRAY[]::TEXT[]) AS image_urls
+    COALESCE(ARRAY_AGG(DISTINCT ii.image_url) FILTER (WHERE ii.image_url IS NOT NULL), ARRAY[]::TEXT[]) AS image_urls,
+    i.status
 FROM
     item i
 LEFT JOIN brand b ON i.brand_id = b.id  -- Join with the brand table
@@ -1068,7 +1328,7 @@ GROUP BY
     i.id, b.name  -- Group by brand name as well
 `
 
-	rows, err := s.db.Query(query)
+	rows, err := s.reader(readCatalog).Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("error executing query for manager items: %w", err)
 	}
@@ -1082,7 +1342,7 @@ GROUP BY
 		var categoryNames []sql.NullString
 		var imageUrls []sql.NullString
 
-		err = rows.Scan(&item.ID, &item.Name, &item.BrandID, &item.BrandName, &item.Quantity, &barcode, &item.UnitOfQuantity, &item.Description, &item.CreatedAt, &createdBy, pq.Array(&categoryIDs), pq.Array(&categoryNames), pq.Array(&imageUrls))
+		err = rows.Scan(&item.ID, &item.Name, &item.BrandID, &item.BrandName, &item.Quantity, &barcode, &item.UnitOfQuantity, &item.Description, &item.CreatedAt, &createdBy, pq.Array(&categoryIDs), pq.Array(&categoryNames), pq.Array(&imageUrls), &item.Status)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning row into ManagerItem: %w", err)
 		}
@@ -1120,6 +1380,77 @@ GROUP BY
 	return items, nil
 }
 
+// ListManagerItems is the paginated form of GetManagerItems.
+func (s *PostgresStore) ListManagerItems(params types.ListParams) (*types.Page[ManagerItem], error) {
+	q := &listQuery{
+		columns: `i.id, i.name, COALESCE(i.brand_id, 0), COALESCE(b.name, ''), i.quantity, i.barcode, i.unit_of_quantity,
+			COALESCE(i.description, ''), i.created_at, COALESCE(i.created_by, 0),
+			COALESCE(ARRAY_AGG(DISTINCT ic.category_id) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::INT[]),
+			COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::TEXT[]),
+			COALESCE(ARRAY_AGG(DISTINCT ii.image_url) FILTER (WHERE ii.image_url IS NOT NULL), ARRAY[]::TEXT[]),
+			i.status`,
+		from: `item i
+			LEFT JOIN brand b ON i.brand_id = b.id
+			LEFT JOIN item_category ic ON i.id = ic.item_id
+			LEFT JOIN category c ON ic.category_id = c.id
+			LEFT JOIN item_image ii ON i.id = ii.item_id`,
+		groupBy: "i.id, b.name",
+		sorts: map[string]string{
+			"id":         "i.id",
+			"name":       "LOWER(i.name)",
+			"created_at": "i.created_at",
+		},
+		defaultSort: "id",
+		keys:        []string{"i.id"},
+	}
+
+	if params.BrandID != 0 {
+		q.filter("i.brand_id = ?", params.BrandID)
+	}
+	if params.CategoryID != 0 {
+		q.filter("EXISTS (SELECT 1 FROM item_category fc WHERE fc.item_id = i.id AND fc.category_id = ?)", params.CategoryID)
+	}
+	if params.StoreID != 0 {
+		q.filter("EXISTS (SELECT 1 FROM item_store fs WHERE fs.item_id = i.id AND fs.store_id = ?)", params.StoreID)
+	}
+	if params.Status != "" {
+		q.filter("i.status = ?", params.Status)
+	}
+	if params.From != nil {
+		q.filter("i.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		q.filter("i.created_at < ?", *params.To)
+	}
+
+	return runListQuery(s.reader(readCatalog), q, params, func(rows *sql.Rows, cursorDest []interface{}) (ManagerItem, error) {
+		var item ManagerItem
+		var barcode sql.NullString
+		var categoryIDs pq.Int64Array
+		var categoryNames pq.StringArray
+		var imageUrls pq.StringArray
+
+		dest := []interface{}{
+			&item.ID, &item.Name, &item.BrandID, &item.BrandName, &item.Quantity, &barcode, &item.UnitOfQuantity,
+			&item.Description, &item.CreatedAt, &item.CreatedBy,
+			&categoryIDs, &categoryNames, &imageUrls, &item.Status,
+		}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return item, err
+		}
+
+		if barcode.Valid {
+			item.Barcode = &barcode.String
+		}
+		for _, cid := range categoryIDs {
+			item.CategoryIDs = append(item.CategoryIDs, int(cid))
+		}
+		item.CategoryNames = categoryNames
+		item.ImageURLs = imageUrls
+		return item, nil
+	})
+}
+
 func (s *PostgresStore) GetManagerItem(id int) (*ManagerItem, error) {
 	var item ManagerItem
 	var barcode sql.NullString
@@ -1142,7 +1473,8 @@ SELECT
     COALESCE(i.created_by, 0) as created_by,
     COALESCE(ARRAY_AGG(DISTINCT ic.category_id) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::INT[]) AS category_ids,
     COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE ic.category_id IS NOT NULL), ARRAY[]::TEXT[]) AS category_names,
-    COALESCE(ARRAY_AGG(DISTINCT ii.image_url) FILTER (WHERE ii.image_url IS NOT NULL), ARRAY[]::TEXT[]) AS image_urls
+    COALESCE(ARRAY_AGG(DISTINCT ii.image_url) FILTER (WHERE ii.image_url IS NOT NULL), ARRAY[]::TEXT[]) AS image_urls,
+    i.status
 FROM
     item i
 LEFT JOIN brand b ON i.brand_id = b.id
@@ -1156,7 +1488,7 @@ GROUP BY
 `
 
 	row := s.db.QueryRow(query, id)
-	err := row.Scan(&item.ID, &item.Name, &item.BrandID, &item.BrandName, &item.Quantity, &barcode, &item.UnitOfQuantity, &item.Description, &item.CreatedAt, &createdBy, pq.Array(&categoryIDs), pq.Array(&categoryNames), pq.Array(&imageUrls))
+	err := row.Scan(&item.ID, &item.Name, &item.BrandID, &item.BrandName, &item.Quantity, &barcode, &item.UnitOfQuantity, &item.Description, &item.CreatedAt, &createdBy, pq.Array(&categoryIDs), pq.Array(&categoryNames), pq.Array(&imageUrls), &item.Status)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no ManagerItem found with ID %d", id)
@@ -1195,6 +1527,7 @@ GROUP BY
 }
 
 func (s *PostgresStore) EditItem(item *types.ItemEdit) (*types.ItemEdit, error) {
+	defer s.catalog.invalidateAllItems()
 	// Begin a transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -1272,6 +1605,7 @@ func (s *PostgresStore) EditItem(item *types.ItemEdit) (*types.ItemEdit, error)
 }
 
 func (s *PostgresStore) AddItemFinancials(item *types.ItemFinancials) (*types.ItemFinancials, error) {
+	defer s.catalog.invalidateAllItems()
 	// Begin a transaction
 	tx, err := s.db.Begin()
 	if err != nil {
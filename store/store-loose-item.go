@@ -0,0 +1,263 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// CreateLooseItemTables lets items be sold by weight. A loose item is ordered in units of its own
+// quantity (in g or kg), packed by actual weight, and charged for that weight: order_item_weight
+// keeps the estimated and actual amount of each loose line of an order, and order_weight_settlement
+// the difference a prepaid order is owed or owes.
+func (s *PostgresStore) CreateLooseItemTables(tx *sql.Tx) error {
+	query := `
+    ALTER TABLE item
+        ADD COLUMN IF NOT EXISTS sold_by_weight BOOLEAN NOT NULL DEFAULT FALSE,
+        ADD COLUMN IF NOT EXISTS weight_tolerance_pct DECIMAL(5, 2) NOT NULL DEFAULT 10
+            CHECK (weight_tolerance_pct >= 0 AND weight_tolerance_pct <= 100);
+
+    CREATE OR REPLACE FUNCTION item_unit_grams(p_quantity INT, p_unit unit_enum) RETURNS INT AS $$
+        SELECT CASE p_unit WHEN 'g' THEN p_quantity WHEN 'kg' THEN p_quantity * 1000 END
+    $$ LANGUAGE SQL IMMUTABLE;
+
+    ALTER TABLE packer_item ADD COLUMN IF NOT EXISTS weight_grams INT CHECK (weight_grams > 0);
+
+    CREATE TABLE IF NOT EXISTS order_item_weight (
+        sales_order_id INT NOT NULL REFERENCES sales_order(id) ON DELETE CASCADE,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        quantity INT NOT NULL,
+        estimated_grams INT NOT NULL,
+        actual_grams INT NOT NULL,
+        unit_price DECIMAL(10, 2) NOT NULL,
+        estimated_amount DECIMAL(10, 2) NOT NULL,
+        actual_amount DECIMAL(10, 2) NOT NULL,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (sales_order_id, item_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS order_weight_settlement (
+        sales_order_id INT PRIMARY KEY REFERENCES sales_order(id) ON DELETE CASCADE,
+        delta DECIMAL(10, 2) NOT NULL,
+        settlement TEXT NOT NULL CHECK (settlement IN ('none', 'refund', 'collect')),
+        status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'settled')),
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating loose item tables: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetLooseItem(itemID int) (*types.LooseItem, error) {
+	item := &types.LooseItem{ItemID: itemID}
+	err := s.db.QueryRow(`
+        SELECT i.sold_by_weight, COALESCE(item_unit_grams(i.quantity, i.unit_of_quantity), 0),
+               COALESCE(ifin.mrp_price, 0), i.weight_tolerance_pct
+        FROM item i
+        LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+        WHERE i.id = $1`, itemID).Scan(&item.SoldByWeight, &item.EstimatedGrams, &item.MRPPrice, &item.TolerancePct)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item %d does not exist", itemID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying item %d: %w", itemID, err)
+	}
+	if item.EstimatedGrams > 0 {
+		item.PricePerKg = math.Round(item.MRPPrice*1000/float64(item.EstimatedGrams)*100) / 100
+	}
+	return item, nil
+}
+
+// SetLooseItem marks an item as sold by weight, or back to per unit. A per-kg price is applied as
+// an immediate price change in the same transaction, so carts are re-priced and the price history
+// records it.
+func (s *PostgresStore) SetLooseItem(req types.SetLooseItem) (*types.LooseItem, error) {
+	item, err := s.GetLooseItem(req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if req.SoldByWeight && item.EstimatedGrams <= 0 {
+		return nil, fmt.Errorf("item %d must have a quantity in g or kg to be sold by weight", req.ItemID)
+	}
+	if req.TolerancePct != nil && (*req.TolerancePct < 0 || *req.TolerancePct > 100) {
+		return nil, fmt.Errorf("tolerance_pct must be between 0 and 100")
+	}
+
+	if req.PricePerKg != nil {
+		if !req.SoldByWeight {
+			return nil, fmt.Errorf("price_per_kg only applies to items sold by weight")
+		}
+		if *req.PricePerKg <= 0 {
+			return nil, fmt.Errorf("price_per_kg must be positive")
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	carts := &repricedCarts{}
+	if req.PricePerKg != nil {
+		mrp := math.Round(*req.PricePerKg*float64(item.EstimatedGrams)/1000*100) / 100
+		_, carts, err = applyPriceChangeNow(tx, types.SchedulePriceChange{
+			ItemID:    req.ItemID,
+			MRPPrice:  &mrp,
+			Reason:    fmt.Sprintf("%.2f per kg", *req.PricePerKg),
+			CreatedBy: req.CreatedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tolerance := item.TolerancePct
+	if req.TolerancePct != nil {
+		tolerance = *req.TolerancePct
+	}
+	if _, err := tx.Exec(`UPDATE item SET sold_by_weight = $2, weight_tolerance_pct = $3 WHERE id = $1`,
+		req.ItemID, req.SoldByWeight, tolerance); err != nil {
+		return nil, fmt.Errorf("error updating item %d: %w", req.ItemID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidateItems(0, req.ItemID)
+	s.refreshRepricedCarts(carts)
+
+	return s.GetLooseItem(req.ItemID)
+}
+
+// packedWeight validates the weight a packer records for units of an item. Items sold per unit
+// need no weight and get a NULL one; a loose item needs a weight within its tolerance of the
+// estimate for those units.
+func (s *PostgresStore) packedWeight(itemID, units, weightGrams int) (sql.NullInt64, error) {
+	var byWeight bool
+	var grams sql.NullInt64
+	var tolerance float64
+	err := s.db.QueryRow(`
+        SELECT sold_by_weight, item_unit_grams(quantity, unit_of_quantity), weight_tolerance_pct
+        FROM item WHERE id = $1`, itemID).Scan(&byWeight, &grams, &tolerance)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("error querying item %d: %w", itemID, err)
+	}
+	if !byWeight {
+		return sql.NullInt64{}, nil
+	}
+	if weightGrams <= 0 {
+		return sql.NullInt64{}, fmt.Errorf("item %d is sold by weight, weigh it and send weight_grams", itemID)
+	}
+
+	expected := float64(grams.Int64 * int64(units))
+	if math.Abs(float64(weightGrams)-expected) > expected*tolerance/100 {
+		return sql.NullInt64{}, fmt.Errorf("packed weight %dg of item %d is not within %.0f%% of the expected %.0fg, re-weigh it",
+			weightGrams, itemID, tolerance, expected)
+	}
+	return sql.NullInt64{Int64: int64(weightGrams), Valid: true}, nil
+}
+
+// recordOrderItemWeight recomputes the loose line of itemID in the order from the weights packed so
+// far, and the order's settlement with it, inside the transaction that packs them. The line only
+// covers the units packed so far, so a part-packed line is weighed against their estimate alone and
+// the units not packed yet are neither charged nor refunded.
+func recordOrderItemWeight(tx *sql.Tx, orderID, itemID int) error {
+	_, err := tx.Exec(`
+        INSERT INTO order_item_weight (sales_order_id, item_id, quantity, estimated_grams, actual_grams,
+                                       unit_price, estimated_amount, actual_amount)
+        SELECT so.id, ci.item_id, SUM(pi.quantity), item_unit_grams(i.quantity, i.unit_of_quantity) * SUM(pi.quantity),
+               SUM(pi.weight_grams), ci.sold_price, ci.sold_price * SUM(pi.quantity),
+               ROUND(ci.sold_price::numeric * SUM(pi.weight_grams) / item_unit_grams(i.quantity, i.unit_of_quantity), 2)
+        FROM sales_order so
+        JOIN cart_item ci ON ci.cart_id = so.cart_id AND ci.item_id = $2
+        JOIN item i ON i.id = ci.item_id
+        JOIN packer_item pi ON pi.sales_order_id = so.id AND pi.item_id = ci.item_id AND pi.weight_grams IS NOT NULL
+        WHERE so.id = $1
+        GROUP BY so.id, ci.item_id, ci.quantity, ci.sold_price, i.quantity, i.unit_of_quantity
+        ON CONFLICT (sales_order_id, item_id) DO UPDATE
+        SET quantity = EXCLUDED.quantity, estimated_grams = EXCLUDED.estimated_grams,
+            actual_grams = EXCLUDED.actual_grams, unit_price = EXCLUDED.unit_price,
+            estimated_amount = EXCLUDED.estimated_amount, actual_amount = EXCLUDED.actual_amount,
+            updated_at = CURRENT_TIMESTAMP`, orderID, itemID)
+	if err != nil {
+		return fmt.Errorf("error recording packed weight of item %d: %w", itemID, err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO order_weight_settlement (sales_order_id, delta, settlement)
+        SELECT so.id, d.delta,
+               CASE WHEN NOT so.paid OR so.payment_type = 'cash' OR d.delta = 0 THEN 'none'
+                    WHEN d.delta < 0 THEN 'refund'
+                    ELSE 'collect' END
+        FROM sales_order so
+        CROSS JOIN LATERAL (
+            SELECT COALESCE(SUM(w.actual_amount - w.estimated_amount), 0) AS delta
+            FROM order_item_weight w WHERE w.sales_order_id = so.id
+        ) d
+        WHERE so.id = $1
+        ON CONFLICT (sales_order_id) DO UPDATE
+        SET delta = EXCLUDED.delta, settlement = EXCLUDED.settlement, updated_at = CURRENT_TIMESTAMP
+        WHERE order_weight_settlement.status = 'pending'`, orderID)
+	if err != nil {
+		return fmt.Errorf("error recording weight settlement of order %d: %w", orderID, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetOrderWeightSettlement(orderID int) (*types.OrderWeightSettlement, error) {
+	settlement := &types.OrderWeightSettlement{
+		SalesOrderID: orderID,
+		Lines:        []types.OrderWeightLine{},
+		Settlement:   types.WeightSettlementNone,
+		Status:       "pending",
+	}
+	err := s.db.QueryRow(`SELECT delta, settlement, status FROM order_weight_settlement WHERE sales_order_id = $1`,
+		orderID).Scan(&settlement.Delta, &settlement.Settlement, &settlement.Status)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error querying weight settlement of order %d: %w", orderID, err)
+	}
+
+	rows, err := s.db.Query(`
+        SELECT w.item_id, i.name, w.quantity, w.estimated_grams, w.actual_grams, w.unit_price,
+               w.estimated_amount, w.actual_amount
+        FROM order_item_weight w
+        JOIN item i ON i.id = w.item_id
+        WHERE w.sales_order_id = $1
+        ORDER BY i.name`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying weighed lines of order %d: %w", orderID, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line types.OrderWeightLine
+		if err := rows.Scan(&line.ItemID, &line.Name, &line.Quantity, &line.EstimatedGrams, &line.ActualGrams,
+			&line.UnitPrice, &line.EstimatedAmount, &line.ActualAmount); err != nil {
+			return nil, fmt.Errorf("error scanning weighed line: %w", err)
+		}
+		settlement.Lines = append(settlement.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return settlement, nil
+}
+
+// SettleOrderWeight records that the refund or collection of an order's weight delta was made,
+// which freezes the delta.
+func (s *PostgresStore) SettleOrderWeight(orderID int) (*types.OrderWeightSettlement, error) {
+	res, err := s.db.Exec(`
+        UPDATE order_weight_settlement SET status = 'settled', updated_at = CURRENT_TIMESTAMP
+        WHERE sales_order_id = $1 AND status = 'pending'`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error settling order %d: %w", orderID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("order %d has no pending weight settlement", orderID)
+	}
+	return s.GetOrderWeightSettlement(orderID)
+}
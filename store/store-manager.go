@@ -248,6 +248,7 @@ func (s *PostgresStore) AuthenticateRequestManager(phone, token string) (bool, e
 }
 
 func (s *PostgresStore) ManagerItemStoreCombo() (bool, error) {
+	defer s.catalog.invalidateAllItems()
 	// Retrieve all items
 	itemsQuery := `SELECT id FROM item`
 	rows, err := s.db.Query(itemsQuery)
@@ -291,6 +292,7 @@ func (s *PostgresStore) ManagerItemStoreCombo() (bool, error) {
 }
 
 func (s *PostgresStore) ManagerAddNewItem(item types.ItemBasic) (types.ItemBasicReturn, error) {
+	defer s.catalog.invalidateAllItems()
 	// Start a transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -310,8 +312,8 @@ func (s *PostgresStore) ManagerAddNewItem(item types.ItemBasic) (types.ItemBasic
 
 	// Insert the item
 	insertQuery := `
-        INSERT INTO item (name, brand_id, quantity, unit_of_quantity, description)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO item (name, brand_id, quantity, unit_of_quantity, description, status)
+        VALUES ($1, $2, $3, $4, $5, 'draft')
         RETURNING id;`
 	var itemId int
 	err = tx.QueryRow(insertQuery, item.Name, item.BrandId, item.Quantity, item.UnitOfQuantity, item.Description).Scan(&itemId)
@@ -355,20 +357,30 @@ func (s *PostgresStore) ManagerAddNewItem(item types.ItemBasic) (types.ItemBasic
 	}, nil
 }
 
+// ManagerUpdateItemBarcode makes item.Barcode the item's primary code. Its other codes stay attached.
 func (s *PostgresStore) ManagerUpdateItemBarcode(item types.ItemBarcodeBasic) (types.ItemBarcodeBasicReturn, error) {
-	// Prepare the update query to change the barcode for the given item ID
-	updateQuery := `UPDATE item SET barcode = $1 WHERE id = $2 RETURNING name;`
+	tx, err := s.db.Begin()
+	if err != nil {
+		return types.ItemBarcodeBasicReturn{}, err
+	}
+	defer tx.Rollback()
 
 	// Variable to store the item's name after the update
 	var itemName string
 
-	// Execute the update query and scan the returned item name
-	err := s.db.QueryRow(updateQuery, item.Barcode, item.ItemID).Scan(&itemName)
+	err = tx.QueryRow(`SELECT name FROM item WHERE id = $1 FOR UPDATE`, item.ItemID).Scan(&itemName)
 	if err != nil {
-		// Handle the error, possibly no rows were affected or a database error occurred
 		return types.ItemBarcodeBasicReturn{}, fmt.Errorf("error updating item barcode: %w", err)
 	}
 
+	if _, err := addItemBarcode(tx, item.ItemID, item.Barcode, true); err != nil {
+		return types.ItemBarcodeBasicReturn{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return types.ItemBarcodeBasicReturn{}, err
+	}
+	s.catalog.invalidateItems(0, item.ItemID)
+
 	// Return the updated item details
 	return types.ItemBarcodeBasicReturn{
 		ItemID:   item.ItemID,
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// orderEventsChannel is the Postgres NOTIFY channel written by the sales_order trigger.
+const orderEventsChannel = "order_events"
+
+func (s *PostgresStore) CreateOrderEventTrigger(tx *sql.Tx) error {
+	functionQuery := `
+    CREATE OR REPLACE FUNCTION notify_order_event() RETURNS trigger AS $$
+    BEGIN
+        IF TG_OP = 'UPDATE'
+            AND NEW.order_status IS NOT DISTINCT FROM OLD.order_status
+            AND NEW.order_dp_status IS NOT DISTINCT FROM OLD.order_dp_status
+            AND NEW.delivery_partner_id IS NOT DISTINCT FROM OLD.delivery_partner_id THEN
+            RETURN NEW;
+        END IF;
+
+        PERFORM pg_notify('order_events', json_build_object(
+            'event', CASE WHEN TG_OP = 'INSERT' THEN 'created' ELSE 'updated' END,
+            'order_id', NEW.id,
+            'store_id', NEW.store_id,
+            'customer_id', NEW.customer_id,
+            'cart_id', NEW.cart_id,
+            'delivery_partner_id', NEW.delivery_partner_id,
+            'packer_id', NEW.packer_id,
+            'order_status', NEW.order_status,
+            'order_dp_status', NEW.order_dp_status
+        )::text);
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;`
+
+	if _, err := tx.Exec(functionQuery); err != nil {
+		return fmt.Errorf("error creating notify_order_event function: %w", err)
+	}
+
+	triggerQuery := `
+    DROP TRIGGER IF EXISTS sales_order_notify_event ON sales_order;
+    CREATE TRIGGER sales_order_notify_event
+        AFTER INSERT OR UPDATE ON sales_order
+        FOR EACH ROW EXECUTE FUNCTION notify_order_event();`
+
+	if _, err := tx.Exec(triggerQuery); err != nil {
+		return fmt.Errorf("error creating sales_order_notify_event trigger: %w", err)
+	}
+
+	return nil
+}
+
+type orderEventSubscriber struct {
+	filter func(types.OrderEvent) bool
+	events chan types.OrderEvent
+}
+
+// orderEventHub fans out order events received from Postgres to the open SSE streams of this instance.
+type orderEventHub struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]*orderEventSubscriber
+}
+
+func newOrderEventHub() *orderEventHub {
+	return &orderEventHub{subscribers: make(map[int]*orderEventSubscriber)}
+}
+
+func (h *orderEventHub) publish(event types.OrderEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for id, sub := range h.subscribers {
+		if !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			log.Printf("order events: subscriber %d is not keeping up, dropped event for order %d", id, event.OrderID)
+		}
+	}
+}
+
+// SubscribeOrderEvents registers a stream for the events accepted by filter.
+// The returned function must be called once the stream is closed.
+func (s *PostgresStore) SubscribeOrderEvents(filter func(types.OrderEvent) bool) (<-chan types.OrderEvent, func()) {
+	h := s.orderEvents
+	sub := &orderEventSubscriber{filter: filter, events: make(chan types.OrderEvent, 32)}
+
+	h.mu.Lock()
+	h.nextID++
+	id := h.nextID
+	h.subscribers[id] = sub
+	h.mu.Unlock()
+
+	return sub.events, func() {
+		h.mu.Lock()
+		delete(h.subscribers, id)
+		h.mu.Unlock()
+	}
+}
+
+// ListenOrderEvents relays NOTIFY payloads from every instance to the local subscribers until ctx is done.
+func (s *PostgresStore) ListenOrderEvents(ctx context.Context) error {
+	listener := pq.NewListener(s.connStr, 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("order events listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(orderEventsChannel); err != nil {
+		return fmt.Errorf("error listening on %s: %w", orderEventsChannel, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established; streams catch up on their own.
+			if n == nil {
+				continue
+			}
+			var event types.OrderEvent
+			if err := json.Unmarshal([]byte(n.Extra), &event); err != nil {
+				log.Printf("order events: invalid payload %q: %v", n.Extra, err)
+				continue
+			}
+			s.router.markWrite(readOrders)
+			s.orderEvents.publish(event)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+// CreatePackerStoreColumn assigns each packer to a store, whose order feed is the only one they can
+// stream. Packers without a store are assigned the store they last packed an item in.
+func (s *PostgresStore) CreatePackerStoreColumn(tx *sql.Tx) error {
+	query := `
+    ALTER TABLE packer ADD COLUMN IF NOT EXISTS store_id INT REFERENCES store(id) ON DELETE SET NULL;
+
+    UPDATE packer p SET store_id = (
+        SELECT pi.store_id FROM packer_item pi
+        WHERE pi.packer_id = p.id
+        ORDER BY pi.packing_time DESC NULLS LAST, pi.id DESC
+        LIMIT 1
+    )
+    WHERE p.store_id IS NULL;`
+
+	_, err := tx.Exec(query)
+	if err != nil {
+		return fmt.Errorf("error adding store to packer: %w", err)
+	}
+	return nil
+}
+
+// GetPackerStoreByPhone returns the store of a packer, 0 when they have none.
+func (s *PostgresStore) GetPackerStoreByPhone(phone string) (int, error) {
+	var storeID sql.NullInt64
+	err := s.db.QueryRow(`SELECT store_id FROM packer WHERE phone = $1`, phone).Scan(&storeID)
+	if err != nil {
+		return 0, fmt.Errorf("error finding packer for phone %s: %w", phone, err)
+	}
+	return int(storeID.Int64), nil
+}
+
+func (s *PostgresStore) SetPackerStore(req types.SetPackerStore) error {
+	res, err := s.db.Exec(`UPDATE packer SET store_id = $2 WHERE id = $1`, req.PackerID, req.StoreID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return fmt.Errorf("store %d does not exist", req.StoreID)
+		}
+		return fmt.Errorf("error assigning packer %d to store %d: %w", req.PackerID, req.StoreID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("packer with id = [%d] not found", req.PackerID)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCustomerIDByPhone(phone string) (int, error) {
+	var customerID int
+	err := s.db.QueryRow(`SELECT id FROM customer WHERE phone = $1`, phone).Scan(&customerID)
+	if err != nil {
+		return 0, fmt.Errorf("error finding customer for phone %s: %w", phone, err)
+	}
+	return customerID, nil
+}
+
+func (s *PostgresStore) GetDeliveryPartnerIDByPhone(phone string) (int, error) {
+	var deliveryPartnerID int
+	err := s.db.QueryRow(`SELECT id FROM delivery_partner WHERE phone = $1`, phone).Scan(&deliveryPartnerID)
+	if err != nil {
+		return 0, fmt.Errorf("error finding delivery partner for phone %s: %w", phone, err)
+	}
+	return deliveryPartnerID, nil
+}
@@ -25,7 +25,8 @@ func (s *PostgresStore) CreatePackerItemTable(tx *sql.Tx) error {
 	return nil
 }
 
-func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, orderId int, storeId int) (PackerItemResponse, error) {
+// PackerPackItem packs one unit of the item with barcode. Loose items need its weightGrams.
+func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, orderId int, storeId int, weightGrams int) (PackerItemResponse, error) {
 	var response PackerItemResponse
 	var allItemsPacked bool
 
@@ -42,8 +43,8 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 
 	// Retrieve item_id from item table using barcode
 	var itemId int
-	itemIdQuery := `SELECT id FROM item WHERE barcode = $1`
-	err = s.db.QueryRow(itemIdQuery, barcode).Scan(&itemId)
+	itemIdQuery := `SELECT item_id FROM item_barcode WHERE gtin = $1`
+	err = s.db.QueryRow(itemIdQuery, gtinKey(barcode)).Scan(&itemId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response, fmt.Errorf("no item found with barcode %s: %w", barcode, err)
@@ -53,9 +54,10 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 
 	// Retrieve required quantity from cart_item table
 	var requiredQuantity int
-	cartItemQuery := `SELECT ci.quantity FROM cart_item ci
+	cartItemQuery := `SELECT SUM(ci.quantity) FROM cart_pick_item ci
                       JOIN sales_order so ON ci.cart_id = so.cart_id
-                      WHERE so.id = $1 AND ci.item_id = $2`
+                      WHERE so.id = $1 AND ci.item_id = $2
+                      GROUP BY ci.item_id`
 	err = s.db.QueryRow(cartItemQuery, orderId, itemId).Scan(&requiredQuantity)
 	if err != nil {
 		return response, fmt.Errorf("error querying required item quantity for orderId %d and itemId %d: %w", orderId, itemId, err)
@@ -73,9 +75,22 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 
 	// Compare and insert if necessary
 	if requiredQuantity > packedQuantity {
+		// Loose items are packed with their weight
+		weight, err := s.packedWeight(itemId, 1, weightGrams)
+		if err != nil {
+			return response, err
+		}
+
+		// The packed unit and the weighed line it settles are written together
+		tx, err := s.db.Begin()
+		if err != nil {
+			return response, fmt.Errorf("error starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+
 		// Step 1: Query the maximum packer_item_id
 		var maxPackerItemId int
-		err := s.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM packer_item").Scan(&maxPackerItemId)
+		err = tx.QueryRow("SELECT COALESCE(MAX(id), 0) FROM packer_item").Scan(&maxPackerItemId)
 		if err != nil {
 			return response, fmt.Errorf("error querying max packer_item_id: %w", err)
 		}
@@ -84,12 +99,20 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 		newPackerItemId := maxPackerItemId + 1
 	
 		// Step 3: Insert a new record into the packer_item table
-		insertQuery := `INSERT INTO packer_item (id, item_id, packer_id, sales_order_id, quantity, store_id) VALUES ($1, $2, $3, $4, $5, $6)`
+		insertQuery := `INSERT INTO packer_item (id, item_id, packer_id, sales_order_id, quantity, store_id, weight_grams) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 	
-		_, err = s.db.Exec(insertQuery, newPackerItemId, itemId, packerId, orderId, 1, storeId)
+		_, err = tx.Exec(insertQuery, newPackerItemId, itemId, packerId, orderId, 1, storeId, weight)
 		if err != nil {
 			return response, fmt.Errorf("error inserting into packer_item table with itemId %d, packerId %d, orderId %d, storeId %d: %w", itemId, packerId, orderId, storeId, err)
 		}
+		if weight.Valid {
+			if err := recordOrderItemWeight(tx, orderId, itemId); err != nil {
+				return response, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return response, fmt.Errorf("error committing transaction: %w", err)
+		}
 	
 		packedQuantity++
 	}
@@ -110,7 +133,7 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 
 	// Retrieve total required quantity from the cart_item table using cart_id
 	var totalRequiredQuantity int
-	totalCartItemQuery := `SELECT SUM(quantity) FROM cart_item WHERE cart_id = $1`
+	totalCartItemQuery := `SELECT SUM(quantity) FROM cart_pick_item WHERE cart_id = $1`
 	err = s.db.QueryRow(totalCartItemQuery, cartId).Scan(&totalRequiredQuantity)
 	if err != nil {
 		return response, fmt.Errorf("error querying total required quantity from cart_item with cartId %d: %w", cartId, err)
@@ -165,7 +188,8 @@ func (s *PostgresStore) PackerPackItem(barcode string, packerPhone string, order
 	return response, nil
 }
 
-func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packerPhone string, orderId int, storeId int) (PackerItemResponse, error) {
+// PackerPackItemQuick packs itemQuantity units of an item. Loose items need their total weightGrams.
+func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packerPhone string, orderId int, storeId int, weightGrams int) (PackerItemResponse, error) {
 	var response PackerItemResponse
 	var allItemsPacked bool
 
@@ -184,9 +208,10 @@ func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packer
 
 	// Retrieve required quantity from cart_item table
 	var requiredQuantity int
-	cartItemQuery := `SELECT ci.quantity FROM cart_item ci
+	cartItemQuery := `SELECT SUM(ci.quantity) FROM cart_pick_item ci
                       JOIN sales_order so ON ci.cart_id = so.cart_id
-                      WHERE so.id = $1 AND ci.item_id = $2`
+                      WHERE so.id = $1 AND ci.item_id = $2
+                      GROUP BY ci.item_id`
 	err = s.db.QueryRow(cartItemQuery, orderId, itemId).Scan(&requiredQuantity)
 	if err != nil {
 		return response, fmt.Errorf("error querying required item quantity for orderId %d and itemId %d: %w", orderId, itemId, err)
@@ -204,9 +229,22 @@ func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packer
 
 	// Compare and insert if necessary
 	if requiredQuantity > packedQuantity {
+		// Loose items are packed with their weight
+		weight, err := s.packedWeight(itemId, itemQuantity, weightGrams)
+		if err != nil {
+			return response, err
+		}
+
+		// The packed unit and the weighed line it settles are written together
+		tx, err := s.db.Begin()
+		if err != nil {
+			return response, fmt.Errorf("error starting transaction: %w", err)
+		}
+		defer tx.Rollback()
+
 		// Step 1: Query the maximum packer_item_id
 		var maxPackerItemId int
-		err := s.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM packer_item").Scan(&maxPackerItemId)
+		err = tx.QueryRow("SELECT COALESCE(MAX(id), 0) FROM packer_item").Scan(&maxPackerItemId)
 		if err != nil {
 			return response, fmt.Errorf("error querying max packer_item_id: %w", err)
 		}
@@ -215,12 +253,20 @@ func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packer
 		newPackerItemId := maxPackerItemId + 1
 	
 		// Step 3: Insert a new record into the packer_item table
-		insertQuery := `INSERT INTO packer_item (id, item_id, packer_id, sales_order_id, quantity, store_id) VALUES ($1, $2, $3, $4, $5, $6)`
+		insertQuery := `INSERT INTO packer_item (id, item_id, packer_id, sales_order_id, quantity, store_id, weight_grams) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 	
-		_, err = s.db.Exec(insertQuery, newPackerItemId, itemId, packerId, orderId, itemQuantity, storeId)
+		_, err = tx.Exec(insertQuery, newPackerItemId, itemId, packerId, orderId, itemQuantity, storeId, weight)
 		if err != nil {
 			return response, fmt.Errorf("error inserting into packer_item table with itemId %d, packerId %d, orderId %d, storeId %d: %w", itemId, packerId, orderId, storeId, err)
 		}
+		if weight.Valid {
+			if err := recordOrderItemWeight(tx, orderId, itemId); err != nil {
+				return response, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return response, fmt.Errorf("error committing transaction: %w", err)
+		}
 	
 		packedQuantity++
 	}
@@ -241,7 +287,7 @@ func (s *PostgresStore) PackerPackItemQuick(itemId int, itemQuantity int, packer
 
 	// Retrieve total required quantity from the cart_item table using cart_id
 	var totalRequiredQuantity int
-	totalCartItemQuery := `SELECT SUM(quantity) FROM cart_item WHERE cart_id = $1`
+	totalCartItemQuery := `SELECT SUM(quantity) FROM cart_pick_item WHERE cart_id = $1`
 	err = s.db.QueryRow(totalCartItemQuery, cartId).Scan(&totalRequiredQuantity)
 	if err != nil {
 		return response, fmt.Errorf("error querying total required quantity from cart_item with cartId %d: %w", cartId, err)
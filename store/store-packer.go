@@ -393,9 +393,9 @@ func (s *PostgresStore) PackerFindOrder(req types.FindItemBasic) (FindItemRespon
     SELECT i.name, i.id, s.horizontal, s.vertical
     FROM Item i
     LEFT JOIN Shelf s ON i.id = s.item_id
-    WHERE i.barcode = $1 AND (s.store_id = $2 OR s.store_id IS NULL);
+    WHERE i.id = (SELECT item_id FROM item_barcode WHERE gtin = $1) AND (s.store_id = $2 OR s.store_id IS NULL);
     `
-	err := s.db.QueryRow(query, req.Barcode, req.StoreID).Scan(&response.ItemName, &response.ItemId, &horizontal, &vertical)
+	err := s.db.QueryRow(query, gtinKey(req.Barcode), req.StoreID).Scan(&response.ItemName, &response.ItemId, &horizontal, &vertical)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return response, fmt.Errorf("item with barcode '%s' not found", req.Barcode)
@@ -480,12 +480,12 @@ func (s *PostgresStore) PackerFindItem(new_req types.FindItemBasic) (*FindItemRe
         SELECT i.name, i.id, s.horizontal, s.vertical
         FROM item i
         LEFT JOIN shelf s ON i.id = s.item_id AND s.store_id = $2
-        WHERE i.barcode = $1
+        WHERE i.id = (SELECT item_id FROM item_barcode WHERE gtin = $1)
     `
 
 	// Execute the query
 	var response FindItemResponse
-	err := s.db.QueryRow(query, new_req.Barcode, new_req.StoreID).Scan(
+	err := s.db.QueryRow(query, gtinKey(new_req.Barcode), new_req.StoreID).Scan(
 		&response.ItemName,
 		&response.ItemId,
 		&response.ShelfHorizontal, // This will be NULL if there's no shelf
@@ -584,6 +584,7 @@ type FindItemResponse struct {
 }
 
 func (s *PostgresStore) PackerLoadItem(req types.LoadItemBasic) (LoadItemResponse, error) {
+	defer s.catalog.invalidateStock(req.StoreID, req.ItemID)
 	var response LoadItemResponse
 
 	// Start a transaction
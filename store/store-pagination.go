@@ -0,0 +1,225 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+// listCursor is the decoded form of the opaque cursor returned to clients.
+// It remembers the sort it was issued for so it cannot be replayed against a different ordering.
+// Null marks a row whose sort value is NULL; those rows sort last in either direction.
+type listCursor struct {
+	Sort  string  `json:"s"`
+	Desc  bool    `json:"d"`
+	Value string  `json:"v"`
+	Null  bool    `json:"n,omitempty"`
+	Keys  []int64 `json:"k"`
+}
+
+func encodeCursor(c listCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var c listCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
+
+// listQuery describes a keyset-paginated SELECT. Filters are added with ? placeholders,
+// which are numbered in the order they are added.
+type listQuery struct {
+	with        string            // optional CTE body, without the WITH keyword
+	columns     string            // columns read by the caller's scan function, in order
+	from        string            // FROM clause including joins
+	groupBy     string            // optional GROUP BY expressions
+	sorts       map[string]string // sort name -> SQL expression (must not be an aggregate)
+	defaultSort string
+	keys        []string // tie-breaker expressions that make each row unique, scanned as BIGINT
+	where       []string
+	args        []interface{}
+}
+
+func (q *listQuery) filter(cond string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(q.args)), 1)
+	}
+	q.where = append(q.where, cond)
+}
+
+func (q *listQuery) prefix() string {
+	if q.with == "" {
+		return ""
+	}
+	return "WITH " + q.with + "\n"
+}
+
+func (q *listQuery) tail(where []string) string {
+	var b strings.Builder
+	if len(where) > 0 {
+		b.WriteString("\nWHERE " + strings.Join(where, " AND "))
+	}
+	if q.groupBy != "" {
+		b.WriteString("\nGROUP BY " + q.groupBy)
+	}
+	return b.String()
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return types.DefaultPageLimit
+	}
+	if limit > types.MaxPageLimit {
+		return types.MaxPageLimit
+	}
+	return limit
+}
+
+// build returns the page query, its arguments, and the sort name that was applied.
+func (q *listQuery) build(params types.ListParams) (string, []interface{}, string, error) {
+	sortName := params.Sort
+	if sortName == "" {
+		sortName = q.defaultSort
+	}
+	sortExpr, ok := q.sorts[sortName]
+	if !ok {
+		return "", nil, "", fmt.Errorf("unsupported sort %q", params.Sort)
+	}
+
+	where := append([]string{}, q.where...)
+	args := append([]interface{}{}, q.args...)
+
+	tuple := append([]string{sortExpr}, q.keys...)
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if cursor.Sort != sortName || cursor.Desc != params.Desc || len(cursor.Keys) != len(q.keys) {
+			return "", nil, "", fmt.Errorf("cursor does not match the requested sort")
+		}
+
+		keyPlaceholders := []string{}
+		for _, key := range cursor.Keys {
+			args = append(args, key)
+			keyPlaceholders = append(keyPlaceholders, fmt.Sprintf("$%d", len(args)))
+		}
+
+		op := ">"
+		if params.Desc {
+			op = "<"
+		}
+		// A row comparison with a NULL is never true, so rows with a NULL sort value are
+		// matched on their own: they follow every non-NULL value and page by the keys alone.
+		if cursor.Null {
+			where = append(where, fmt.Sprintf("(%s) IS NULL AND (%s) %s (%s)",
+				sortExpr, strings.Join(q.keys, ", "), op, strings.Join(keyPlaceholders, ", ")))
+		} else {
+			args = append(args, cursor.Value)
+			placeholders := append([]string{fmt.Sprintf("$%d", len(args))}, keyPlaceholders...)
+			where = append(where, fmt.Sprintf("((%s) %s (%s) OR (%s) IS NULL)",
+				strings.Join(tuple, ", "), op, strings.Join(placeholders, ", "), sortExpr))
+		}
+	}
+
+	direction := "ASC"
+	if params.Desc {
+		direction = "DESC"
+	}
+	orderBy := make([]string, len(tuple))
+	for i, expr := range tuple {
+		orderBy[i] = expr + " " + direction
+	}
+	orderBy[0] += " NULLS LAST"
+
+	query := q.prefix() +
+		"SELECT " + q.columns + ", (" + sortExpr + ")::text, " + strings.Join(q.keys, ", ") +
+		"\nFROM " + q.from +
+		q.tail(where) +
+		"\nORDER BY " + strings.Join(orderBy, ", ") +
+		fmt.Sprintf("\nLIMIT %d", pageLimit(params.Limit)+1)
+
+	return query, args, sortName, nil
+}
+
+func (q *listQuery) count(db *sql.DB) (int, error) {
+	query := q.prefix() + "SELECT COUNT(*) FROM (SELECT 1 FROM " + q.from + q.tail(q.where) + ") AS counted"
+
+	var total int
+	if err := db.QueryRow(query, q.args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("error counting rows: %w", err)
+	}
+	return total, nil
+}
+
+// runListQuery executes q and returns one page. scan must read the listQuery columns into a
+// new row and append cursorDest to its Scan destinations.
+func runListQuery[T any](db *sql.DB, q *listQuery, params types.ListParams, scan func(rows *sql.Rows, cursorDest []interface{}) (T, error)) (*types.Page[T], error) {
+	query, args, sortName, err := q.build(params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying page: %w", err)
+	}
+	defer rows.Close()
+
+	limit := pageLimit(params.Limit)
+	page := &types.Page[T]{Data: []T{}}
+	var last listCursor
+
+	for rows.Next() {
+		cursor := listCursor{Sort: sortName, Desc: params.Desc, Keys: make([]int64, len(q.keys))}
+		var value sql.NullString
+		cursorDest := []interface{}{&value}
+		for i := range cursor.Keys {
+			cursorDest = append(cursorDest, &cursor.Keys[i])
+		}
+
+		row, err := scan(rows, cursorDest)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning page row: %w", err)
+		}
+		cursor.Value, cursor.Null = value.String, !value.Valid
+
+		if len(page.Data) == limit {
+			page.HasMore = true
+			break
+		}
+		page.Data = append(page.Data, row)
+		last = cursor
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating page rows: %w", err)
+	}
+
+	if page.HasMore {
+		page.NextCursor = encodeCursor(last)
+	}
+
+	if params.Cursor == "" {
+		total, err := q.count(db)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
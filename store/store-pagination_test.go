@@ -0,0 +1,37 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor listCursor
+	}{
+		{"ascending", listCursor{Sort: "id", Value: "42", Keys: []int64{42}}},
+		{"descending", listCursor{Sort: "name", Desc: true, Value: "amul butter", Keys: []int64{7, 3}}},
+		{"null value", listCursor{Sort: "price", Null: true, Keys: []int64{9}}},
+		{"unicode value", listCursor{Sort: "name", Value: "आटा", Keys: []int64{1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(encodeCursor(tt.cursor))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("got %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, s := range []string{"not base64!", "bm90IGpzb24", "W10"} {
+		if _, err := decodeCursor(s); err == nil {
+			t.Errorf("decodeCursor(%q) = nil error, want an error", s)
+		}
+	}
+}
@@ -113,6 +113,7 @@ func (s *PostgresStore) PhonePeCheckStatus(customerPhone string, cartID int, mer
 			response.Amount = 0
 			return response, fmt.Errorf("error committing transaction: %w", err)
 		}
+		s.invalidateCartStock(cartID)
 
 		_, err = s.sendOrderNotifToPacker()
 		if err != nil {
@@ -612,6 +613,7 @@ func (s *PostgresStore) PhonePePaymentCallback(cartID int, sign string, response
 			fmt.Printf("Error committing transaction: %v\n", err)
 			return nil, err
 		}
+		s.invalidateCartStock(cartID)
 
 		_, err = s.sendOrderNotifToPacker()
 		if err != nil {
@@ -636,6 +638,7 @@ func (s *PostgresStore) PhonePePaymentCallback(cartID int, sign string, response
 			fmt.Printf("Error committing transaction: %v\n", err)
 			return nil, err
 		}
+		s.invalidateCartStock(cartID)
 
 		result := &types.PaymentCallbackResult{
 			PaymentResponse:   paymentResponse,
@@ -0,0 +1,329 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// CreateProductGroupTable links items that are sizes of one product. An item belongs to at most one group.
+func (s *PostgresStore) CreateProductGroupTable(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS product_group (
+        id SERIAL PRIMARY KEY,
+        name VARCHAR(100) NOT NULL,
+        default_item_id INT REFERENCES item(id) ON DELETE SET NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        created_by INT
+    );
+
+    ALTER TABLE item ADD COLUMN IF NOT EXISTS product_group_id INT REFERENCES product_group(id) ON DELETE SET NULL;
+    CREATE INDEX IF NOT EXISTS item_product_group_id ON item (product_group_id);`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating product_group table: %w", err)
+	}
+	return nil
+}
+
+// variantCard is one card of a collapsed listing: the index of the variant it shows and the indexes
+// of all its variants. Items outside a product group have no variants.
+type variantCard struct {
+	shown    int
+	variants []int
+}
+
+// variantCards collapses a listing into cards. A product group takes the position of its first listed
+// item and shows its default variant when that is listed too.
+func variantCards(itemIDs []int, groupIDs []int, defaultIDs []int) []variantCard {
+	cards := make([]variantCard, 0, len(itemIDs))
+	byGroup := map[int]int{}
+	for i := range itemIDs {
+		if groupIDs[i] == 0 {
+			cards = append(cards, variantCard{shown: i})
+			continue
+		}
+		c, ok := byGroup[groupIDs[i]]
+		if !ok {
+			byGroup[groupIDs[i]] = len(cards)
+			cards = append(cards, variantCard{shown: i, variants: []int{i}})
+			continue
+		}
+		cards[c].variants = append(cards[c].variants, i)
+		if itemIDs[i] == defaultIDs[i] {
+			cards[c].shown = i
+		}
+	}
+	return cards
+}
+
+// variantBaseSize converts a pack size to grams, millilitres or pieces so sizes in different units sort together.
+func variantBaseSize(quantity int, unit string) float64 {
+	switch strings.ToLower(unit) {
+	case "kg", "l":
+		return float64(quantity) * 1000
+	case "mg":
+		return float64(quantity) / 1000
+	}
+	return float64(quantity)
+}
+
+// sortVariants orders a size selector from the smallest pack to the largest.
+func sortVariants(variants []types.ItemVariant) {
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variantBaseSize(variants[i].Quantity, variants[i].Unit_Of_Quantity) < variantBaseSize(variants[j].Quantity, variants[j].Unit_Of_Quantity)
+	})
+}
+
+// lockProductGroupItems locks the items being regrouped and checks that none belongs to another group.
+func lockProductGroupItems(tx *sql.Tx, groupID int, itemIDs []int) error {
+	rows, err := tx.Query(`SELECT id, COALESCE(product_group_id, 0) FROM item WHERE id = ANY($1) FOR UPDATE`, pq.Array(itemIDs))
+	if err != nil {
+		return fmt.Errorf("error locking items: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool, len(itemIDs))
+	for rows.Next() {
+		var id, current int
+		if err := rows.Scan(&id, &current); err != nil {
+			return fmt.Errorf("error scanning item: %w", err)
+		}
+		if current != 0 && current != groupID {
+			return fmt.Errorf("item %d already belongs to product group %d", id, current)
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range itemIDs {
+		if !found[id] {
+			return fmt.Errorf("item with id = [%d] not found", id)
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) CreateProductGroup(req types.CreateProductGroup) (*types.ProductGroup, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("product group name is required")
+	}
+	if len(req.ItemIDs) == 0 {
+		return nil, fmt.Errorf("a product group needs at least one item")
+	}
+	seen := make(map[int]bool, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("item %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	if req.DefaultItemID == 0 {
+		req.DefaultItemID = req.ItemIDs[0]
+	}
+	if !seen[req.DefaultItemID] {
+		return nil, fmt.Errorf("default item %d is not one of the group's items", req.DefaultItemID)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockProductGroupItems(tx, 0, req.ItemIDs); err != nil {
+		return nil, err
+	}
+
+	var groupID int
+	err = tx.QueryRow(`INSERT INTO product_group (name, default_item_id) VALUES ($1, $2) RETURNING id`, req.Name, req.DefaultItemID).Scan(&groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting into product_group: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE item SET product_group_id = $1 WHERE id = ANY($2)`, groupID, pq.Array(req.ItemIDs)); err != nil {
+		return nil, fmt.Errorf("error adding items to product group %d: %w", groupID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateAllItems()
+	return s.GetProductGroup(groupID)
+}
+
+// AddProductGroupVariant adds an item to a group, optionally as its new default variant.
+func (s *PostgresStore) AddProductGroupVariant(req types.ProductGroupVariant) (*types.ProductGroup, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockProductGroup(tx, req.ProductGroupID); err != nil {
+		return nil, err
+	}
+	if err := lockProductGroupItems(tx, req.ProductGroupID, []int{req.ItemID}); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE item SET product_group_id = $1 WHERE id = $2`, req.ProductGroupID, req.ItemID); err != nil {
+		return nil, fmt.Errorf("error adding item %d to product group %d: %w", req.ItemID, req.ProductGroupID, err)
+	}
+	if req.Default {
+		if _, err := tx.Exec(`UPDATE product_group SET default_item_id = $2 WHERE id = $1`, req.ProductGroupID, req.ItemID); err != nil {
+			return nil, fmt.Errorf("error setting default variant of product group %d: %w", req.ProductGroupID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateAllItems()
+	return s.GetProductGroup(req.ProductGroupID)
+}
+
+// RemoveProductGroupVariant takes an item out of its group. If it was the default, the smallest remaining
+// variant becomes the default; a group left without variants is deleted.
+func (s *PostgresStore) RemoveProductGroupVariant(req types.ProductGroupVariant) (*types.ProductGroup, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockProductGroup(tx, req.ProductGroupID); err != nil {
+		return nil, err
+	}
+
+	res, err := tx.Exec(`UPDATE item SET product_group_id = NULL WHERE id = $1 AND product_group_id = $2`, req.ItemID, req.ProductGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("error removing item %d from product group %d: %w", req.ItemID, req.ProductGroupID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("item %d is not a variant of product group %d", req.ItemID, req.ProductGroupID)
+	}
+
+	rows, err := tx.Query(`SELECT id, quantity, unit_of_quantity FROM item WHERE product_group_id = $1`, req.ProductGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying variants of product group %d: %w", req.ProductGroupID, err)
+	}
+	var remaining []types.ItemVariant
+	for rows.Next() {
+		var v types.ItemVariant
+		if err := rows.Scan(&v.ItemID, &v.Quantity, &v.Unit_Of_Quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning variant: %w", err)
+		}
+		remaining = append(remaining, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	deleted := len(remaining) == 0
+	if deleted {
+		if _, err := tx.Exec(`DELETE FROM product_group WHERE id = $1`, req.ProductGroupID); err != nil {
+			return nil, fmt.Errorf("error deleting product group %d: %w", req.ProductGroupID, err)
+		}
+	} else {
+		sortVariants(remaining)
+		query := `
+        UPDATE product_group SET default_item_id = $2
+        WHERE id = $1 AND (default_item_id IS NULL OR default_item_id = $3)`
+
+		if _, err := tx.Exec(query, req.ProductGroupID, remaining[0].ItemID, req.ItemID); err != nil {
+			return nil, fmt.Errorf("error setting default variant of product group %d: %w", req.ProductGroupID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.catalog.invalidateAllItems()
+	if deleted {
+		return nil, nil
+	}
+	return s.GetProductGroup(req.ProductGroupID)
+}
+
+func (s *PostgresStore) SetProductGroupDefault(req types.ProductGroupVariant) (*types.ProductGroup, error) {
+	query := `
+    UPDATE product_group SET default_item_id = $2
+    WHERE id = $1 AND EXISTS (SELECT 1 FROM item WHERE id = $2 AND product_group_id = $1)`
+
+	res, err := s.db.Exec(query, req.ProductGroupID, req.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("error setting default variant of product group %d: %w", req.ProductGroupID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("item %d is not a variant of product group %d", req.ItemID, req.ProductGroupID)
+	}
+
+	s.catalog.invalidateAllItems()
+	return s.GetProductGroup(req.ProductGroupID)
+}
+
+func lockProductGroup(tx *sql.Tx, groupID int) error {
+	var id int
+	err := tx.QueryRow(`SELECT id FROM product_group WHERE id = $1 FOR UPDATE`, groupID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product group with id = [%d] not found", groupID)
+	}
+	if err != nil {
+		return fmt.Errorf("error locking product group %d: %w", groupID, err)
+	}
+	return nil
+}
+
+// GetProductGroup returns a group with its variants at catalog level, priced by MRP.
+func (s *PostgresStore) GetProductGroup(groupID int) (*types.ProductGroup, error) {
+	group := &types.ProductGroup{}
+	var defaultItemID sql.NullInt64
+	err := s.db.QueryRow(`SELECT id, name, default_item_id, created_at FROM product_group WHERE id = $1`, groupID).
+		Scan(&group.ID, &group.Name, &defaultItemID, &group.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product group with id = [%d] not found", groupID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying product group %d: %w", groupID, err)
+	}
+	group.DefaultItemID = int(defaultItemID.Int64)
+
+	query := `
+    SELECT i.id, i.name, i.quantity, i.unit_of_quantity, COALESCE(ifin.mrp_price, 0),
+           COALESCE((SELECT image_url FROM item_image WHERE item_id = i.id ORDER BY order_position LIMIT 1), '')
+    FROM item i
+    LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+    WHERE i.product_group_id = $1`
+
+	rows, err := s.db.Query(query, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying variants of product group %d: %w", groupID, err)
+	}
+	defer rows.Close()
+
+	group.Variants = []types.ItemVariant{}
+	for rows.Next() {
+		var v types.ItemVariant
+		if err := rows.Scan(&v.ItemID, &v.Name, &v.Quantity, &v.Unit_Of_Quantity, &v.MRP_Price, &v.Image); err != nil {
+			return nil, fmt.Errorf("error scanning variant: %w", err)
+		}
+		v.Store_Price = v.MRP_Price
+		v.Default = v.ItemID == group.DefaultItemID
+		group.Variants = append(group.Variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortVariants(group.Variants)
+	return group, nil
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariantCards(t *testing.T) {
+	tests := []struct {
+		name       string
+		itemIDs    []int
+		groupIDs   []int
+		defaultIDs []int
+		want       []variantCard
+	}{
+		{
+			name: "empty",
+			want: []variantCard{},
+		},
+		{
+			name:       "ungrouped items keep their order",
+			itemIDs:    []int{1, 2, 3},
+			groupIDs:   []int{0, 0, 0},
+			defaultIDs: []int{0, 0, 0},
+			want:       []variantCard{{shown: 0}, {shown: 1}, {shown: 2}},
+		},
+		{
+			name:       "group takes the position of its first item and shows its default",
+			itemIDs:    []int{10, 20, 11, 12},
+			groupIDs:   []int{5, 0, 5, 5},
+			defaultIDs: []int{11, 0, 11, 11},
+			want:       []variantCard{{shown: 2, variants: []int{0, 2, 3}}, {shown: 1}},
+		},
+		{
+			name:       "first item is shown when the default is not listed",
+			itemIDs:    []int{10, 12},
+			groupIDs:   []int{5, 5},
+			defaultIDs: []int{11, 11},
+			want:       []variantCard{{shown: 0, variants: []int{0, 1}}},
+		},
+		{
+			name:       "two groups",
+			itemIDs:    []int{1, 2, 3, 4},
+			groupIDs:   []int{7, 8, 7, 8},
+			defaultIDs: []int{1, 4, 1, 4},
+			want:       []variantCard{{shown: 0, variants: []int{0, 2}}, {shown: 3, variants: []int{1, 3}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := variantCards(tt.itemIDs, tt.groupIDs, tt.defaultIDs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// CreatePurchaseLimitTables adds per-item and per-category purchase limits, the restrictions an
+// item carries, and the restrictions a customer has confirmed on a cart.
+func (s *PostgresStore) CreatePurchaseLimitTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS purchase_limit (
+        id SERIAL PRIMARY KEY,
+        item_id INT UNIQUE REFERENCES item(id) ON DELETE CASCADE,
+        category_id INT UNIQUE REFERENCES category(id) ON DELETE CASCADE,
+        max_per_order INT NOT NULL DEFAULT 0 CHECK (max_per_order >= 0),
+        max_per_customer_day INT NOT NULL DEFAULT 0 CHECK (max_per_customer_day >= 0),
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CHECK ((item_id IS NULL) <> (category_id IS NULL))
+    );
+
+    ALTER TABLE item ADD COLUMN IF NOT EXISTS restrictions TEXT[] NOT NULL DEFAULT '{}';
+
+    CREATE TABLE IF NOT EXISTS cart_restriction_confirmation (
+        cart_id INT NOT NULL REFERENCES shopping_cart(id) ON DELETE CASCADE,
+        restriction TEXT NOT NULL,
+        confirmed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (cart_id, restriction)
+    );
+
+    CREATE INDEX IF NOT EXISTS sales_order_customer_date ON sales_order (customer_id, order_date);`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating purchase limit tables: %w", err)
+	}
+	return nil
+}
+
+// purchaseLimitUsageQuery returns, for each limit that covers an item in cart $1, the quantity in
+// the cart and the quantity the cart's customer has ordered today in other carts. A category limit
+// covers the items of the category and of its subcategories. With $2 set, only the limits covering
+// that item are returned.
+const purchaseLimitUsageQuery = `
+    WITH RECURSIVE limited AS (
+        SELECT pl.category_id AS limit_category, pl.category_id
+        FROM purchase_limit pl
+        WHERE pl.category_id IS NOT NULL
+        UNION
+        SELECT l.limit_category, c.id
+        FROM category c
+        JOIN limited l ON c.parent_id = l.category_id
+    ),
+    limited_item AS (
+        SELECT DISTINCT l.limit_category, ic.item_id
+        FROM limited l
+        JOIN item_category ic ON ic.category_id = l.category_id
+    ),
+    covered AS (
+        SELECT pl.item_id AS target_item, 0 AS target_category, pl.item_id, pl.max_per_order, pl.max_per_customer_day
+        FROM purchase_limit pl
+        WHERE pl.item_id IS NOT NULL
+        UNION ALL
+        SELECT 0, li.limit_category, li.item_id, pl.max_per_order, pl.max_per_customer_day
+        FROM limited_item li
+        JOIN purchase_limit pl ON pl.category_id = li.limit_category
+    ),
+    ordered AS (
+        SELECT oci.item_id, SUM(oci.quantity) AS quantity
+        FROM sales_order so
+        JOIN shopping_cart sc ON sc.id = $1
+        JOIN cart_item oci ON oci.cart_id = so.cart_id
+        WHERE so.customer_id = sc.customer_id AND so.cart_id <> sc.id AND so.order_date >= CURRENT_DATE
+        GROUP BY oci.item_id
+    )
+    SELECT cv.target_item, cv.target_category, cv.max_per_order, cv.max_per_customer_day,
+           COALESCE(SUM(ci.quantity), 0), COALESCE(SUM(o.quantity), 0)
+    FROM covered cv
+    LEFT JOIN cart_item ci ON ci.item_id = cv.item_id AND ci.cart_id = $1
+    LEFT JOIN ordered o ON o.item_id = cv.item_id
+    GROUP BY cv.target_item, cv.target_category, cv.max_per_order, cv.max_per_customer_day
+    HAVING COALESCE(SUM(ci.quantity), 0) > 0
+       AND ($2 = 0 OR bool_or(cv.item_id = $2))
+    ORDER BY cv.target_item = 0, cv.target_item, cv.target_category`
+
+// checkPurchaseLimits returns a *types.PurchaseLimitError for the first limit cart cartID breaks,
+// per order limits before daily ones, or for a restricted item whose restriction the customer has
+// not confirmed on the cart. With itemID set, only the limits and restrictions of that item are
+// checked, so lowering the quantity of one item never fails because of another.
+func checkPurchaseLimits(q queryer, cartID, itemID int) error {
+	rows, err := q.Query(purchaseLimitUsageQuery, cartID, itemID)
+	if err != nil {
+		return fmt.Errorf("error querying purchase limits for cart %d: %w", cartID, err)
+	}
+	defer rows.Close()
+
+	var daily *types.PurchaseLimitError
+	for rows.Next() {
+		var targetItem, targetCategory, maxPerOrder, maxPerDay, inCart, orderedToday int
+		if err := rows.Scan(&targetItem, &targetCategory, &maxPerOrder, &maxPerDay, &inCart, &orderedToday); err != nil {
+			return fmt.Errorf("error scanning purchase limit: %w", err)
+		}
+
+		if maxPerOrder > 0 && inCart > maxPerOrder {
+			code := types.ErrItemOrderLimit
+			if targetCategory != 0 {
+				code = types.ErrCategoryOrderLimit
+			}
+			return &types.PurchaseLimitError{Code: code, ItemID: targetItem, CategoryID: targetCategory,
+				Limit: maxPerOrder, Quantity: inCart}
+		}
+		if daily == nil && maxPerDay > 0 && inCart+orderedToday > maxPerDay {
+			code := types.ErrItemDailyLimit
+			if targetCategory != 0 {
+				code = types.ErrCategoryDailyLimit
+			}
+			daily = &types.PurchaseLimitError{Code: code, ItemID: targetItem, CategoryID: targetCategory,
+				Limit: maxPerDay, Quantity: inCart + orderedToday}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if daily != nil {
+		return daily
+	}
+
+	var restrictedItem int
+	var restriction string
+	err = q.QueryRow(`
+        SELECT ci.item_id, r.restriction
+        FROM cart_item ci
+        JOIN item i ON i.id = ci.item_id
+        CROSS JOIN LATERAL unnest(i.restrictions) AS r(restriction)
+        WHERE ci.cart_id = $1 AND ci.quantity > 0 AND ($2 = 0 OR ci.item_id = $2)
+          AND NOT EXISTS (
+              SELECT 1 FROM cart_restriction_confirmation crc
+              WHERE crc.cart_id = ci.cart_id AND crc.restriction = r.restriction
+          )
+        ORDER BY ci.item_id, r.restriction
+        LIMIT 1`, cartID, itemID).Scan(&restrictedItem, &restriction)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking item restrictions for cart %d: %w", cartID, err)
+	}
+	return &types.PurchaseLimitError{Code: types.ErrRestrictionUnconfirmed, ItemID: restrictedItem, Restriction: restriction}
+}
+
+// SetPurchaseLimit sets the limits of an item or a category. Setting both limits to zero removes
+// them.
+func (s *PostgresStore) SetPurchaseLimit(req types.SetPurchaseLimit) (*types.PurchaseLimit, error) {
+	if (req.ItemID == 0) == (req.CategoryID == 0) {
+		return nil, fmt.Errorf("a purchase limit needs exactly one of item_id and category_id")
+	}
+	if req.MaxPerOrder < 0 || req.MaxPerCustomerDay < 0 {
+		return nil, fmt.Errorf("purchase limits cannot be negative")
+	}
+
+	target, id := "item_id", req.ItemID
+	if req.CategoryID != 0 {
+		target, id = "category_id", req.CategoryID
+	}
+
+	if req.MaxPerOrder == 0 && req.MaxPerCustomerDay == 0 {
+		if _, err := s.db.Exec(`DELETE FROM purchase_limit WHERE `+target+` = $1`, id); err != nil {
+			return nil, fmt.Errorf("error removing purchase limit: %w", err)
+		}
+		return &types.PurchaseLimit{ItemID: req.ItemID, CategoryID: req.CategoryID}, nil
+	}
+
+	_, err := s.db.Exec(`
+        INSERT INTO purchase_limit (`+target+`, max_per_order, max_per_customer_day)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (`+target+`) DO UPDATE
+        SET max_per_order = EXCLUDED.max_per_order,
+            max_per_customer_day = EXCLUDED.max_per_customer_day,
+            updated_at = CURRENT_TIMESTAMP`, id, req.MaxPerOrder, req.MaxPerCustomerDay)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%s %d does not exist", target[:len(target)-3], id)
+		}
+		return nil, fmt.Errorf("error setting purchase limit: %w", err)
+	}
+
+	limits, err := s.GetPurchaseLimits(types.PurchaseLimitRequest{ItemID: req.ItemID, CategoryID: req.CategoryID})
+	if err != nil {
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return nil, fmt.Errorf("purchase limit was not saved")
+	}
+	return &limits[0], nil
+}
+
+// GetPurchaseLimits lists the purchase limits, filtered to an item or a category when one is set.
+func (s *PostgresStore) GetPurchaseLimits(req types.PurchaseLimitRequest) ([]types.PurchaseLimit, error) {
+	rows, err := s.db.Query(`
+        SELECT pl.id, COALESCE(pl.item_id, 0), COALESCE(pl.category_id, 0), COALESCE(i.name, c.name, ''),
+               pl.max_per_order, pl.max_per_customer_day, pl.updated_at
+        FROM purchase_limit pl
+        LEFT JOIN item i ON i.id = pl.item_id
+        LEFT JOIN category c ON c.id = pl.category_id
+        WHERE ($1 = 0 OR pl.item_id = $1) AND ($2 = 0 OR pl.category_id = $2)
+        ORDER BY pl.item_id IS NULL, pl.item_id, pl.category_id`, req.ItemID, req.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying purchase limits: %w", err)
+	}
+	defer rows.Close()
+
+	limits := []types.PurchaseLimit{}
+	for rows.Next() {
+		var limit types.PurchaseLimit
+		var updatedAt time.Time
+		if err := rows.Scan(&limit.ID, &limit.ItemID, &limit.CategoryID, &limit.Name,
+			&limit.MaxPerOrder, &limit.MaxPerCustomerDay, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning purchase limit: %w", err)
+		}
+		limit.UpdatedAt = updatedAt.Format(time.RFC3339)
+		limits = append(limits, limit)
+	}
+	return limits, rows.Err()
+}
+
+// SetItemRestrictions replaces the restrictions of an item. Carts that hold the item need the new
+// restrictions confirmed before their next update or checkout.
+func (s *PostgresStore) SetItemRestrictions(req types.SetItemRestrictions) ([]string, error) {
+	restrictions := []string{}
+	seen := map[string]bool{}
+	for _, r := range req.Restrictions {
+		known := false
+		for _, k := range types.ItemRestrictions {
+			known = known || k == r
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown item restriction %q", r)
+		}
+		if !seen[r] {
+			seen[r] = true
+			restrictions = append(restrictions, r)
+		}
+	}
+
+	res, err := s.db.Exec(`UPDATE item SET restrictions = $2 WHERE id = $1`, req.ItemID, pq.Array(restrictions))
+	if err != nil {
+		return nil, fmt.Errorf("error setting item restrictions: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, fmt.Errorf("item %d does not exist", req.ItemID)
+	}
+	return restrictions, nil
+}
+
+// GetCartRestrictions lists the restrictions of the items in a cart and which of them the customer
+// has confirmed.
+func (s *PostgresStore) GetCartRestrictions(cartID int) (*types.CartRestrictions, error) {
+	var required, confirmed pq.StringArray
+	err := s.db.QueryRow(`
+        SELECT ARRAY(
+                   SELECT DISTINCT r FROM cart_item ci
+                   JOIN item i ON i.id = ci.item_id
+                   CROSS JOIN LATERAL unnest(i.restrictions) AS r
+                   WHERE ci.cart_id = $1 AND ci.quantity > 0
+                   ORDER BY r
+               ),
+               ARRAY(SELECT restriction FROM cart_restriction_confirmation WHERE cart_id = $1 ORDER BY restriction)`,
+		cartID).Scan(&required, &confirmed)
+	if err != nil {
+		return nil, fmt.Errorf("error querying cart restrictions: %w", err)
+	}
+
+	cart := &types.CartRestrictions{
+		CartId:      cartID,
+		Required:    []string(required),
+		Confirmed:   []string(confirmed),
+		Unconfirmed: []string{},
+	}
+	done := map[string]bool{}
+	for _, r := range confirmed {
+		done[r] = true
+	}
+	for _, r := range required {
+		if !done[r] {
+			cart.Unconfirmed = append(cart.Unconfirmed, r)
+		}
+	}
+	return cart, nil
+}
+
+// ConfirmCartRestriction records that the customer has confirmed a restriction, such as their age,
+// for the cart.
+func (s *PostgresStore) ConfirmCartRestriction(cartID int, restriction string) (*types.CartRestrictions, error) {
+	known := false
+	for _, k := range types.ItemRestrictions {
+		known = known || k == restriction
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown item restriction %q", restriction)
+	}
+
+	_, err := s.db.Exec(`
+        INSERT INTO cart_restriction_confirmation (cart_id, restriction)
+        VALUES ($1, $2)
+        ON CONFLICT (cart_id, restriction) DO UPDATE SET confirmed_at = CURRENT_TIMESTAMP`, cartID, restriction)
+	if err != nil {
+		return nil, fmt.Errorf("error confirming cart restriction: %w", err)
+	}
+	return s.GetCartRestrictions(cartID)
+}
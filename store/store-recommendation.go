@@ -0,0 +1,330 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+const (
+	// recommendationInterval is how often RunRecommendationJob refreshes the tables.
+	recommendationInterval = 24 * time.Hour
+	// recommendationWindowDays limits the order history, so seasonal and delisted items fade out.
+	recommendationWindowDays = 365
+	// recommendationMinPairOrders drops pairs seen together too rarely for their lift to mean anything.
+	recommendationMinPairOrders = 3
+	// recommendationMaxRelated is the number of related items kept per item and store.
+	recommendationMaxRelated   = 20
+	recommendationDefaultLimit = 10
+	recommendationMaxLimit     = 50
+)
+
+// CreateRecommendationTables holds the output of the recommendation batch job: item co-occurrence per
+// store and per-customer purchase frequency, both computed from completed orders.
+func (s *PostgresStore) CreateRecommendationTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS item_pair_stat (
+        store_id INT NOT NULL REFERENCES store(id) ON DELETE CASCADE,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        related_item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        pair_orders INT NOT NULL,
+        support DOUBLE PRECISION NOT NULL,
+        confidence DOUBLE PRECISION NOT NULL,
+        lift DOUBLE PRECISION NOT NULL,
+        PRIMARY KEY (store_id, item_id, related_item_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS customer_item_frequency (
+        customer_id INT NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+        store_id INT NOT NULL REFERENCES store(id) ON DELETE CASCADE,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        order_count INT NOT NULL,
+        total_quantity INT NOT NULL,
+        last_ordered_at TIMESTAMP NOT NULL,
+        PRIMARY KEY (customer_id, store_id, item_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS recommendation_run (
+        id SERIAL PRIMARY KEY,
+        started_at TIMESTAMP NOT NULL,
+        finished_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        orders INT NOT NULL,
+        pairs INT NOT NULL,
+        customers INT NOT NULL
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating recommendation tables: %w", err)
+	}
+	return nil
+}
+
+// RefreshRecommendations recomputes the recommendation tables in one transaction, so readers see either
+// the old or the new results. It returns nil without a run when another instance is already refreshing.
+func (s *PostgresStore) RefreshRecommendations() (*types.RecommendationRun, error) {
+	started := time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock(hashtext('recommendations'))`).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("error locking recommendations: %w", err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	run := &types.RecommendationRun{}
+	countQuery := `
+    SELECT COUNT(*) FROM sales_order
+    WHERE order_status = 'completed' AND order_date >= CURRENT_TIMESTAMP - $1 * INTERVAL '1 day'`
+
+	if err := tx.QueryRow(countQuery, recommendationWindowDays).Scan(&run.Orders); err != nil {
+		return nil, fmt.Errorf("error counting orders: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM item_pair_stat`); err != nil {
+		return nil, fmt.Errorf("error clearing item_pair_stat: %w", err)
+	}
+
+	// support = P(a and b), confidence = P(b | a), lift = P(a and b) / (P(a) P(b)), all over the store's orders
+	pairQuery := `
+    WITH lines AS (
+        SELECT DISTINCT so.store_id, so.id AS order_id, ci.item_id
+        FROM sales_order so
+        JOIN cart_item ci ON ci.cart_id = so.cart_id
+        WHERE so.order_status = 'completed' AND so.order_date >= CURRENT_TIMESTAMP - $1 * INTERVAL '1 day'
+    ), totals AS (
+        SELECT store_id, COUNT(DISTINCT order_id) AS orders FROM lines GROUP BY store_id
+    ), item_orders AS (
+        SELECT store_id, item_id, COUNT(*) AS orders FROM lines GROUP BY store_id, item_id
+    ), pairs AS (
+        SELECT a.store_id, a.item_id, b.item_id AS related_item_id, COUNT(*) AS orders
+        FROM lines a
+        JOIN lines b ON a.order_id = b.order_id AND a.item_id <> b.item_id
+        GROUP BY a.store_id, a.item_id, b.item_id
+        HAVING COUNT(*) >= $2
+    ), scored AS (
+        SELECT p.store_id, p.item_id, p.related_item_id, p.orders,
+               p.orders::float8 / t.orders AS support,
+               p.orders::float8 / ia.orders AS confidence,
+               p.orders::float8 * t.orders / (ia.orders * ib.orders) AS lift
+        FROM pairs p
+        JOIN totals t ON t.store_id = p.store_id
+        JOIN item_orders ia ON ia.store_id = p.store_id AND ia.item_id = p.item_id
+        JOIN item_orders ib ON ib.store_id = p.store_id AND ib.item_id = p.related_item_id
+    ), ranked AS (
+        SELECT *, ROW_NUMBER() OVER (PARTITION BY store_id, item_id ORDER BY lift DESC, orders DESC) AS rank
+        FROM scored
+    )
+    INSERT INTO item_pair_stat (store_id, item_id, related_item_id, pair_orders, support, confidence, lift)
+    SELECT store_id, item_id, related_item_id, orders, support, confidence, lift
+    FROM ranked
+    WHERE rank <= $3`
+
+	res, err := tx.Exec(pairQuery, recommendationWindowDays, recommendationMinPairOrders, recommendationMaxRelated)
+	if err != nil {
+		return nil, fmt.Errorf("error computing item pairs: %w", err)
+	}
+	pairs, _ := res.RowsAffected()
+	run.Pairs = int(pairs)
+
+	if _, err := tx.Exec(`DELETE FROM customer_item_frequency`); err != nil {
+		return nil, fmt.Errorf("error clearing customer_item_frequency: %w", err)
+	}
+
+	frequencyQuery := `
+    INSERT INTO customer_item_frequency (customer_id, store_id, item_id, order_count, total_quantity, last_ordered_at)
+    SELECT so.customer_id, so.store_id, ci.item_id, COUNT(DISTINCT so.id), SUM(ci.quantity), MAX(so.order_date)
+    FROM sales_order so
+    JOIN cart_item ci ON ci.cart_id = so.cart_id
+    WHERE so.order_status = 'completed' AND so.order_date >= CURRENT_TIMESTAMP - $1 * INTERVAL '1 day'
+      AND so.customer_id IS NOT NULL AND so.store_id IS NOT NULL
+    GROUP BY so.customer_id, so.store_id, ci.item_id`
+
+	if _, err := tx.Exec(frequencyQuery, recommendationWindowDays); err != nil {
+		return nil, fmt.Errorf("error computing purchase frequency: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT COUNT(DISTINCT customer_id) FROM customer_item_frequency`).Scan(&run.Customers); err != nil {
+		return nil, fmt.Errorf("error counting customers: %w", err)
+	}
+
+	err = tx.QueryRow(`
+        INSERT INTO recommendation_run (started_at, finished_at, orders, pairs, customers)
+        VALUES ($1, clock_timestamp(), $2, $3, $4)
+        RETURNING id, started_at, finished_at`,
+		started, run.Orders, run.Pairs, run.Customers).Scan(&run.ID, &run.StartedAt, &run.FinishedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error recording recommendation run: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// RunRecommendationJob refreshes the recommendations whenever the last run is older than
+// recommendationInterval, until ctx is done. Several instances can run it at once.
+func (s *PostgresStore) RunRecommendationJob(ctx context.Context) error {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		var due bool
+		dueQuery := `SELECT COALESCE(MAX(finished_at) < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second', TRUE) FROM recommendation_run`
+		if err := s.db.QueryRow(dueQuery, int(recommendationInterval.Seconds())).Scan(&due); err != nil {
+			log.Printf("recommendations: %v", err)
+		} else if due {
+			run, err := s.RefreshRecommendations()
+			if err != nil {
+				log.Printf("recommendations: %v", err)
+			} else if run != nil {
+				log.Printf("recommendations: %d orders, %d pairs, %d customers", run.Orders, run.Pairs, run.Customers)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func recommendationLimit(limit int) int {
+	if limit <= 0 {
+		return recommendationDefaultLimit
+	}
+	if limit > recommendationMaxLimit {
+		return recommendationMaxLimit
+	}
+	return limit
+}
+
+// customerIDByPhone resolves the authenticated customer, so requests cannot read another customer's data.
+func (s *PostgresStore) customerIDByPhone(phone string) (int, error) {
+	var id int
+	err := s.db.QueryRow(`SELECT id FROM customer WHERE phone = $1`, phone).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("customer with phone %s not found", phone)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error querying customer: %w", err)
+	}
+	return id, nil
+}
+
+// recommendedItemColumns and recommendedItemJoins describe an active, listed, in-stock item of the store in $1.
+const recommendedItemColumns = `
+        i.id, i.name, COALESCE(b.name, ''),
+        COALESCE((SELECT image_url FROM item_image WHERE item_id = i.id ORDER BY order_position LIMIT 1), ''),
+        i.quantity, COALESCE(i.unit_of_quantity::text, ''),
+        COALESCE(ifin.mrp_price, 0), istore.discount, COALESCE(store_selling_price(ifin.mrp_price, istore.discount, istore.price_override), istore.store_price, 0),
+        istore.stock_quantity`
+
+const recommendedItemJoins = `
+    JOIN item i ON i.id = r.item_id AND i.status = 'active'
+    JOIN item_store istore ON istore.item_id = i.id AND istore.store_id = $1 AND istore.listed AND istore.stock_quantity > 0
+    LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+    LEFT JOIN brand b ON b.id = i.brand_id`
+
+func scanRecommendedItems(rows *sql.Rows, withLift bool) ([]types.RecommendedItem, error) {
+	defer rows.Close()
+
+	items := []types.RecommendedItem{}
+	for rows.Next() {
+		var item types.RecommendedItem
+		var extra interface{} = &item.LastOrderedAt
+		if withLift {
+			extra = &item.Lift
+		}
+		err := rows.Scan(&item.ItemID, &item.Name, &item.Brand, &item.Image, &item.Quantity, &item.Unit_Of_Quantity,
+			&item.MRP_Price, &item.Discount, &item.Store_Price, &item.Stock_Quantity, &item.Score, extra)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning recommended item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// CartRecommendations returns items frequently bought together with the items of the customer's cart.
+// Items already in the cart and pairs no more likely than chance (lift <= 1) are left out.
+func (s *PostgresStore) CartRecommendations(req types.RecommendationRequest) ([]types.RecommendedItem, error) {
+	customerID, err := s.customerIDByPhone(req.PhoneAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM shopping_cart WHERE id = $1 AND customer_id = $2)`, req.CartID, customerID).Scan(&owned); err != nil {
+		return nil, fmt.Errorf("error checking cart %d: %w", req.CartID, err)
+	}
+	if !owned {
+		return nil, fmt.Errorf("cart %d does not belong to the customer", req.CartID)
+	}
+
+	query := `
+    WITH cart AS (
+        SELECT item_id FROM cart_item WHERE cart_id = $2
+    ), r AS (
+        SELECT p.related_item_id AS item_id, SUM(p.confidence) AS score, MAX(p.lift) AS lift
+        FROM item_pair_stat p
+        WHERE p.store_id = $1 AND p.item_id IN (SELECT item_id FROM cart)
+          AND p.related_item_id NOT IN (SELECT item_id FROM cart)
+          AND p.lift > 1
+        GROUP BY p.related_item_id
+    )
+    SELECT ` + recommendedItemColumns + `, r.score, r.lift
+    FROM r` + recommendedItemJoins + `
+    ORDER BY r.score DESC, r.lift DESC, i.id
+    LIMIT $3`
+
+	rows, err := s.reader(readCatalog).Query(query, req.StoreID, req.CartID, recommendationLimit(req.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("error querying cart recommendations: %w", err)
+	}
+	items, err := scanRecommendedItems(rows, true)
+	if err != nil {
+		return nil, err
+	}
+	return items, s.translateRecommendedItems(req.Languages, items)
+}
+
+// BuyAgainRecommendations returns the customer's most often ordered items that the store has in stock.
+func (s *PostgresStore) BuyAgainRecommendations(req types.RecommendationRequest) ([]types.RecommendedItem, error) {
+	customerID, err := s.customerIDByPhone(req.PhoneAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+    WITH r AS (
+        SELECT item_id, order_count, last_ordered_at
+        FROM customer_item_frequency
+        WHERE customer_id = $2 AND store_id = $1
+    )
+    SELECT ` + recommendedItemColumns + `, r.order_count::float8, r.last_ordered_at::text
+    FROM r` + recommendedItemJoins + `
+    ORDER BY r.order_count DESC, r.last_ordered_at DESC, i.id
+    LIMIT $3`
+
+	rows, err := s.reader(readCatalog).Query(query, req.StoreID, customerID, recommendationLimit(req.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("error querying buy-again recommendations: %w", err)
+	}
+	items, err := scanRecommendedItems(rows, false)
+	if err != nil {
+		return nil, err
+	}
+	return items, s.translateRecommendedItems(req.Languages, items)
+}
@@ -535,7 +535,7 @@ func (s *PostgresStore) GetSalesOrderDetails(salesOrderID, customerID int) ([]*S
 // ... rest of the implementation remains the same
 
 func (s *PostgresStore) Get_All_Sales_Orders() ([]*types.Sales_Order, error) {
-	rows, err := s.db.Query("SELECT id, delivery_partner_id, cart_id, store_id, customer_id, address_id, paid, payment_type, order_date FROM sales_order")
+	rows, err := s.reader(readOrders).Query("SELECT id, delivery_partner_id, cart_id, store_id, customer_id, address_id, paid, payment_type, order_date FROM sales_order")
 	if err != nil {
 		return nil, err
 	}
@@ -558,6 +558,42 @@ func (s *PostgresStore) Get_All_Sales_Orders() ([]*types.Sales_Order, error) {
 	return salesOrders, nil
 }
 
+// ListSalesOrders is the paginated form of Get_All_Sales_Orders. Status matches order_status.
+func (s *PostgresStore) ListSalesOrders(params types.ListParams) (*types.Page[*types.Sales_Order], error) {
+	q := &listQuery{
+		columns: "so.id, so.delivery_partner_id, so.cart_id, so.store_id, so.customer_id, so.address_id, so.paid, so.payment_type, so.order_date",
+		from:    "sales_order so",
+		sorts: map[string]string{
+			"id":         "so.id",
+			"order_date": "so.order_date",
+		},
+		defaultSort: "id",
+		keys:        []string{"so.id"},
+	}
+
+	if params.StoreID != 0 {
+		q.filter("so.store_id = ?", params.StoreID)
+	}
+	if params.Status != "" {
+		q.filter("so.order_status::text = ?", params.Status)
+	}
+	if params.From != nil {
+		q.filter("so.order_date >= ?", *params.From)
+	}
+	if params.To != nil {
+		q.filter("so.order_date < ?", *params.To)
+	}
+
+	return runListQuery(s.reader(readOrders), q, params, func(rows *sql.Rows, cursorDest []interface{}) (*types.Sales_Order, error) {
+		var order types.Sales_Order
+		dest := []interface{}{&order.ID, &order.DeliveryPartnerID, &order.CartID, &order.StoreID, &order.CustomerID, &order.AddressID, &order.Paid, &order.PaymentType, &order.OrderDate}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return nil, err
+		}
+		return &order, nil
+	})
+}
+
 type OrderDetails struct {
 	OrderID      int    `json:"order_id"`
 	OrderDate    string `json:"order_date"`
@@ -877,12 +913,14 @@ func (s *PostgresStore) PackOrder(storeId int, phoneNumber string) ([]PackedItem
 	}
 
 	// Fetching items for the updated order, no changes needed
+	// Bundles are picked as their components
 	itemsQuery := `
         SELECT i.id, i.name, b.name, i.quantity, i.unit_of_quantity, ci.quantity, 
-               s.horizontal, s.vertical
-        FROM cart_item ci
+               s.horizontal, s.vertical, bundle.name
+        FROM cart_pick_item ci
         JOIN item i ON ci.item_id = i.id
         JOIN brand b ON i.brand_id = b.id
+        LEFT JOIN item bundle ON ci.bundle_item_id = bundle.id
         LEFT JOIN shelf s ON i.id = s.item_id AND s.store_id = $2
         WHERE ci.cart_id = (SELECT cart_id FROM sales_order WHERE id = $1)
     `
@@ -898,7 +936,8 @@ func (s *PostgresStore) PackOrder(storeId int, phoneNumber string) ([]PackedItem
 		var item PackedItem
 		var horizontal sql.NullInt64
 		var vertical sql.NullString
-		if err := rows.Scan(&item.ItemID, &item.Name, &item.Brand, &item.Quantity, &item.UnitOfQuantity, &item.ItemQuantity, &horizontal, &vertical); err != nil {
+		var bundle sql.NullString
+		if err := rows.Scan(&item.ItemID, &item.Name, &item.Brand, &item.Quantity, &item.UnitOfQuantity, &item.ItemQuantity, &horizontal, &vertical, &bundle); err != nil {
 			return nil, fmt.Errorf("error scanning items: %w", err)
 		}
 
@@ -912,6 +951,10 @@ func (s *PostgresStore) PackOrder(storeId int, phoneNumber string) ([]PackedItem
 			*item.ShelfVertical = vertical.String
 		}
 
+		if bundle.Valid {
+			item.Bundle = &bundle.String
+		}
+
 		images, err := s.getItemImages(item.ItemID)
 		if err != nil {
 			return nil, err
@@ -927,10 +970,11 @@ func (s *PostgresStore) PackOrder(storeId int, phoneNumber string) ([]PackedItem
 func (s *PostgresStore) fetchPackedItems(orderId int) ([]PackedItem, error) {
 	itemsQuery := `
         SELECT i.id, i.name, b.name, i.quantity, i.unit_of_quantity, ci.quantity, 
-               s.horizontal, s.vertical
-        FROM cart_item ci
+               s.horizontal, s.vertical, bundle.name
+        FROM cart_pick_item ci
         JOIN item i ON ci.item_id = i.id
         JOIN brand b ON i.brand_id = b.id
+        LEFT JOIN item bundle ON ci.bundle_item_id = bundle.id
         LEFT JOIN shelf s ON i.id = s.item_id AND s.store_id = (
             SELECT store_id FROM sales_order WHERE id = $1
         )
@@ -947,7 +991,8 @@ func (s *PostgresStore) fetchPackedItems(orderId int) ([]PackedItem, error) {
 		var item PackedItem
 		var horizontal sql.NullInt64
 		var vertical sql.NullString
-		if err := rows.Scan(&item.ItemID, &item.Name, &item.Brand, &item.Quantity, &item.UnitOfQuantity, &item.ItemQuantity, &horizontal, &vertical); err != nil {
+		var bundle sql.NullString
+		if err := rows.Scan(&item.ItemID, &item.Name, &item.Brand, &item.Quantity, &item.UnitOfQuantity, &item.ItemQuantity, &horizontal, &vertical, &bundle); err != nil {
 			return nil, fmt.Errorf("error scanning items: %w", err)
 		}
 
@@ -962,6 +1007,10 @@ func (s *PostgresStore) fetchPackedItems(orderId int) ([]PackedItem, error) {
 			*item.ShelfVertical = vertical.String
 		}
 
+		if bundle.Valid {
+			item.Bundle = &bundle.String
+		}
+
 		images, err := s.getItemImages(item.ItemID)
 		if err != nil {
 			return nil, err
@@ -1007,6 +1056,7 @@ type PackedItem struct {
 	ShelfHorizontal *int     `json:"shelf_horizontal"` // Pointer to handle NULLs
 	ShelfVertical   *string  `json:"shelf_vertical"`   // Pointer to handle NULLs
 	ImageURLs       []string `json:"image_urls"`
+	Bundle          *string  `json:"bundle,omitempty"` // Bundle the item is picked for, if any
 }
 
 func (s *PostgresStore) PackerOrderAllocateSpace(req types.SpaceOrder) (AllocationInfo, error) {
@@ -1048,10 +1098,15 @@ func (s *PostgresStore) PackerOrderAllocateSpace(req types.SpaceOrder) (Allocati
 
 	// 5. Update the order status in sales_order
 	orderStatusQuery := `UPDATE sales_order SET order_status = 'packed' WHERE id = $1 AND (order_status = 'accepted' OR order_status = 'received')`
-	_, err = tx.Exec(orderStatusQuery, req.SalesOrderID)
+	result, err := tx.Exec(orderStatusQuery, req.SalesOrderID)
 	if err != nil {
 		return info, fmt.Errorf("error updating sales_order status: %w", err)
 	}
+	if packed, _ := result.RowsAffected(); packed > 0 {
+		if err = writeOrderOutboxEvent(tx, OrderEventPacked, req.SalesOrderID); err != nil {
+			return info, err
+		}
+	}
 
 	// Commit the transaction
 	err = tx.Commit()
@@ -1094,8 +1149,14 @@ func (s *PostgresStore) CancelPackOrder(storeId int, phoneNumber string, orderId
         WHERE id = $1 AND store_id = $2 AND packer_id = $3 AND order_status = 'accepted'
         RETURNING id;
     `
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var updatedOrderId int
-	err = s.db.QueryRow(updateQuery, orderId, storeId, packerId).Scan(&updatedOrderId)
+	err = tx.QueryRow(updateQuery, orderId, storeId, packerId).Scan(&updatedOrderId)
 	if err != nil {
 		// If no rows are affected, it might not necessarily be an error.
 		// It could be that no order met the criteria.
@@ -1107,6 +1168,12 @@ func (s *PostgresStore) CancelPackOrder(storeId int, phoneNumber string, orderId
 
 	// Check if the update was successful
 	if updatedOrderId == orderId {
+		if err = writeOrderOutboxEvent(tx, OrderEventPackingCancelled, orderId); err != nil {
+			return false, err
+		}
+		if err = tx.Commit(); err != nil {
+			return false, fmt.Errorf("error committing transaction: %w", err)
+		}
 		return true, nil // The order was successfully updated.
 	}
 
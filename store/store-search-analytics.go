@@ -0,0 +1,345 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+)
+
+const (
+	catalogKeyAutocomplete = "autocomplete:"
+
+	autocompleteDefaultLimit = 8
+	autocompleteMaxLimit     = 20
+	// autocompleteMinSearches keeps one-off queries, and the typos among them, out of the suggestions.
+	autocompleteMinSearches = 3
+	autocompleteMaxQueries  = 500
+
+	searchReportDefaultDays  = 30
+	searchReportDefaultLimit = 50
+	// A query is low-conversion once it has searchReportMinSearches searches with results but fewer
+	// than searchReportLowConversion of them led to an add-to-cart.
+	searchReportMinSearches   = 10
+	searchReportLowConversion = 0.05
+)
+
+// CreateSearchAnalyticsTables logs every search with its result count, and the clicks and
+// add-to-carts that followed it.
+func (s *PostgresStore) CreateSearchAnalyticsTables(tx *sql.Tx) error {
+	query := `
+    CREATE TABLE IF NOT EXISTS search_query (
+        id BIGSERIAL PRIMARY KEY,
+        query TEXT NOT NULL,
+        normalized_query TEXT NOT NULL,
+        customer_id INT REFERENCES customer(id) ON DELETE SET NULL,
+        store_id INT,
+        result_count INT NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS search_query_created_at ON search_query (created_at);
+    CREATE INDEX IF NOT EXISTS search_query_normalized ON search_query (normalized_query, created_at);
+
+    CREATE TABLE IF NOT EXISTS search_event (
+        search_id BIGINT NOT NULL REFERENCES search_query(id) ON DELETE CASCADE,
+        item_id INT NOT NULL REFERENCES item(id) ON DELETE CASCADE,
+        event TEXT NOT NULL CHECK (event IN ('click', 'add_to_cart')),
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (search_id, item_id, event)
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating search analytics tables: %w", err)
+	}
+	return nil
+}
+
+// logSearch records a search and returns its id. Logging never fails the search itself.
+func (s *PostgresStore) logSearch(search types.Search_Item, result *types.SearchResult) int64 {
+	var id int64
+	err := s.db.QueryRow(`
+        INSERT INTO search_query (query, normalized_query, customer_id, store_id, result_count)
+        VALUES ($1, $2, (SELECT id FROM customer WHERE phone = $3), NULLIF($4, 0), $5)
+        RETURNING id`,
+		search.Name, result.Query, search.PhoneAuth, search.Store_ID, result.Total).Scan(&id)
+	if err != nil {
+		log.Printf("error logging search %q: %v", result.Query, err)
+		return 0
+	}
+	return id
+}
+
+// RecordSearchEvent stores a click or add-to-cart on a search result. Repeated events are ignored,
+// and a search logged for a customer only accepts events from that customer.
+func (s *PostgresStore) RecordSearchEvent(event types.SearchEvent) error {
+	if event.Event != types.SearchEventClick && event.Event != types.SearchEventAddToCart {
+		return fmt.Errorf("invalid search event %q", event.Event)
+	}
+
+	res, err := s.db.Exec(`
+        INSERT INTO search_event (search_id, item_id, event)
+        SELECT q.id, $2, $3
+        FROM search_query q
+        WHERE q.id = $1
+          AND (q.customer_id IS NULL OR q.customer_id = (SELECT id FROM customer WHERE phone = $4))
+        ON CONFLICT (search_id, item_id, event) DO NOTHING`,
+		event.Search_ID, event.Item_ID, event.Event, event.PhoneAuth)
+	if err != nil {
+		return fmt.Errorf("error recording search event: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var exists bool
+		if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM search_event WHERE search_id = $1 AND item_id = $2 AND event = $3)`,
+			event.Search_ID, event.Item_ID, event.Event).Scan(&exists); err != nil {
+			return fmt.Errorf("error checking search event: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("search %d not found", event.Search_ID)
+		}
+	}
+	return nil
+}
+
+type autocompleteEntry struct {
+	suggestion types.SearchSuggestion
+	normalized string
+	weight     float64
+}
+
+// autocompleteEntries loads the suggestion candidates of a store: its items, the brands and categories
+// of those items, and queries that were searched repeatedly with results. Weights are completed orders,
+// or searches for queries. Item and category names are translated into langs before they are matched.
+// The list is cached like the catalog, so typing costs no database round trip.
+func (s *PostgresStore) autocompleteEntries(storeID int, langs []string) ([]autocompleteEntry, error) {
+	key := catalogKeyAutocomplete + strconv.Itoa(storeID) + catalogLanguageKey(langs)
+	if entry, ok := s.catalog.get(key); ok {
+		return entry.value.([]autocompleteEntry), nil
+	}
+	gen := s.catalog.generation()
+
+	query := `
+    WITH stocked AS (
+        SELECT DISTINCT item_id FROM item_store WHERE listed AND ($1::int = 0 OR store_id = $1::int)
+    ), popularity AS (
+        SELECT item_id, SUM(order_count) AS orders
+        FROM customer_item_frequency
+        WHERE $1::int = 0 OR store_id = $1::int
+        GROUP BY item_id
+    ), items AS (
+        SELECT i.id, i.name, i.brand_id, COALESCE(p.orders, 0) AS orders
+        FROM item i
+        JOIN stocked st ON st.item_id = i.id AND i.status = 'active'
+        LEFT JOIN popularity p ON p.item_id = i.id
+    )
+    SELECT 'item', id, name, orders::float8 FROM items
+    UNION ALL
+    SELECT 'brand', b.id, b.name, SUM(items.orders)::float8
+    FROM brand b
+    JOIN items ON items.brand_id = b.id
+    GROUP BY b.id
+    UNION ALL
+    SELECT 'category', c.id, c.name, SUM(items.orders)::float8
+    FROM category c
+    JOIN item_category ic ON ic.category_id = c.id
+    JOIN items ON items.id = ic.item_id
+    GROUP BY c.id
+    UNION ALL
+    (SELECT 'query', 0, normalized_query, COUNT(*)::float8
+     FROM search_query
+     WHERE result_count > 0 AND ($1::int = 0 OR store_id = $1::int)
+       AND created_at >= CURRENT_TIMESTAMP - $2 * INTERVAL '1 day'
+     GROUP BY normalized_query
+     HAVING COUNT(*) >= $3
+     ORDER BY COUNT(*) DESC
+     LIMIT $4)`
+
+	rows, err := s.reader(readCatalog).Query(query, storeID, searchReportDefaultDays, autocompleteMinSearches, autocompleteMaxQueries)
+	if err != nil {
+		return nil, fmt.Errorf("error querying autocomplete entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []autocompleteEntry{}
+	for rows.Next() {
+		var entry autocompleteEntry
+		if err := rows.Scan(&entry.suggestion.Kind, &entry.suggestion.ID, &entry.suggestion.Text, &entry.weight); err != nil {
+			return nil, fmt.Errorf("error scanning autocomplete entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	texts := []catalogText{}
+	for i := range entries {
+		suggestion := &entries[i].suggestion
+		switch suggestion.Kind {
+		case types.SearchSuggestionItem:
+			texts = append(texts, catalogText{types.TranslationEntityItem, suggestion.ID, "name", &suggestion.Text})
+		case types.SearchSuggestionCategory:
+			texts = append(texts, catalogText{types.TranslationEntityCategory, suggestion.ID, "name", &suggestion.Text})
+		}
+	}
+	if err := s.translate(langs, texts); err != nil {
+		return nil, err
+	}
+
+	matchable := entries[:0]
+	for _, entry := range entries {
+		entry.normalized = normalizeSearchText(entry.suggestion.Text)
+		if entry.normalized != "" {
+			matchable = append(matchable, entry)
+		}
+	}
+	entries = matchable
+
+	s.catalog.put(key, gen, &catalogEntry{value: entries, storeID: storeID})
+	return entries, nil
+}
+
+// Autocomplete suggests items, brands, categories and popular queries with a word starting with prefix.
+// Suggestions starting with the prefix come first, then the more ordered or searched ones.
+func (s *PostgresStore) Autocomplete(prefix string, storeID int, limit int, langs []string) ([]types.SearchSuggestion, error) {
+	if limit <= 0 {
+		limit = autocompleteDefaultLimit
+	}
+	if limit > autocompleteMaxLimit {
+		limit = autocompleteMaxLimit
+	}
+
+	suggestions := []types.SearchSuggestion{}
+	prefix = normalizeSearchText(prefix)
+	if prefix == "" {
+		return suggestions, nil
+	}
+
+	entries, err := s.autocompleteEntries(storeID, langs)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		entry  *autocompleteEntry
+		inWord bool
+	}
+	var matches []match
+	for i := range entries {
+		entry := &entries[i]
+		switch {
+		case strings.HasPrefix(entry.normalized, prefix):
+			matches = append(matches, match{entry, false})
+		case strings.Contains(entry.normalized, " "+prefix):
+			matches = append(matches, match{entry, true})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.inWord != b.inWord {
+			return !a.inWord
+		}
+		if a.entry.weight != b.entry.weight {
+			return a.entry.weight > b.entry.weight
+		}
+		return len(a.entry.normalized) < len(b.entry.normalized)
+	})
+
+	// a popular query and a category of the same name are one suggestion
+	seen := map[string]bool{}
+	for _, m := range matches {
+		if seen[m.entry.normalized] {
+			continue
+		}
+		seen[m.entry.normalized] = true
+		suggestions = append(suggestions, m.entry.suggestion)
+		if len(suggestions) == limit {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
+// searchQueryStatsQuery aggregates the searches of the last $1 days for store $2 (0 for all) per query.
+const searchQueryStatsQuery = `
+    WITH searches AS (
+        SELECT q.normalized_query, q.result_count,
+               EXISTS (SELECT 1 FROM search_event e WHERE e.search_id = q.id AND e.event = 'click') AS clicked,
+               EXISTS (SELECT 1 FROM search_event e WHERE e.search_id = q.id AND e.event = 'add_to_cart') AS added
+        FROM search_query q
+        WHERE q.created_at >= CURRENT_TIMESTAMP - $1 * INTERVAL '1 day'
+          AND ($2::int = 0 OR q.store_id = $2::int)
+          AND q.normalized_query <> ''
+    ), stats AS (
+        SELECT normalized_query AS query,
+               COUNT(*) AS searches,
+               AVG(result_count)::float8 AS avg_results,
+               COUNT(*) FILTER (WHERE result_count = 0) AS zero_results,
+               COUNT(*) FILTER (WHERE clicked) AS clicks,
+               COUNT(*) FILTER (WHERE added) AS add_to_cart
+        FROM searches
+        GROUP BY normalized_query
+    )
+    SELECT query, searches, avg_results, zero_results, clicks, add_to_cart,
+           add_to_cart::float8 / searches AS conversion
+    FROM stats`
+
+func querySearchStats(db *sql.DB, where string, order string, args ...interface{}) ([]types.SearchQueryStat, error) {
+	rows, err := db.Query(searchQueryStatsQuery+where+order+" LIMIT $3", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying search stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []types.SearchQueryStat{}
+	for rows.Next() {
+		var stat types.SearchQueryStat
+		if err := rows.Scan(&stat.Query, &stat.Searches, &stat.Avg_Results, &stat.Zero_Results, &stat.Clicks,
+			&stat.Add_To_Cart, &stat.Conversion); err != nil {
+			return nil, fmt.Errorf("error scanning search stat: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// SearchReport lists the top, zero-result and low-conversion queries of a period.
+func (s *PostgresStore) SearchReport(req types.SearchReportRequest) (*types.SearchReport, error) {
+	if req.Days <= 0 {
+		req.Days = searchReportDefaultDays
+	}
+	if req.Limit <= 0 {
+		req.Limit = searchReportDefaultLimit
+	}
+
+	report := &types.SearchReport{Store_ID: req.Store_ID, Days: req.Days}
+	db := s.reader(readExport)
+
+	var err error
+	report.Top_Queries, err = querySearchStats(db, ``, `
+    ORDER BY searches DESC, query`, req.Days, req.Store_ID, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Zero_Result_Queries, err = querySearchStats(db, `
+    WHERE zero_results > 0`, `
+    ORDER BY zero_results DESC, query`, req.Days, req.Store_ID, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Low_Conversion_Queries, err = querySearchStats(db, `
+    WHERE searches - zero_results >= $4 AND add_to_cart::float8 / searches < $5`, `
+    ORDER BY searches DESC, query`, req.Days, req.Store_ID, req.Limit, searchReportMinSearches, searchReportLowConversion)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
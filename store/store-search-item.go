@@ -1,123 +1,23 @@
 package store
 
 import (
-	"database/sql"
 	"fmt"
-	"strings"
 
 	"github.com/girithc/pronto-go/types"
 
 	"github.com/lib/pq"
 )
 
-func (s *PostgresStore) Search_Items(query string) ([]*types.Get_Items_By_CategoryID_And_StoreID_noCategory, error) {
+// Search_Items answers /search-item with the items of Search, without the facets, and the id of
+// the logged search (0 if it was not logged).
+func (s *PostgresStore) Search_Items(search types.Search_Item) ([]*types.Get_Items_By_CategoryID_And_StoreID_noCategory, int64, error) {
 	fmt.Println("Entered Search_Items")
 
-	lowerQuery := strings.ToLower(query) // Ensure the query is case-insensitive
-	fuzzyMatchQuery := "%" + lowerQuery + "%"
-
-	_, err := s.db.Exec("SELECT set_limit(0.15);") // Set a lower similarity threshold
+	result, err := s.Search(search)
 	if err != nil {
-		return nil, fmt.Errorf("error setting similarity threshold: %v", err)
+		return nil, 0, err
 	}
-
-	rows, err := s.db.Query(`
-    SELECT
-        item.id,
-        MAX(item.name) AS name,
-        MAX(item_financial.mrp_price) AS mrp_price,
-        MAX(item_store.discount) AS discount,
-        MAX(item_store.store_price) AS store_price,
-        MAX(store.name) AS store_name,
-        MAX(item_store.stock_quantity) AS stock_quantity,
-        MAX(item_store.locked_quantity) AS locked_quantity,
-        MAX((SELECT image_url FROM item_image WHERE item_image.item_id = item.id ORDER BY order_position LIMIT 1)) AS image_url,
-        MAX(brand.name) AS brand_name,
-        MAX(item.quantity) AS quantity,
-        MAX(item.unit_of_quantity) AS unit_of_quantity,
-        MAX(item.created_at) AS created_at,
-        MAX(item.created_by) AS created_by
-    FROM item
-    INNER JOIN item_store ON item.id = item_store.item_id
-    INNER JOIN item_financial ON item.id = item_financial.item_id
-    LEFT JOIN store ON item_store.store_id = store.id
-    LEFT JOIN brand ON item.brand_id = brand.id
-    LEFT JOIN item_category ON item.id = item_category.item_id
-    LEFT JOIN category ON item_category.category_id = category.id
-    WHERE lower(item.name) % $1 OR
-          lower(brand.name) % $1 OR
-          lower(item.description) % $1 OR
-          lower(category.name) % $1
-    GROUP BY item.id
-    ORDER BY MAX(similarity(lower(item.name), $1) * 0.4 + 
-                 similarity(lower(brand.name), $1) * 0.3 +
-                 similarity(lower(item.description), $1) * 0.2 +
-                 similarity(lower(category.name), $1) * 0.2) DESC, 
-             MAX(item_store.stock_quantity) DESC
-    `, fuzzyMatchQuery)
-
-	if err != nil {
-		return nil, fmt.Errorf("error executing search query: %v", err)
-	}
-	defer rows.Close()
-
-	var items []*types.Get_Items_By_CategoryID_And_StoreID_noCategory
-	for rows.Next() {
-		var item types.Get_Items_By_CategoryID_And_StoreID_noCategory
-		var imageURL sql.NullString
-		var createdBy sql.NullInt64
-		var mrpPrice sql.NullFloat64
-
-		err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&mrpPrice,
-			&item.Discount,
-			&item.Store_Price,
-			&item.Store,
-			&item.Stock_Quantity,
-			&item.Locked_Quantity,
-			&imageURL,
-			&item.Brand,
-			&item.Quantity,
-			&item.Unit_Of_Quantity,
-			&item.Created_At,
-			&createdBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning row: %v", err)
-		}
-
-		// Handle null values
-		if mrpPrice.Valid {
-			item.MRP_Price = mrpPrice.Float64
-		} else {
-			item.MRP_Price = 0.0
-		}
-
-		if imageURL.Valid {
-			item.Image = imageURL.String
-		} else {
-			item.Image = ""
-		}
-
-		if createdBy.Valid {
-			item.Created_By = int(createdBy.Int64)
-		} else {
-			item.Created_By = 0
-		}
-
-		item.Store_Price = item.MRP_Price - item.Discount
-
-		items = append(items, &item)
-	}
-
-	_, err = s.db.Exec("SELECT set_limit(0.3);") // Reset the similarity threshold
-	if err != nil {
-		return nil, fmt.Errorf("error resetting similarity threshold: %v", err)
-	}
-
-	return items, nil
+	return result.Items, result.Search_ID, nil
 }
 
 func (s *PostgresStore) ManagerSearchItem(name string) ([]ManagerSearchItem, error) {
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxSearchQueries bounds the synonym expansion of a query.
+const maxSearchQueries = 8
+
+var devanagariVowels = map[rune]string{
+	'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ee", 'उ': "u", 'ऊ': "oo", 'ऋ': "ri",
+	'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au", 'ऑ': "o",
+}
+
+var devanagariMatras = map[rune]string{
+	'ा': "aa", 'ि': "i", 'ी': "ee", 'ु': "u", 'ू': "oo", 'ृ': "ri",
+	'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au", 'ॉ': "o",
+}
+
+var devanagariConsonants = map[rune]string{
+	'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n",
+	'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "n",
+	'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n",
+	'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+	'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+	'य': "y", 'र': "r", 'ल': "l", 'व': "v",
+	'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+	'\u0958': "q", '\u0959': "kh", '\u095A': "g", '\u095B': "z", '\u095C': "r", '\u095D': "rh", '\u095E': "f", '\u095F': "y", // nukta forms
+}
+
+// devanagariNuktaForms maps a consonant to its precomposed nukta form, for text typed as the
+// consonant followed by a separate nukta.
+var devanagariNuktaForms = map[rune]rune{
+	'क': '\u0958', 'ख': '\u0959', 'ग': '\u095A', 'ज': '\u095B', 'ड': '\u095C', 'ढ': '\u095D', 'फ': '\u095E', 'य': '\u095F',
+}
+
+const (
+	devanagariVirama      = '्'
+	devanagariNukta       = '़'
+	devanagariAnusvara    = 'ं'
+	devanagariCandrabindu = 'ँ'
+	devanagariVisarga     = 'ः'
+)
+
+// transliterateDevanagari romanizes Hindi the way it is usually typed in Hinglish, e.g. "आटा" becomes
+// "aataa". The inherent vowel of a consonant is dropped at the end of a word, as it is in speech.
+// Other scripts pass through unchanged.
+func transliterateDevanagari(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if consonant, ok := devanagariConsonants[r]; ok {
+			next := i + 1
+			if next < len(runes) && runes[next] == devanagariNukta {
+				if nukta, ok := devanagariNuktaForms[r]; ok {
+					consonant = devanagariConsonants[nukta]
+				}
+				next++
+			}
+			b.WriteString(consonant)
+			if next >= len(runes) {
+				continue
+			}
+			if matra, ok := devanagariMatras[runes[next]]; ok {
+				b.WriteString(matra)
+				i = next
+				continue
+			}
+			if runes[next] == devanagariVirama {
+				i = next
+				continue
+			}
+			// the inherent vowel is silent at the end of a word
+			if isDevanagariLetter(runes[next]) {
+				b.WriteString("a")
+			}
+			i = next - 1
+			continue
+		}
+		switch {
+		case devanagariVowels[r] != "":
+			b.WriteString(devanagariVowels[r])
+		case devanagariMatras[r] != "":
+			b.WriteString(devanagariMatras[r])
+		case r == devanagariAnusvara || r == devanagariCandrabindu:
+			b.WriteString("n")
+		case r == devanagariVisarga:
+			b.WriteString("h")
+		case r == devanagariNukta || r == devanagariVirama:
+		case r >= '०' && r <= '९':
+			b.WriteRune('0' + (r - '०'))
+		case r == '।' || r == '॥':
+			b.WriteString(" ")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isDevanagariLetter(r rune) bool {
+	if _, ok := devanagariConsonants[r]; ok {
+		return true
+	}
+	if _, ok := devanagariVowels[r]; ok {
+		return true
+	}
+	return r == devanagariAnusvara || r == devanagariCandrabindu || r == devanagariVisarga
+}
+
+// normalizeSearchText transliterates, lowercases and reduces text to words separated by single spaces.
+func normalizeSearchText(text string) string {
+	text = strings.ToLower(transliterateDevanagari(text))
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// expandSearchQuery returns the normalized query followed by its synonym variants: every term of a
+// synonym group found in the query as whole words is replaced by each other term of the group.
+func expandSearchQuery(query string, synonyms []*searchSynonymGroup) []string {
+	queries := []string{query}
+	seen := map[string]bool{query: true}
+
+	padded := " " + query + " "
+	for _, group := range synonyms {
+		for _, term := range group.terms {
+			if !strings.Contains(padded, " "+term+" ") {
+				continue
+			}
+			for _, other := range group.terms {
+				if other == term {
+					continue
+				}
+				variant := strings.TrimSpace(strings.ReplaceAll(padded, " "+term+" ", " "+other+" "))
+				if !seen[variant] && len(queries) < maxSearchQueries {
+					seen[variant] = true
+					queries = append(queries, variant)
+				}
+			}
+		}
+	}
+	return queries
+}
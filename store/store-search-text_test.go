@@ -0,0 +1,81 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransliterateDevanagari(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"आटा", "aataa"},
+		{"दाल", "daal"},
+		{"चावल", "chaaval"},
+		{"नमक", "namak"},
+		{"घी", "ghee"},
+		{"दूध", "doodh"},
+		{"मसाला", "masaalaa"},
+		{"सब्ज़ी", "sabzee"},     // consonant followed by a nukta
+		{"सब्\u095Bी", "sabzee"}, // precomposed nukta form
+		{"२५०", "250"},
+		{"amul आटा", "amul aataa"},
+		{"bread", "bread"},
+	}
+	for _, tt := range tests {
+		if got := transliterateDevanagari(tt.text); got != tt.want {
+			t.Errorf("transliterateDevanagari(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"  Amul   Butter ", "amul butter"},
+		{"Coca-Cola (500ml)", "coca cola 500ml"},
+		{"दाल, चावल", "daal chaaval"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeSearchText(tt.text); got != tt.want {
+			t.Errorf("normalizeSearchText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExpandSearchQuery(t *testing.T) {
+	synonyms := []*searchSynonymGroup{
+		{id: 1, terms: []string{"atta", "aataa", "flour"}},
+		{id: 2, terms: []string{"curd", "dahi"}},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"rice", []string{"rice"}},
+		{"atta", []string{"atta", "aataa", "flour"}},
+		{"whole wheat atta", []string{"whole wheat atta", "whole wheat aataa", "whole wheat flour"}},
+		{"attachment", []string{"attachment"}},
+		{"curd", []string{"curd", "dahi"}},
+		{"atta curd", []string{"atta curd", "aataa curd", "flour curd", "atta dahi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := expandSearchQuery(tt.query, synonyms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandSearchQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandSearchQueryLimit(t *testing.T) {
+	group := &searchSynonymGroup{id: 1, terms: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}}
+	got := expandSearchQuery("a", []*searchSynonymGroup{group})
+	if len(got) != maxSearchQueries {
+		t.Errorf("got %d queries, want %d", len(got), maxSearchQueries)
+	}
+}
@@ -0,0 +1,515 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+const (
+	catalogKeySearchSynonyms = "search-synonyms"
+
+	// searchWordSimilarity is the trigram threshold for typos, applied per search transaction.
+	searchWordSimilarity = 0.4
+	searchDefaultLimit   = 50
+	searchMaxLimit       = 200
+)
+
+// CreateSearchTables creates item_search, one denormalized search row per item. Triggers on item,
+// item_category, brand and category keep it current. search_key holds search_key(search_text), which
+// folds common Hinglish spelling variants ("aata", "atta") onto one form.
+func (s *PostgresStore) CreateSearchTables(tx *sql.Tx) error {
+	query := `
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+    CREATE OR REPLACE FUNCTION search_key(t TEXT) RETURNS TEXT AS $$
+        SELECT regexp_replace(
+                 regexp_replace(
+                   translate(
+                     replace(replace(replace(replace(replace(lower(t), 'ph', 'f'), 'sh', 's'), 'ee', 'i'), 'oo', 'u'), 'ck', 'k'),
+                     'wzq', 'vjk'),
+                   '([bcdgjkpt])h', '\1', 'g'),
+                 '([a-z])\1+', '\1', 'g')
+    $$ LANGUAGE SQL IMMUTABLE;
+
+    CREATE TABLE IF NOT EXISTS item_search (
+        item_id INT PRIMARY KEY REFERENCES item(id) ON DELETE CASCADE,
+        search_text TEXT NOT NULL,
+        search_key TEXT NOT NULL,
+        document TSVECTOR NOT NULL,
+        updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS item_search_document ON item_search USING GIN (document);
+    CREATE INDEX IF NOT EXISTS item_search_text_trgm ON item_search USING GIN (search_text gin_trgm_ops);
+    CREATE INDEX IF NOT EXISTS item_search_key_trgm ON item_search USING GIN (search_key gin_trgm_ops);
+
+    CREATE OR REPLACE FUNCTION refresh_item_search(ids INT[]) RETURNS VOID AS $$
+        INSERT INTO item_search (item_id, search_text, search_key, document, updated_at)
+        SELECT i.id, d.search_text, search_key(d.search_text),
+               setweight(to_tsvector('simple', i.name), 'A') ||
+               setweight(to_tsvector('simple', COALESCE(b.name, '')), 'B') ||
+               setweight(to_tsvector('simple', search_key(concat_ws(' ', i.name, b.name))), 'B') ||
+               setweight(to_tsvector('simple', COALESCE(c.names, '')), 'C') ||
+               setweight(to_tsvector('simple', COALESCE(i.description, '')), 'D'),
+               CURRENT_TIMESTAMP
+        FROM item i
+        LEFT JOIN brand b ON b.id = i.brand_id
+        LEFT JOIN LATERAL (
+            SELECT string_agg(cat.name, ' ') AS names
+            FROM item_category ic
+            JOIN category cat ON cat.id = ic.category_id
+            WHERE ic.item_id = i.id
+        ) c ON TRUE
+        CROSS JOIN LATERAL (SELECT lower(concat_ws(' ', i.name, b.name, c.names)) AS search_text) d
+        WHERE i.id = ANY(ids)
+        ON CONFLICT (item_id) DO UPDATE
+        SET search_text = EXCLUDED.search_text, search_key = EXCLUDED.search_key,
+            document = EXCLUDED.document, updated_at = EXCLUDED.updated_at;
+    $$ LANGUAGE SQL;
+
+    CREATE OR REPLACE FUNCTION item_search_refresh() RETURNS TRIGGER AS $$
+    BEGIN
+        IF TG_TABLE_NAME = 'item' THEN
+            PERFORM refresh_item_search(ARRAY[NEW.id]);
+        ELSIF TG_TABLE_NAME = 'item_category' THEN
+            IF TG_OP = 'DELETE' THEN
+                PERFORM refresh_item_search(ARRAY[OLD.item_id]);
+                RETURN OLD;
+            END IF;
+            PERFORM refresh_item_search(ARRAY[NEW.item_id]);
+        ELSIF TG_TABLE_NAME = 'brand' THEN
+            PERFORM refresh_item_search(ARRAY(SELECT id FROM item WHERE brand_id = NEW.id));
+        ELSIF TG_TABLE_NAME = 'category' THEN
+            PERFORM refresh_item_search(ARRAY(SELECT item_id FROM item_category WHERE category_id = NEW.id));
+        END IF;
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS item_search_item ON item;
+    CREATE TRIGGER item_search_item AFTER INSERT OR UPDATE OF name, description, brand_id ON item
+    FOR EACH ROW EXECUTE FUNCTION item_search_refresh();
+
+    DROP TRIGGER IF EXISTS item_search_item_category ON item_category;
+    CREATE TRIGGER item_search_item_category AFTER INSERT OR DELETE ON item_category
+    FOR EACH ROW EXECUTE FUNCTION item_search_refresh();
+
+    DROP TRIGGER IF EXISTS item_search_brand ON brand;
+    CREATE TRIGGER item_search_brand AFTER UPDATE OF name ON brand
+    FOR EACH ROW EXECUTE FUNCTION item_search_refresh();
+
+    DROP TRIGGER IF EXISTS item_search_category ON category;
+    CREATE TRIGGER item_search_category AFTER UPDATE OF name ON category
+    FOR EACH ROW EXECUTE FUNCTION item_search_refresh();
+
+    SELECT refresh_item_search(ARRAY(SELECT id FROM item WHERE id NOT IN (SELECT item_id FROM item_search)));
+
+    CREATE TABLE IF NOT EXISTS search_synonym (
+        id SERIAL PRIMARY KEY,
+        terms TEXT[] NOT NULL CHECK (cardinality(terms) >= 2),
+        created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating search tables: %w", err)
+	}
+	return nil
+}
+
+type searchSynonymGroup struct {
+	id    int
+	terms []string
+}
+
+func (s *PostgresStore) searchSynonyms() ([]*searchSynonymGroup, error) {
+	if entry, ok := s.catalog.get(catalogKeySearchSynonyms); ok {
+		return entry.value.([]*searchSynonymGroup), nil
+	}
+	gen := s.catalog.generation()
+
+	rows, err := s.reader(readCatalog).Query(`SELECT id, terms FROM search_synonym ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying search synonyms: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*searchSynonymGroup{}
+	for rows.Next() {
+		group := &searchSynonymGroup{}
+		if err := rows.Scan(&group.id, pq.Array(&group.terms)); err != nil {
+			return nil, fmt.Errorf("error scanning search synonym: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	s.catalog.put(catalogKeySearchSynonyms, gen, &catalogEntry{value: groups})
+	return groups, nil
+}
+
+func (s *PostgresStore) GetSearchSynonyms() ([]types.SearchSynonym, error) {
+	groups, err := s.searchSynonyms()
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms := make([]types.SearchSynonym, 0, len(groups))
+	for _, group := range groups {
+		synonyms = append(synonyms, types.SearchSynonym{ID: group.id, Terms: group.terms})
+	}
+	return synonyms, nil
+}
+
+// SetSearchSynonym creates a synonym group, or replaces the terms of group ID when it is set.
+// Terms are normalized like queries, so Devanagari terms are stored transliterated.
+func (s *PostgresStore) SetSearchSynonym(req types.SearchSynonym) (*types.SearchSynonym, error) {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, term := range req.Terms {
+		term = normalizeSearchText(term)
+		if term != "" && !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) < 2 {
+		return nil, fmt.Errorf("a synonym group needs at least two distinct terms")
+	}
+
+	synonym := &types.SearchSynonym{ID: req.ID, Terms: terms}
+	if req.ID == 0 {
+		if err := s.db.QueryRow(`INSERT INTO search_synonym (terms) VALUES ($1) RETURNING id`, pq.Array(terms)).Scan(&synonym.ID); err != nil {
+			return nil, fmt.Errorf("error creating search synonym: %w", err)
+		}
+	} else {
+		res, err := s.db.Exec(`UPDATE search_synonym SET terms = $2 WHERE id = $1`, req.ID, pq.Array(terms))
+		if err != nil {
+			return nil, fmt.Errorf("error updating search synonym %d: %w", req.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("search synonym %d not found", req.ID)
+		}
+	}
+
+	s.catalog.invalidate(catalogKeySearchSynonyms)
+	return synonym, nil
+}
+
+func (s *PostgresStore) DeleteSearchSynonym(id int) error {
+	res, err := s.db.Exec(`DELETE FROM search_synonym WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting search synonym %d: %w", id, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("search synonym %d not found", id)
+	}
+
+	s.catalog.invalidate(catalogKeySearchSynonyms)
+	return nil
+}
+
+// searchBaseQuery yields one row per matching item with a flag per facet filter, so the item and
+// facet queries can leave out the filter of the facet they count. An item is matched by full-text on
+// any query variant, or by trigram similarity of a variant or its search_key for misspellings. The
+// score favours full-text matches and is boosted by the item's completed orders in the store.
+//
+// $1 query variants, $2 store, $3 category, $4 brands, $5/$6 price bounds, $7 in stock only,
+// $8 veg mark, $9 excluded allergens, $10 country of origin.
+const searchBaseQuery = `
+    WITH RECURSIVE category_scope AS (
+        SELECT id FROM category WHERE id = $3::int
+        UNION
+        SELECT c.id FROM category c JOIN category_scope cs ON c.parent_id = cs.id
+    ), alt AS (
+        SELECT a FROM unnest($1::text[]) a
+        UNION
+        SELECT search_key(a) FROM unnest($1::text[]) a
+    ), q AS (
+        SELECT string_agg('(' || plainto_tsquery('simple', a)::text || ')', ' | ')::tsquery AS tsq
+        FROM alt
+        WHERE plainto_tsquery('simple', a)::text <> ''
+    ), matches AS (
+        SELECT si.item_id,
+               COALESCE(ts_rank_cd(si.document, q.tsq), 0) AS text_rank,
+               (SELECT MAX(GREATEST(word_similarity(alt.a, si.search_text), word_similarity(alt.a, si.search_key))) FROM alt) AS fuzzy_rank
+        FROM item_search si
+        CROSS JOIN q
+        WHERE si.document @@ q.tsq
+           OR EXISTS (SELECT 1 FROM alt WHERE alt.a <% si.search_text OR alt.a <% si.search_key)
+    ), popularity AS (
+        SELECT item_id, SUM(order_count) AS orders
+        FROM customer_item_frequency
+        WHERE $2::int = 0 OR store_id = $2::int
+        GROUP BY item_id
+    ), offers AS (
+        SELECT DISTINCT ON (m.item_id)
+               m.item_id, istore.store_id, COALESCE(st.name, '') AS store_name,
+               COALESCE(ifin.mrp_price, 0) AS mrp_price, istore.discount,
+               COALESCE(store_selling_price(ifin.mrp_price, istore.discount, istore.price_override), istore.store_price) AS price,
+               istore.stock_quantity, istore.locked_quantity,
+               (m.text_rank * 2 + m.fuzzy_rank) * (1 + 0.1 * ln(1 + COALESCE(pop.orders, 0))) AS score
+        FROM matches m
+        JOIN item_store istore ON istore.item_id = m.item_id AND istore.listed AND ($2::int = 0 OR istore.store_id = $2::int)
+        JOIN item_financial ifin ON ifin.item_id = m.item_id
+        LEFT JOIN store st ON st.id = istore.store_id
+        LEFT JOIN popularity pop ON pop.item_id = m.item_id
+        ORDER BY m.item_id, istore.stock_quantity DESC
+    ), base AS (
+        SELECT o.*, i.name, i.brand_id, COALESCE(b.name, '') AS brand_name,
+               i.quantity, COALESCE(i.unit_of_quantity::text, '') AS unit_of_quantity, i.created_at, i.created_by,
+               (SELECT image_url FROM item_image WHERE item_image.item_id = o.item_id ORDER BY order_position LIMIT 1) AS image_url,
+               COALESCE(ia.veg_mark, '') AS veg_mark,
+               COALESCE(i.product_group_id, 0) AS product_group_id,
+               COALESCE(pg.default_item_id, 0) AS default_item_id,
+               (cardinality($4::int[]) = 0 OR i.brand_id = ANY($4::int[])) AS brand_ok,
+               ($3::int = 0 OR EXISTS (
+                   SELECT 1 FROM item_category ic
+                   WHERE ic.item_id = o.item_id AND ic.category_id IN (SELECT id FROM category_scope)
+               )) AS category_ok,
+               (($5::numeric <= 0 OR o.price >= $5::numeric) AND ($6::numeric <= 0 OR o.price <= $6::numeric)) AS price_ok,
+               (NOT $7::boolean OR o.stock_quantity > 0) AS stock_ok
+        FROM offers o
+        JOIN item i ON i.id = o.item_id AND i.status = 'active'
+        LEFT JOIN brand b ON b.id = i.brand_id
+        LEFT JOIN item_attribute ia ON ia.item_id = o.item_id
+        LEFT JOIN product_group pg ON pg.id = i.product_group_id
+        WHERE ($8::text = '' OR ia.veg_mark = $8::text)
+          AND (cardinality($9::text[]) = 0 OR (ia.item_id IS NOT NULL AND NOT ia.allergens && $9::text[]))
+          AND ($10::text = '' OR ia.country_of_origin = $10::text)
+    )`
+
+const searchItemsQuery = searchBaseQuery + `
+    SELECT item_id, name, mrp_price, discount, COALESCE(price, 0), store_name, stock_quantity, locked_quantity, image_url,
+           brand_name, quantity, unit_of_quantity, created_at, created_by, veg_mark, product_group_id,
+           default_item_id, COUNT(*) OVER ()
+    FROM base
+    WHERE brand_ok AND category_ok AND price_ok AND stock_ok
+    ORDER BY score DESC, stock_quantity > 0 DESC, item_id
+    LIMIT $11`
+
+// searchFacetsQuery returns (facet, key, label, count) rows ordered for display: brands and
+// categories by count, price ranges by bound.
+const searchFacetsQuery = searchBaseQuery + `
+    , price_range (lo, hi) AS (
+        VALUES (0, 50), (50, 100), (100, 200), (200, 500), (500, NULL)
+    )
+    SELECT facet, key, label, count FROM (
+        SELECT 'brand' AS facet, COALESCE(brand_id, 0)::text AS key, MAX(brand_name) AS label, COUNT(*) AS count, -COUNT(*) AS ord
+        FROM base
+        WHERE category_ok AND price_ok AND stock_ok
+        GROUP BY brand_id
+        UNION ALL
+        SELECT 'category', cat.id::text, MAX(cat.name), COUNT(DISTINCT base.item_id), -COUNT(DISTINCT base.item_id)
+        FROM base
+        JOIN item_category ic ON ic.item_id = base.item_id
+        JOIN category cat ON cat.id = ic.category_id
+        WHERE brand_ok AND price_ok AND stock_ok
+        GROUP BY cat.id
+        UNION ALL
+        SELECT 'price', pr.lo || COALESCE('-' || pr.hi, '+'), pr.lo || COALESCE('-' || pr.hi, '+'), COUNT(*), pr.lo
+        FROM base
+        JOIN price_range pr ON base.price >= pr.lo AND (pr.hi IS NULL OR base.price < pr.hi)
+        WHERE brand_ok AND category_ok AND stock_ok
+        GROUP BY pr.lo, pr.hi
+        UNION ALL
+        SELECT 'in_stock', '', '', COUNT(*), 0
+        FROM base
+        WHERE brand_ok AND category_ok AND price_ok AND stock_quantity > 0
+    ) facets
+    ORDER BY facet, ord, label`
+
+// Search runs a catalog search. Both queries run in one read-only transaction, so the trigram
+// threshold is set for this search only and facets count the same snapshot as the items.
+func (s *PostgresStore) Search(search types.Search_Item) (*types.SearchResult, error) {
+	synonyms, err := s.searchSynonyms()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.SearchResult{
+		Query: normalizeSearchText(search.Name),
+		Items: []*types.Get_Items_By_CategoryID_And_StoreID_noCategory{},
+		Facets: types.SearchFacets{
+			Brands:     []types.SearchFacetValue{},
+			Categories: []types.SearchFacetValue{},
+			Prices:     []types.SearchFacetValue{},
+		},
+	}
+	if result.Query == "" {
+		result.Queries = []string{}
+		return result, nil
+	}
+	result.Queries = expandSearchQuery(result.Query, synonyms)
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+
+	excludeAllergens := []string{}
+	for _, a := range search.Exclude_Allergens {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+			excludeAllergens = append(excludeAllergens, a)
+		}
+	}
+	brandIDs := search.Brand_IDs
+	if brandIDs == nil {
+		brandIDs = []int{}
+	}
+
+	args := []interface{}{
+		pq.Array(result.Queries),
+		search.Store_ID,
+		search.Category_ID,
+		pq.Array(brandIDs),
+		search.Min_Price,
+		search.Max_Price,
+		search.In_Stock,
+		strings.ToLower(strings.TrimSpace(search.Veg_Mark)),
+		pq.Array(excludeAllergens),
+		strings.ToUpper(strings.TrimSpace(search.Country_Of_Origin)),
+	}
+
+	tx, err := s.reader(readCatalog).BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL pg_trgm.word_similarity_threshold = %g", searchWordSimilarity)); err != nil {
+		return nil, fmt.Errorf("error setting similarity threshold: %w", err)
+	}
+
+	items, ids, groupIDs, defaultIDs, total, err := scanSearchItems(tx, append(args, limit))
+	if err != nil {
+		return nil, err
+	}
+	result.Total = total
+	result.Items = collapseVariantCards(items, ids, groupIDs, defaultIDs)
+
+	if err := scanSearchFacets(tx, args, &result.Facets); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.Search_ID = s.logSearch(search, result)
+	if err := s.translateSearchResult(search.Languages, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func scanSearchItems(tx *sql.Tx, args []interface{}) (items []*types.Get_Items_By_CategoryID_And_StoreID_noCategory, ids, groupIDs, defaultIDs []int, total int, err error) {
+	rows, err := tx.Query(searchItemsQuery, args...)
+	if err != nil {
+		return nil, nil, nil, nil, 0, fmt.Errorf("error executing search query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item types.Get_Items_By_CategoryID_And_StoreID_noCategory
+		var defaultID int
+		var imageURL sql.NullString
+		var createdBy sql.NullInt64
+
+		err := rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.MRP_Price,
+			&item.Discount,
+			&item.Store_Price,
+			&item.Store,
+			&item.Stock_Quantity,
+			&item.Locked_Quantity,
+			&imageURL,
+			&item.Brand,
+			&item.Quantity,
+			&item.Unit_Of_Quantity,
+			&item.Created_At,
+			&createdBy,
+			&item.Veg_Mark,
+			&item.Product_Group_ID,
+			&defaultID,
+			&total,
+		)
+		if err != nil {
+			return nil, nil, nil, nil, 0, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		item.Image = imageURL.String
+		item.Created_By = int(createdBy.Int64)
+
+		items = append(items, &item)
+		ids = append(ids, item.ID)
+		groupIDs = append(groupIDs, item.Product_Group_ID)
+		defaultIDs = append(defaultIDs, defaultID)
+	}
+	return items, ids, groupIDs, defaultIDs, total, rows.Err()
+}
+
+func scanSearchFacets(tx *sql.Tx, args []interface{}, facets *types.SearchFacets) error {
+	rows, err := tx.Query(searchFacetsQuery, args...)
+	if err != nil {
+		return fmt.Errorf("error executing search facets query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var facet string
+		var value types.SearchFacetValue
+		if err := rows.Scan(&facet, &value.Key, &value.Label, &value.Count); err != nil {
+			return fmt.Errorf("error scanning search facet: %w", err)
+		}
+		switch facet {
+		case "brand":
+			facets.Brands = append(facets.Brands, value)
+		case "category":
+			facets.Categories = append(facets.Categories, value)
+		case "price":
+			facets.Prices = append(facets.Prices, value)
+		case "in_stock":
+			facets.In_Stock = value.Count
+		}
+	}
+	return rows.Err()
+}
+
+// collapseVariantCards folds matching sizes of one product into one card at the rank of the best match.
+func collapseVariantCards(items []*types.Get_Items_By_CategoryID_And_StoreID_noCategory, ids, groupIDs, defaultIDs []int) []*types.Get_Items_By_CategoryID_And_StoreID_noCategory {
+	cards := []*types.Get_Items_By_CategoryID_And_StoreID_noCategory{}
+	for _, card := range variantCards(ids, groupIDs, defaultIDs) {
+		item := items[card.shown]
+		for _, v := range card.variants {
+			variant := items[v]
+			item.Variants = append(item.Variants, types.ItemVariant{
+				ItemID:           variant.ID,
+				Name:             variant.Name,
+				Quantity:         variant.Quantity,
+				Unit_Of_Quantity: variant.Unit_Of_Quantity,
+				MRP_Price:        variant.MRP_Price,
+				Discount:         variant.Discount,
+				Store_Price:      variant.Store_Price,
+				Stock_Quantity:   variant.Stock_Quantity,
+				Locked_Quantity:  variant.Locked_Quantity,
+				Image:            variant.Image,
+				Default:          variant.ID == defaultIDs[v],
+			})
+		}
+		sortVariants(item.Variants)
+		cards = append(cards, item)
+	}
+	return cards
+}
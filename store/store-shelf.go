@@ -156,8 +156,8 @@ func (s *PostgresStore) ManagerAssignItemToShelf(req types.AssignItemShelf) (*Sh
 	shelfID := fmt.Sprintf("%s%d", req.Vertical, req.Horizontal)
 
 	// Retrieve the name of the item to be assigned
-	getItemNameQuery := `SELECT name FROM Item WHERE barcode = $1;`
-	err = tx.QueryRow(getItemNameQuery, req.ItemBarcode).Scan(&newItemName)
+	getItemNameQuery := `SELECT name FROM Item WHERE id = (SELECT item_id FROM item_barcode WHERE gtin = $1);`
+	err = tx.QueryRow(getItemNameQuery, gtinKey(req.ItemBarcode)).Scan(&newItemName)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving item name: %v", err)
 	}
@@ -166,9 +166,9 @@ func (s *PostgresStore) ManagerAssignItemToShelf(req types.AssignItemShelf) (*Sh
 	removeAssignmentsQuery := `
     UPDATE Shelf
     SET item_id = NULL
-    WHERE item_id IN (SELECT id FROM Item WHERE barcode = $1) AND store_id = $2;
+    WHERE item_id IN (SELECT item_id FROM item_barcode WHERE gtin = $1) AND store_id = $2;
     `
-	_, err = tx.Exec(removeAssignmentsQuery, req.ItemBarcode, req.StoreID)
+	_, err = tx.Exec(removeAssignmentsQuery, gtinKey(req.ItemBarcode), req.StoreID)
 	if err != nil {
 		return nil, fmt.Errorf("error removing existing assignments: %v", err)
 	}
@@ -192,10 +192,10 @@ func (s *PostgresStore) ManagerAssignItemToShelf(req types.AssignItemShelf) (*Sh
 	// Assign the item to the new shelf
 	assignItemQuery := `
     UPDATE Shelf
-    SET item_id = (SELECT id FROM Item WHERE barcode = $1)
+    SET item_id = (SELECT item_id FROM item_barcode WHERE gtin = $1)
     WHERE store_id = $2 AND horizontal = $3 AND vertical = $4;
     `
-	_, err = tx.Exec(assignItemQuery, req.ItemBarcode, req.StoreID, req.Horizontal, req.Vertical)
+	_, err = tx.Exec(assignItemQuery, gtinKey(req.ItemBarcode), req.StoreID, req.Horizontal, req.Vertical)
 	if err != nil {
 		return nil, fmt.Errorf("error assigning item to new shelf: %v", err)
 	}
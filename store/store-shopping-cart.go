@@ -870,19 +870,16 @@ func (s *PostgresStore) ApplyPromo(promo string, cartId int) error {
 	return nil
 }
 
-func (s *PostgresStore) ResetPrices() error {
-	// Define the SQL query to update store prices
-	queryUpdatePrices := `
-        UPDATE item_store
-        SET store_price = item_financial.mrp_price
-        FROM item_financial
-        WHERE item_store.item_id = item_financial.item_id AND store_id = 1;
-    `
+// ResetPrices sells every item of storeID at its MRP, or of every store when storeID is 0. The
+// store_price follows from the cleared discount and override.
+func (s *PostgresStore) ResetPrices(storeID int) error {
+	defer s.catalog.invalidateAllItems()
 
-	// Define the SQL query to reset discounts
-	queryResetDiscounts := `
+	// Define the SQL query to reset discounts and overrides
+	queryResetPrices := `
         UPDATE item_store
-        SET discount = 0;
+        SET discount = 0, price_override = NULL
+        WHERE $1 = 0 OR store_id = $1;
     `
 
 	// Start a transaction
@@ -891,18 +888,16 @@ func (s *PostgresStore) ResetPrices() error {
 		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
 
-	// Execute the price update query
-	_, err = tx.Exec(queryUpdatePrices)
-	if err != nil {
+	if err := setPriceSource(tx, "reset"); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to reset prices: %v", err)
+		return err
 	}
 
-	// Execute the discount reset query
-	_, err = tx.Exec(queryResetDiscounts)
+	// Execute the price reset query
+	_, err = tx.Exec(queryResetPrices, storeID)
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("failed to reset discounts: %v", err)
+		return fmt.Errorf("failed to reset prices: %v", err)
 	}
 
 	// Commit the transaction
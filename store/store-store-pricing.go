@@ -0,0 +1,317 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+// CreateStorePricing adds the per-store assortment and price override to item_store and defines
+// the one selling price every reader uses: store_selling_price(mrp, discount, override) is the
+// override when set and mrp - discount otherwise, rounded and kept between 0 and the MRP. The
+// store_item_price view applies it to every item_store row, and triggers keep
+// item_store.store_price equal to it when the MRP, discount or override changes.
+func (s *PostgresStore) CreateStorePricing(tx *sql.Tx) error {
+	query := `
+    ALTER TABLE item_store
+        ADD COLUMN IF NOT EXISTS listed BOOLEAN NOT NULL DEFAULT TRUE,
+        ADD COLUMN IF NOT EXISTS price_override DECIMAL(10, 2) CHECK (price_override >= 0);
+
+    CREATE OR REPLACE FUNCTION store_selling_price(p_mrp NUMERIC, p_discount NUMERIC, p_override NUMERIC)
+    RETURNS NUMERIC AS $$
+        SELECT ROUND(CASE
+            WHEN p_mrp IS NULL THEN p_override
+            ELSE GREATEST(LEAST(COALESCE(p_override, p_mrp - COALESCE(p_discount, 0)), p_mrp), 0)
+        END)
+    $$ LANGUAGE SQL IMMUTABLE;
+
+    CREATE OR REPLACE VIEW store_item_price AS
+    SELECT istore.item_id, istore.store_id, istore.listed,
+           ifin.mrp_price, istore.discount, istore.price_override,
+           store_selling_price(ifin.mrp_price, istore.discount, istore.price_override) AS price
+    FROM item_store istore
+    LEFT JOIN item_financial ifin ON ifin.item_id = istore.item_id;
+
+    CREATE OR REPLACE FUNCTION item_store_sync_price() RETURNS TRIGGER AS $$
+    BEGIN
+        NEW.store_price := COALESCE(store_selling_price(
+            (SELECT mrp_price FROM item_financial WHERE item_id = NEW.item_id),
+            NEW.discount, NEW.price_override), NEW.store_price);
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS item_store_sync_price ON item_store;
+    CREATE TRIGGER item_store_sync_price
+        BEFORE INSERT OR UPDATE OF item_id, discount, price_override, store_price ON item_store
+        FOR EACH ROW EXECUTE FUNCTION item_store_sync_price();
+
+    CREATE OR REPLACE FUNCTION item_financial_sync_store_price() RETURNS TRIGGER AS $$
+    BEGIN
+        UPDATE item_store
+        SET store_price = store_selling_price(NEW.mrp_price, discount, price_override)
+        WHERE item_id = NEW.item_id
+          AND store_price IS DISTINCT FROM store_selling_price(NEW.mrp_price, discount, price_override);
+        RETURN NEW;
+    END;
+    $$ LANGUAGE plpgsql;
+
+    DROP TRIGGER IF EXISTS item_financial_sync_store_price ON item_financial;
+    CREATE TRIGGER item_financial_sync_store_price
+        AFTER INSERT OR UPDATE OF mrp_price ON item_financial
+        FOR EACH ROW EXECUTE FUNCTION item_financial_sync_store_price();`
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("error creating store pricing: %w", err)
+	}
+
+	// bring store prices written before the triggers existed in line with what carts charge
+	if _, err := tx.Exec(`SELECT set_config('pronto.price_source', 'initial', true)`); err != nil {
+		return fmt.Errorf("error tagging price source: %w", err)
+	}
+	_, err := tx.Exec(`
+    UPDATE item_store istore
+    SET store_price = store_selling_price(ifin.mrp_price, istore.discount, istore.price_override)
+    FROM item_financial ifin
+    WHERE ifin.item_id = istore.item_id
+      AND istore.store_price IS DISTINCT FROM store_selling_price(ifin.mrp_price, istore.discount, istore.price_override)`)
+	if err != nil {
+		return fmt.Errorf("error syncing store prices: %w", err)
+	}
+	if _, err := tx.Exec(`SELECT set_config('pronto.price_source', '', true)`); err != nil {
+		return fmt.Errorf("error tagging price source: %w", err)
+	}
+	return nil
+}
+
+// cartItemPriceSQL is the selling price of the item in itemParam at the store of the cart in
+// cartParam, for use as a sold_price subquery.
+func cartItemPriceSQL(cartParam, itemParam string) string {
+	return fmt.Sprintf(`(SELECT p.price FROM store_item_price p JOIN shopping_cart sc ON sc.store_id = p.store_id
+        WHERE sc.id = %s AND p.item_id = %s)`, cartParam, itemParam)
+}
+
+// invalidateStoreAssortment drops the cached listings and autocomplete entries of storeID after its
+// assortment or prices change.
+func (s *PostgresStore) invalidateStoreAssortment(storeID int, itemIDs ...int) {
+	s.catalog.invalidateItems(storeID, itemIDs...)
+	s.catalog.invalidate(catalogKeyAutocomplete + strconv.Itoa(storeID))
+	s.catalog.invalidate(catalogKeyAutocomplete + "0")
+}
+
+func (s *PostgresStore) SetStoreAssortment(req types.SetStoreAssortment) (*types.StoreAssortmentChange, error) {
+	if len(req.ItemIDs) == 0 {
+		return nil, fmt.Errorf("item_ids is required")
+	}
+	if err := s.storeExists(req.StoreID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	change := &types.StoreAssortmentChange{StoreID: req.StoreID}
+	res, err := tx.Exec(`
+        UPDATE item_store SET listed = $3
+        WHERE store_id = $1 AND item_id = ANY($2) AND listed <> $3`,
+		req.StoreID, pq.Array(req.ItemIDs), req.Listed)
+	if err != nil {
+		return nil, fmt.Errorf("error updating assortment: %w", err)
+	}
+	updated, _ := res.RowsAffected()
+	change.Updated = int(updated)
+
+	if req.Listed {
+		res, err = tx.Exec(`
+            INSERT INTO item_store (item_id, store_id, store_price, discount, stock_quantity, listed)
+            SELECT i.id, $1, COALESCE(ifin.mrp_price, 0), 0, 0, TRUE
+            FROM item i
+            LEFT JOIN item_financial ifin ON ifin.item_id = i.id
+            WHERE i.id = ANY($2)
+            ON CONFLICT (item_id, store_id) DO NOTHING`,
+			req.StoreID, pq.Array(req.ItemIDs))
+		if err != nil {
+			return nil, fmt.Errorf("error adding items to store %d: %w", req.StoreID, err)
+		}
+		added, _ := res.RowsAffected()
+		change.Added = int(added)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.invalidateStoreAssortment(req.StoreID, req.ItemIDs...)
+	if change.Added > 0 {
+		// a listing of the store can gain an item it did not contain
+		s.catalog.invalidateAllItems()
+	}
+	return change, nil
+}
+
+func (s *PostgresStore) SetStorePriceOverride(req types.StorePriceOverride) (*types.StoreAssortmentItem, error) {
+	var override sql.NullFloat64
+	if req.Price != nil {
+		if *req.Price < 0 {
+			return nil, fmt.Errorf("price cannot be negative")
+		}
+		override = sql.NullFloat64{Float64: math.Round(*req.Price), Valid: true}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var mrp sql.NullFloat64
+	err = tx.QueryRow(`
+        SELECT p.mrp_price FROM store_item_price p
+        WHERE p.store_id = $1 AND p.item_id = $2`, req.StoreID, req.ItemID).Scan(&mrp)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item %d is not sold in store %d", req.ItemID, req.StoreID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading item price: %w", err)
+	}
+	if override.Valid && mrp.Valid && override.Float64 > mrp.Float64 {
+		return nil, fmt.Errorf("price %.0f is above the mrp %.2f of item %d", override.Float64, mrp.Float64, req.ItemID)
+	}
+
+	if _, err := tx.Exec(`UPDATE item_store SET price_override = $3 WHERE store_id = $1 AND item_id = $2`,
+		req.StoreID, req.ItemID, override); err != nil {
+		return nil, fmt.Errorf("error setting price override: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.invalidateStoreAssortment(req.StoreID, req.ItemID)
+
+	items, err := s.queryStoreAssortment(`WHERE istore.store_id = $1 AND istore.item_id = $2`, req.StoreID, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("item %d is not sold in store %d", req.ItemID, req.StoreID)
+	}
+	return &items[0], nil
+}
+
+// CloneStoreAssortment adds the items of one store to another and copies whether they are listed.
+// Items the target already carries keep their stock; new ones start with none.
+func (s *PostgresStore) CloneStoreAssortment(req types.CloneStoreAssortment) (*types.StoreAssortmentChange, error) {
+	if req.FromStoreID == req.ToStoreID {
+		return nil, fmt.Errorf("from_store_id and to_store_id must differ")
+	}
+	if err := s.storeExists(req.FromStoreID); err != nil {
+		return nil, err
+	}
+	if err := s.storeExists(req.ToStoreID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setPriceSource(tx, "clone"); err != nil {
+		return nil, err
+	}
+
+	change := &types.StoreAssortmentChange{StoreID: req.ToStoreID}
+	res, err := tx.Exec(`
+        UPDATE item_store dst
+        SET listed = src.listed,
+            discount = CASE WHEN $3 THEN src.discount ELSE dst.discount END,
+            price_override = CASE WHEN $3 THEN src.price_override ELSE dst.price_override END
+        FROM item_store src
+        WHERE src.store_id = $1 AND dst.store_id = $2 AND dst.item_id = src.item_id
+          AND (dst.listed <> src.listed OR ($3 AND (dst.discount <> src.discount
+               OR dst.price_override IS DISTINCT FROM src.price_override)))`,
+		req.FromStoreID, req.ToStoreID, req.IncludePrices)
+	if err != nil {
+		return nil, fmt.Errorf("error updating assortment of store %d: %w", req.ToStoreID, err)
+	}
+	updated, _ := res.RowsAffected()
+	change.Updated = int(updated)
+
+	res, err = tx.Exec(`
+        INSERT INTO item_store (item_id, store_id, store_price, discount, price_override, listed, stock_quantity)
+        SELECT src.item_id, $2, src.store_price,
+               CASE WHEN $3 THEN src.discount ELSE 0 END,
+               CASE WHEN $3 THEN src.price_override END,
+               src.listed, 0
+        FROM item_store src
+        WHERE src.store_id = $1 AND src.item_id IS NOT NULL
+        ON CONFLICT (item_id, store_id) DO NOTHING`,
+		req.FromStoreID, req.ToStoreID, req.IncludePrices)
+	if err != nil {
+		return nil, fmt.Errorf("error copying assortment to store %d: %w", req.ToStoreID, err)
+	}
+	added, _ := res.RowsAffected()
+	change.Added = int(added)
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.catalog.invalidateAllItems()
+	s.invalidateStoreAssortment(req.ToStoreID)
+	return change, nil
+}
+
+func (s *PostgresStore) GetStoreAssortment(req types.StoreAssortmentRequest) ([]types.StoreAssortmentItem, error) {
+	if req.Listed != nil {
+		return s.queryStoreAssortment(`WHERE istore.store_id = $1 AND istore.listed = $2`, req.StoreID, *req.Listed)
+	}
+	return s.queryStoreAssortment(`WHERE istore.store_id = $1`, req.StoreID)
+}
+
+func (s *PostgresStore) queryStoreAssortment(where string, args ...interface{}) ([]types.StoreAssortmentItem, error) {
+	rows, err := s.reader(readCatalog).Query(`
+        SELECT i.id, i.name, istore.listed, COALESCE(p.mrp_price, 0), istore.discount, istore.price_override,
+               COALESCE(p.price, istore.store_price), istore.stock_quantity
+        FROM item_store istore
+        JOIN item i ON i.id = istore.item_id
+        JOIN store_item_price p ON p.item_id = istore.item_id AND p.store_id = istore.store_id
+        `+where+`
+        ORDER BY i.name, i.id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying store assortment: %w", err)
+	}
+	defer rows.Close()
+
+	items := []types.StoreAssortmentItem{}
+	for rows.Next() {
+		var item types.StoreAssortmentItem
+		var override sql.NullFloat64
+		if err := rows.Scan(&item.ItemID, &item.Name, &item.Listed, &item.MRPPrice, &item.Discount, &override,
+			&item.Price, &item.StockQuantity); err != nil {
+			return nil, fmt.Errorf("error scanning store assortment: %w", err)
+		}
+		if override.Valid {
+			item.PriceOverride = &override.Float64
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (s *PostgresStore) storeExists(storeID int) error {
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM store WHERE id = $1)`, storeID).Scan(&exists); err != nil {
+		return fmt.Errorf("error checking store %d: %w", storeID, err)
+	}
+	if !exists {
+		return fmt.Errorf("store %d does not exist", storeID)
+	}
+	return nil
+}
@@ -272,7 +272,7 @@ func getAllTableNames(db *sql.DB) ([]string, error) {
 	}
 */
 func (s *PostgresStore) ExportAllData() (string, error) {
-	tables, err := getAllTableNames(s.db)
+	tables, err := getAllTableNames(s.reader(readExport))
 	if err != nil {
 		return "", err
 	}
@@ -285,7 +285,7 @@ func (s *PostgresStore) ExportAllData() (string, error) {
 	for _, table := range tables {
 		// Fetch all data from each table
 		query := fmt.Sprintf("SELECT * FROM %s;", pq.QuoteIdentifier(table))
-		rows, err := s.db.Query(query)
+		rows, err := s.reader(readExport).Query(query)
 		if err != nil {
 			return "", err
 		}
@@ -217,6 +217,7 @@ func (s *PostgresStore) FetchCompletedTransactionDetailsAndCreateOrder(cartID in
 	if err := tx.Commit(); err != nil {
 		return false, fmt.Errorf("error committing transaction: %w", err)
 	}
+	s.invalidateCartStock(cartID)
 
 	_, err = s.sendOrderNotifToPacker()
 	if err != nil {
@@ -67,6 +67,17 @@ func (s *PostgresStore) CreateVendorBrandTable(tx *sql.Tx) error {
 		return fmt.Errorf("error creating brand table: %w", err)
 	}
 
+	alterVendorBrandQuery := `
+	ALTER TABLE vendor_brand
+		ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+	CREATE UNIQUE INDEX IF NOT EXISTS vendor_brand_primary ON vendor_brand (brand_id) WHERE is_primary;`
+
+	if _, err := tx.Exec(alterVendorBrandQuery); err != nil {
+		return fmt.Errorf("error adding vendor_brand columns: %w", err)
+	}
+
 	return nil
 }
 
@@ -186,6 +197,37 @@ func (s *PostgresStore) GetVendorList() ([]types.Vendor, error) {
 	return vendors, nil
 }
 
+// ListVendors is the paginated form of GetVendorList. BrandID keeps vendors supplying that brand.
+func (s *PostgresStore) ListVendors(params types.ListParams) (*types.Page[types.Vendor], error) {
+	q := &listQuery{
+		columns: `v.id, v.name, v.phone, v.email, COALESCE(v.delivery_frequency, ''), v.delivery_day, v.mode_of_communication, COALESCE(v.notes, ''),
+			COALESCE(array_agg(b.name) FILTER (WHERE b.name IS NOT NULL), '{}')`,
+		from: `vendor v
+			LEFT JOIN vendor_brand vb ON v.id = vb.vendor_id
+			LEFT JOIN brand b ON vb.brand_id = b.id`,
+		groupBy: "v.id",
+		sorts: map[string]string{
+			"id":   "v.id",
+			"name": "LOWER(v.name)",
+		},
+		defaultSort: "id",
+		keys:        []string{"v.id"},
+	}
+
+	if params.BrandID != 0 {
+		q.filter("EXISTS (SELECT 1 FROM vendor_brand fvb WHERE fvb.vendor_id = v.id AND fvb.brand_id = ?)", params.BrandID)
+	}
+
+	return runListQuery(s.db, q, params, func(rows *sql.Rows, cursorDest []interface{}) (types.Vendor, error) {
+		var vendor types.Vendor
+		dest := []interface{}{&vendor.ID, &vendor.Name, &vendor.Phone, &vendor.Email, &vendor.DeliveryFrequency, pq.Array(&vendor.DeliveryDay), pq.Array(&vendor.ModeOfCommunication), &vendor.Notes, pq.Array(&vendor.Brands)}
+		if err := rows.Scan(append(dest, cursorDest...)...); err != nil {
+			return vendor, err
+		}
+		return vendor, nil
+	})
+}
+
 func (s *PostgresStore) AddVendor(vendor *types.AddVendor) (*types.Vendor, error) {
 	// Start a transaction
 	tx, err := s.db.Begin()
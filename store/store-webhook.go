@@ -0,0 +1,339 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/girithc/pronto-go/types"
+	"github.com/lib/pq"
+)
+
+const (
+	OrderEventCreated          = "order.created"
+	OrderEventPacked           = "order.packed"
+	OrderEventDispatched       = "order.dispatched"
+	OrderEventCompleted        = "order.completed"
+	OrderEventPackingCancelled = "order.packing_cancelled"
+	CheckoutEventCancelled     = "checkout.cancelled"
+)
+
+const (
+	webhookBatchSize   = 20
+	webhookMaxAttempts = 10
+	// webhookLease keeps other instances away from a claimed delivery while it is in flight.
+	webhookLease = 2 * time.Minute
+)
+
+func (s *PostgresStore) CreateWebhookTables(tx *sql.Tx) error {
+	endpointQuery := `
+    CREATE TABLE IF NOT EXISTS webhook_endpoint (
+        id SERIAL PRIMARY KEY,
+        url TEXT NOT NULL,
+        secret TEXT NOT NULL,
+        event_types TEXT[],
+        active BOOLEAN NOT NULL DEFAULT true,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`
+	if _, err := tx.Exec(endpointQuery); err != nil {
+		return fmt.Errorf("error creating webhook_endpoint table: %w", err)
+	}
+
+	outboxQuery := `
+    CREATE TABLE IF NOT EXISTS outbox_event (
+        id BIGSERIAL PRIMARY KEY,
+        event_type VARCHAR(64) NOT NULL,
+        aggregate_id INT NOT NULL,
+        payload JSONB NOT NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`
+	if _, err := tx.Exec(outboxQuery); err != nil {
+		return fmt.Errorf("error creating outbox_event table: %w", err)
+	}
+
+	deliveryQuery := `
+    CREATE TABLE IF NOT EXISTS webhook_delivery (
+        id BIGSERIAL PRIMARY KEY,
+        event_id BIGINT REFERENCES outbox_event(id) ON DELETE CASCADE NOT NULL,
+        endpoint_id INT REFERENCES webhook_endpoint(id) ON DELETE CASCADE NOT NULL,
+        status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+        attempts INT NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        last_status_code INT,
+        last_error TEXT,
+        delivered_at TIMESTAMP,
+        UNIQUE (event_id, endpoint_id)
+    );
+    CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';`
+	if _, err := tx.Exec(deliveryQuery); err != nil {
+		return fmt.Errorf("error creating webhook_delivery table: %w", err)
+	}
+
+	return nil
+}
+
+// writeOutboxEvent records an event and queues a delivery for every subscribed endpoint.
+// It must run in the transaction that makes the change so the event commits or rolls back with it.
+func writeOutboxEvent(tx *sql.Tx, eventType string, aggregateID int, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding %s event: %w", eventType, err)
+	}
+
+	var eventID int64
+	err = tx.QueryRow(`INSERT INTO outbox_event (event_type, aggregate_id, payload) VALUES ($1, $2, $3) RETURNING id`,
+		eventType, aggregateID, body).Scan(&eventID)
+	if err != nil {
+		return fmt.Errorf("error writing %s event: %w", eventType, err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO webhook_delivery (event_id, endpoint_id)
+        SELECT $1, id FROM webhook_endpoint
+        WHERE active AND (event_types IS NULL OR cardinality(event_types) = 0 OR $2 = ANY(event_types))`,
+		eventID, eventType)
+	if err != nil {
+		return fmt.Errorf("error queueing deliveries for %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// writeOrderOutboxEvent snapshots the sales_order row as seen by tx into an outbox event.
+func writeOrderOutboxEvent(tx *sql.Tx, eventType string, orderID int) error {
+	event := types.OrderEvent{Event: eventType}
+	var deliveryPartnerID, packerID sql.NullInt64
+	err := tx.QueryRow(`
+        SELECT id, store_id, customer_id, cart_id, delivery_partner_id, packer_id, order_status, order_dp_status
+        FROM sales_order WHERE id = $1`, orderID).Scan(
+		&event.OrderID, &event.StoreID, &event.CustomerID, &event.CartID,
+		&deliveryPartnerID, &packerID, &event.OrderStatus, &event.OrderDPStatus)
+	if err != nil {
+		return fmt.Errorf("error reading sales_order %d for %s event: %w", orderID, eventType, err)
+	}
+	if deliveryPartnerID.Valid {
+		id := int(deliveryPartnerID.Int64)
+		event.DeliveryPartnerID = &id
+	}
+	if packerID.Valid {
+		id := int(packerID.Int64)
+		event.PackerID = &id
+	}
+
+	return writeOutboxEvent(tx, eventType, orderID, event)
+}
+
+func (s *PostgresStore) CreateWebhookEndpoint(req types.CreateWebhookEndpoint) (*types.WebhookEndpoint, error) {
+	if req.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("error generating webhook secret: %w", err)
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+
+	endpoint := &types.WebhookEndpoint{}
+	err := s.db.QueryRow(`
+        INSERT INTO webhook_endpoint (url, secret, event_types)
+        VALUES ($1, $2, $3)
+        RETURNING id, url, secret, COALESCE(event_types, '{}'), active, created_at`,
+		req.URL, req.Secret, pq.Array(req.EventTypes)).Scan(
+		&endpoint.ID, &endpoint.URL, &endpoint.Secret, pq.Array(&endpoint.EventTypes), &endpoint.Active, &endpoint.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook endpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// ReplayWebhooks queues the selected events again, including for endpoints registered after they happened.
+func (s *PostgresStore) ReplayWebhooks(req types.WebhookReplay) (*types.WebhookReplayResult, error) {
+	if len(req.EventIDs) == 0 && req.From == nil && req.To == nil {
+		return nil, fmt.Errorf("event_ids or a from/to window is required")
+	}
+	if len(req.EventIDs) > 0 {
+		// The listed events are replayed wherever they fall, so the window does not apply.
+		req.From, req.To = nil, nil
+	}
+
+	query := `
+        INSERT INTO webhook_delivery (event_id, endpoint_id)
+        SELECT e.id, w.id
+        FROM outbox_event e
+        JOIN webhook_endpoint w ON w.active
+            AND (w.event_types IS NULL OR cardinality(w.event_types) = 0 OR e.event_type = ANY(w.event_types))
+        WHERE ($1 = 0 OR w.id = $1)
+          AND (COALESCE(cardinality($2::bigint[]), 0) = 0 OR e.id = ANY($2::bigint[]))
+          AND ($3::timestamp IS NULL OR e.created_at >= $3)
+          AND ($4::timestamp IS NULL OR e.created_at < $4)
+        ON CONFLICT (event_id, endpoint_id) DO UPDATE
+        SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, last_error = NULL`
+
+	result, err := s.db.Exec(query, req.EndpointID, pq.Array(req.EventIDs), req.From, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("error replaying webhooks: %w", err)
+	}
+
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error replaying webhooks: %w", err)
+	}
+
+	return &types.WebhookReplayResult{Queued: int(queued)}, nil
+}
+
+type webhookDelivery struct {
+	id        int64
+	eventID   int64
+	eventType string
+	attempts  int
+	url       string
+	secret    string
+	payload   []byte
+}
+
+// RunWebhookRelay delivers pending outbox events until ctx is done. Several instances can run it at once.
+func (s *PostgresStore) RunWebhookRelay(ctx context.Context) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := s.claimWebhookDeliveries()
+		if err != nil {
+			log.Printf("webhook relay: %v", err)
+		}
+		for _, d := range deliveries {
+			statusCode, err := sendWebhook(ctx, client, d)
+			s.finishWebhookDelivery(d, statusCode, err)
+		}
+
+		// Keep draining while there is a backlog.
+		if len(deliveries) == webhookBatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PostgresStore) claimWebhookDeliveries() ([]webhookDelivery, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        UPDATE webhook_delivery d
+        SET attempts = d.attempts + 1, next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'
+        FROM outbox_event e, webhook_endpoint w
+        WHERE d.id IN (
+            SELECT id FROM webhook_delivery
+            WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+          AND e.id = d.event_id AND w.id = d.endpoint_id
+        RETURNING d.id, d.event_id, e.event_type, d.attempts, w.url, w.secret, e.payload`,
+		webhookBatchSize, int(webhookLease.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("error claiming webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []webhookDelivery
+	for rows.Next() {
+		var d webhookDelivery
+		if err := rows.Scan(&d.id, &d.eventID, &d.eventType, &d.attempts, &d.url, &d.secret, &d.payload); err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing webhook claim: %w", err)
+	}
+	return deliveries, nil
+}
+
+// sendWebhook posts the payload signed as X-Pronto-Signature: t=<unix>,v1=<hex HMAC-SHA256 of "<unix>.<body>">.
+func sendWebhook(ctx context.Context, client *http.Client, d webhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(d.secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(d.payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(d.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Pronto-Event", d.eventType)
+	req.Header.Set("X-Pronto-Event-Id", strconv.FormatInt(d.eventID, 10))
+	req.Header.Set("X-Pronto-Delivery", strconv.FormatInt(d.id, 10))
+	req.Header.Set("X-Pronto-Signature", "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *PostgresStore) finishWebhookDelivery(d webhookDelivery, statusCode int, sendErr error) {
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = s.db.Exec(`
+            UPDATE webhook_delivery
+            SET status = 'delivered', delivered_at = CURRENT_TIMESTAMP, last_status_code = $2, last_error = NULL
+            WHERE id = $1`, d.id, statusCode)
+	case d.attempts >= webhookMaxAttempts:
+		_, err = s.db.Exec(`
+            UPDATE webhook_delivery
+            SET status = 'failed', last_status_code = NULLIF($2, 0), last_error = $3
+            WHERE id = $1`, d.id, statusCode, sendErr.Error())
+	default:
+		// Exponential backoff: 30s, 1m, 2m, ... capped at 6h.
+		backoff := 30 * time.Second << (d.attempts - 1)
+		if backoff > 6*time.Hour {
+			backoff = 6 * time.Hour
+		}
+		_, err = s.db.Exec(`
+            UPDATE webhook_delivery
+            SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second', last_status_code = NULLIF($3, 0), last_error = $4
+            WHERE id = $1`, d.id, int(backoff.Seconds()), statusCode, sendErr.Error())
+	}
+	if err != nil {
+		log.Printf("webhook relay: error recording delivery %d: %v", d.id, err)
+	}
+}
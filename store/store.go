@@ -17,14 +17,19 @@ import (
 )
 
 type PostgresStore struct {
-	db                *sql.DB
-	db2               *sql.DB
+	db                *sql.DB // primary: all writes, checkout, locks and payments
+	db2               *sql.DB // replica for catalog, report and export reads; equals db without DB_REPLICA_URL
 	cancelFuncs       map[int]context.CancelFunc
 	lockExtended      map[int]bool
 	paymentStatus     map[int]bool
 	firebaseMessaging *messaging.Client
 	firebaseStorage   *storage.Client
 	context           context.Context
+	catalog           *catalogCache
+	orderEvents       *orderEventHub
+	connStr           string
+	router            *readRouter
+	images            imageStorage
 }
 
 func NewPostgresStore() (*PostgresStore, func() error) {
@@ -37,16 +42,24 @@ func NewPostgresStore() (*PostgresStore, func() error) {
 		// db, err = sql.Open("postgres", connStr)
 
 		connStr := "user=postgres dbname=prontodb password=g190201 sslmode=disable"
-		db, err := sql.Open("postgres", connStr)
+		db, err := openPool(connStr, loadPoolConfig("DB_"))
 		if err != nil {
 			log.Fatalf("(local) Error on sql.Open: %v", err)
 		}
-		return &PostgresStore{
+		router := newReadRouter()
+		s := &PostgresStore{
 			db:            db,
+			db2:           openReplica(db),
 			cancelFuncs:   make(map[int]context.CancelFunc), // Already initialized cancelFuncs map
 			lockExtended:  make(map[int]bool),               // Initialize the paymentStatus map
 			paymentStatus: make(map[int]bool),
-		}, nil
+			catalog:       newCatalogCache(router),
+			orderEvents:   newOrderEventHub(),
+			connStr:       connStr,
+			router:        router,
+			images:        newLocalImageStorage(),
+		}
+		return s, s.Close
 	} else {
 		
 		const firebaseConfig = `{
@@ -103,28 +116,35 @@ func NewPostgresStore() (*PostgresStore, func() error) {
 		connStr := fmt.Sprintf(
 		)
 
-		db2, err := sql.Open("postgres", connStr)
+		db, err := openPool(connStr, loadPoolConfig("DB_"))
 		if err != nil {
 			log.Fatalf("Error on sql.Open: %v", err)
 		}
 
-		return &PostgresStore{
-			db:                db2,
-			db2:               db2,
+		router := newReadRouter()
+		s := &PostgresStore{
+			db:                db,
+			db2:               openReplica(db),
 			cancelFuncs:       make(map[int]context.CancelFunc), // Already initialized cancelFuncs map
 			lockExtended:      make(map[int]bool),               // Initialize the paymentStatus map
 			paymentStatus:     make(map[int]bool),
 			firebaseMessaging: client,
 			firebaseStorage:   clientStorage,
 			context:           ctx,
-		}, nil
+			catalog:           newCatalogCache(router),
+			orderEvents:       newOrderEventHub(),
+			connStr:           connStr,
+			router:            router,
+			images:            newFirebaseImageStorage(clientStorage),
+		}
+		return s, s.Close
 
 	}
 }
 
 func (s *PostgresStore) Init() error {
 	// Start a new transaction
-	tx, err := s.db2.Begin()
+	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
@@ -173,6 +193,26 @@ func (s *PostgresStore) Init() error {
 	}
 	fmt.Println("Success - Created Item Image Table")
 
+	if err := s.CreateItemBarcodeTable(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Item Barcode Table")
+
+	if err := s.CreateItemAttributeTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Item Attribute Tables")
+
+	if err := s.CreateProductGroupTable(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Product Group Table")
+
+	if err := s.CreateSearchTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Search Tables")
+
 	if err := s.CreateItemStoreTable(tx); err != nil {
 		return err
 	}
@@ -188,6 +228,16 @@ func (s *PostgresStore) Init() error {
 	}
 	fmt.Println("Success - Created Cart Item Table")
 
+	if err := s.CreateSearchAnalyticsTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Search Analytics Tables")
+
+	if err := s.CreateBundleTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Bundle Tables")
+
 	if err := s.CreateAddressTable(tx); err != nil {
 		return err
 	}
@@ -208,6 +258,11 @@ func (s *PostgresStore) Init() error {
 	}
 	fmt.Println("Success - Created Category Higher Level Mapping Table")
 
+	if err := s.CreateCategoryTree(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Migrated Category Tree")
+
 	if err := s.CreateDeliveryPartnerTable(tx); err != nil {
 		return err
 	}
@@ -238,16 +293,41 @@ func (s *PostgresStore) Init() error {
 	}
 	fmt.Println("Success - Created Sales Order Table")
 
+	if err := s.CreateRecommendationTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Recommendation Tables")
+
 	if err := s.CreateOrderTimelineTable(tx); err != nil {
 		return err
 	}
 	fmt.Println("Success - Created Order Timeline Table")
 
+	if err := s.CreateOrderEventTrigger(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Order Event Trigger")
+
+	if err := s.CreateWebhookTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Webhook Tables")
+
 	if err := s.CreatePackerItemTable(tx); err != nil {
 		return err
 	}
 	fmt.Println("Success - Created Packer Item Table")
 
+	if err := s.CreatePackerStoreColumn(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Packer Store Column")
+
+	if err := s.CreateLooseItemTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Loose Item Tables")
+
 	if err := s.CreateTransactionTable(tx); err != nil {
 		return err
 	}
@@ -321,6 +401,33 @@ func (s *PostgresStore) Init() error {
 		return err
 	}
 	fmt.Println("Success - Created Item Financial Table")
+
+	if err := s.CreateItemPriceTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Item Price Tables")
+
+	if err := s.CreateStorePricing(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Store Pricing")
+
+	if err := s.CreateItemLifecycle(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Item Lifecycle")
+	if err := s.CreatePurchaseLimitTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Purchase Limit Tables")
+	if err := s.CreateCatalogTranslationTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Catalog Translation Tables")
+	if err := s.CreateHomeTables(tx); err != nil {
+		return err
+	}
+	fmt.Println("Success - Created Home Tables")
 	if err := s.CreateManagerTable(tx); err != nil {
 		return err
 	}
@@ -1,10 +1,16 @@
 package types
 
+// BrandLogoMaxBytes caps the size of an uploaded brand logo.
+const BrandLogoMaxBytes = 5 << 20
+
 type Brand struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	Created_At string `json:"created_at"`
-	Created_By int    `json:"created_by"`
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	Logo_URL           string `json:"logo_url"`
+	Logo_Thumbnail_URL string `json:"logo_thumbnail_url"`
+	Created_At         string `json:"created_at"`
+	Created_By         int    `json:"created_by"`
 }
 
 type BrandList struct {
@@ -13,7 +19,9 @@ type BrandList struct {
 }
 
 type Create_Brand struct {
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Logo_URL    string `json:"logo_url"`
 }
 
 func New_Brand(name string) (*Brand, error) {
@@ -22,3 +30,67 @@ func New_Brand(name string) (*Brand, error) {
 		Created_By: 1,
 	}, nil
 }
+
+// UpdateBrand edits a brand; empty fields keep their value. Logo_URL replaces the logo with a
+// linked image.
+type UpdateBrand struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Logo_URL    string `json:"logo_url"`
+}
+
+type BrandRequest struct {
+	ID int `json:"id"`
+}
+
+// BrandDetail is a brand with the vendors supplying it and the number of items it has.
+type BrandDetail struct {
+	Brand
+	ItemCount int           `json:"item_count"`
+	Vendors   []BrandVendor `json:"vendors"`
+}
+
+type BrandVendor struct {
+	VendorID int    `json:"vendor_id"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	Primary  bool   `json:"primary"`
+}
+
+// BrandLanding is the brand page of a store: the brand and its items in stock there.
+type BrandLanding struct {
+	Brand   Brand                                  `json:"brand"`
+	StoreID int                                    `json:"store_id"`
+	Items   []*Get_Items_By_CategoryID_And_StoreID `json:"items"`
+}
+
+// BrandVendorLink links a brand to a vendor. A primary vendor is the one purchase planning orders
+// the brand from; a brand has at most one.
+type BrandVendorLink struct {
+	BrandID  int  `json:"brand_id"`
+	VendorID int  `json:"vendor_id"`
+	Primary  bool `json:"primary"`
+}
+
+type SupplierItemsRequest struct {
+	StoreID  int `json:"store_id"`
+	VendorID int `json:"vendor_id"`
+}
+
+// SupplierItems groups the items of a store by the vendor they are bought from. Items of brands
+// without a vendor are listed under vendor 0.
+type SupplierItems struct {
+	VendorID int            `json:"vendor_id"`
+	Vendor   string         `json:"vendor"`
+	Items    []SupplierItem `json:"items"`
+}
+
+type SupplierItem struct {
+	ItemID          int    `json:"item_id"`
+	Name            string `json:"name"`
+	BrandID         int    `json:"brand_id"`
+	Brand           string `json:"brand"`
+	Stock_Quantity  int    `json:"stock_quantity"`
+	Locked_Quantity int    `json:"locked_quantity"`
+}
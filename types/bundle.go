@@ -0,0 +1,28 @@
+package types
+
+// BundleComponent is one item inside a bundle and how many of it one bundle contains.
+type BundleComponent struct {
+	ItemID           int    `json:"item_id"`
+	Name             string `json:"name"`
+	Quantity         int    `json:"quantity"`
+	Size             int    `json:"size"`
+	Unit_Of_Quantity string `json:"unit_of_quantity"`
+	Image            string `json:"image"`
+}
+
+// Bundle is a combo item priced on its own whose stock comes from its components.
+type Bundle struct {
+	BundleItemID int               `json:"bundle_item_id"`
+	Name         string            `json:"name"`
+	Components   []BundleComponent `json:"components"`
+}
+
+// SetBundle replaces the components of BundleItemID; only ItemID and Quantity of each component are read.
+type SetBundle struct {
+	BundleItemID int               `json:"bundle_item_id"`
+	Components   []BundleComponent `json:"components"`
+}
+
+type RemoveBundle struct {
+	BundleItemID int `json:"bundle_item_id"`
+}
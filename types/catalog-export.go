@@ -0,0 +1,32 @@
+package types
+
+const (
+	CatalogExportCSV   = "csv"
+	CatalogExportJSONL = "jsonl"
+)
+
+type CatalogExportRequest struct {
+	StoreID int    `json:"store_id"`
+	Format  string `json:"format"`
+}
+
+// CatalogExportRow is one item of a store's denormalized catalog. Tax rates are percentages.
+type CatalogExportRow struct {
+	ItemID         int      `json:"item_id"`
+	Name           string   `json:"name"`
+	Brand          string   `json:"brand"`
+	Categories     []string `json:"categories"`
+	Barcodes       []string `json:"barcodes"`
+	Quantity       int      `json:"quantity"`
+	UnitOfQuantity string   `json:"unit_of_quantity"`
+	MRPPrice       *float64 `json:"mrp_price"`
+	StorePrice     float64  `json:"store_price"`
+	Discount       float64  `json:"discount"`
+	BuyPrice       *float64 `json:"buy_price"`
+	MarginNet      *float64 `json:"margin_net"`
+	GST            *float64 `json:"gst"`
+	Cess           *float64 `json:"cess"`
+	StockQuantity  int      `json:"stock_quantity"`
+	LockedQuantity int      `json:"locked_quantity"`
+	Shelves        []string `json:"shelves"`
+}
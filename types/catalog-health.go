@@ -0,0 +1,50 @@
+package types
+
+// Catalog health issues, in the order the report lists them.
+const (
+	CatalogIssueMissingFinancials = "missing_financials"
+	CatalogIssueMissingMRP        = "missing_mrp"
+	CatalogIssueMissingBuyPrice   = "missing_buy_price"
+	CatalogIssueMissingTax        = "missing_tax"
+	CatalogIssueMissingImage      = "missing_image"
+	CatalogIssueMissingBarcode    = "missing_barcode"
+	CatalogIssueMissingShelf      = "missing_shelf"
+	CatalogIssueNonPositiveMargin = "non_positive_margin"
+	CatalogIssueMRPBelowBuyPrice  = "mrp_below_buy_price"
+)
+
+var CatalogIssues = []string{
+	CatalogIssueMissingFinancials, CatalogIssueMissingMRP, CatalogIssueMissingBuyPrice, CatalogIssueMissingTax,
+	CatalogIssueMissingImage, CatalogIssueMissingBarcode, CatalogIssueMissingShelf,
+	CatalogIssueNonPositiveMargin, CatalogIssueMRPBelowBuyPrice,
+}
+
+// CatalogHealthRequest limits the report to the items of StoreID when it is set. Limit caps the
+// items listed under each issue; the counts always cover every item. Discontinued items are left
+// out unless IncludeDiscontinued is set.
+type CatalogHealthRequest struct {
+	StoreID             int  `json:"store_id"`
+	Limit               int  `json:"limit"`
+	IncludeDiscontinued bool `json:"include_discontinued"`
+}
+
+type CatalogHealthItem struct {
+	ItemID int    `json:"item_id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type CatalogHealthIssue struct {
+	Issue string              `json:"issue"`
+	Count int                 `json:"count"`
+	Items []CatalogHealthItem `json:"items"`
+}
+
+type CatalogHealthReport struct {
+	StoreID         int                  `json:"store_id,omitempty"`
+	GeneratedAt     string               `json:"generated_at"`
+	ItemsChecked    int                  `json:"items_checked"`
+	ItemsWithIssues int                  `json:"items_with_issues"`
+	Issues          []CatalogHealthIssue `json:"issues"`
+}
@@ -0,0 +1,27 @@
+package types
+
+// CatalogImportRequest carries the CSV inline so the manager auth fields can travel in the same JSON body.
+//
+// Columns: name, brand, categories, quantity, unit, barcode, mrp, buy_price, tax, margin, images, stock, description.
+// categories and images are separated by "|"; stock is "<store name or id>:<qty>" pairs separated by ";".
+// tax is the total GST percentage, with anything above 28 booked as cess. barcode, when set, must be a
+// valid EAN-8, UPC-A or EAN-13 code. margin is a percentage; when empty it is worked out from buy_price.
+type CatalogImportRequest struct {
+	CSV    string `json:"csv"`
+	DryRun bool   `json:"dry_run"`
+}
+
+type CatalogImportError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type CatalogImportReport struct {
+	DryRun    bool                 `json:"dry_run"`
+	Committed bool                 `json:"committed"`
+	Rows      int                  `json:"rows"`
+	ValidRows int                  `json:"valid_rows"`
+	Errors    []CatalogImportError `json:"errors"`
+	ItemIDs   []int                `json:"item_ids,omitempty"`
+}
@@ -0,0 +1,79 @@
+package types
+
+// Catalog text is entered in English, the base language; translations are kept per field and
+// language and fall back to the next language the customer accepts, then to English.
+const (
+	LanguageEnglish = "en"
+	LanguageHindi   = "hi"
+	LanguageMarathi = "mr"
+)
+
+// CatalogLanguages are the languages catalog text can be translated into.
+var CatalogLanguages = []string{LanguageHindi, LanguageMarathi}
+
+// Higher level categories are the roots of the category tree, so category translations cover them.
+const (
+	TranslationEntityItem     = "item"
+	TranslationEntityCategory = "category"
+)
+
+// TranslatableFields lists the fields of each entity that take translations, in report order.
+var TranslatableFields = map[string][]string{
+	TranslationEntityItem:     {"name", "description"},
+	TranslationEntityCategory: {"name"},
+}
+
+// TranslationEntities orders the entities of TranslatableFields for reports.
+var TranslationEntities = []string{TranslationEntityItem, TranslationEntityCategory}
+
+// SetCatalogTranslation sets the translations of an entity's fields in one language. An empty
+// value removes the translation of that field.
+type SetCatalogTranslation struct {
+	Entity    string            `json:"entity"`
+	EntityID  int               `json:"entity_id"`
+	Language  string            `json:"language"`
+	Fields    map[string]string `json:"fields"`
+	UpdatedBy int               `json:"updated_by"`
+}
+
+type CatalogTranslationRequest struct {
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entity_id"`
+}
+
+// CatalogTranslation holds the base text of an entity, its translations by language and field,
+// and the fields still missing in each language.
+type CatalogTranslation struct {
+	Entity       string                       `json:"entity"`
+	EntityID     int                          `json:"entity_id"`
+	Base         map[string]string            `json:"base"`
+	Translations map[string]map[string]string `json:"translations"`
+	Missing      map[string][]string          `json:"missing"`
+}
+
+type MissingTranslationsRequest struct {
+	Entity   string `json:"entity"`
+	Language string `json:"language"`
+	Limit    int    `json:"limit"`
+}
+
+type MissingTranslation struct {
+	EntityID int    `json:"entity_id"`
+	Base     string `json:"base"`
+}
+
+// TranslationCoverage counts the translated values of one field in one language. Items that are
+// discontinued and fields with no base text are not counted.
+type TranslationCoverage struct {
+	Entity     string               `json:"entity"`
+	Field      string               `json:"field"`
+	Language   string               `json:"language"`
+	Total      int                  `json:"total"`
+	Translated int                  `json:"translated"`
+	Missing    []MissingTranslation `json:"missing"`
+}
+
+type MissingTranslationsReport struct {
+	GeneratedAt string                `json:"generated_at"`
+	Coverage    []TranslationCoverage `json:"coverage"`
+}
@@ -0,0 +1,59 @@
+package types
+
+import "time"
+
+// CategoryNode is one category of the category tree. Children are ordered by SortOrder.
+type CategoryNode struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Image       string          `json:"image"`
+	Promotion   bool            `json:"promotion"`
+	ParentID    *int            `json:"parent_id"`
+	SortOrder   int             `json:"sort_order"`
+	ActiveFrom  *time.Time      `json:"active_from"`
+	ActiveUntil *time.Time      `json:"active_until"`
+	Children    []*CategoryNode `json:"children,omitempty"`
+}
+
+type CategoryBreadcrumb struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryMove moves a category and its whole subtree under ParentID (nil for the root),
+// at Position among its new siblings. Position 0 appends it after the last sibling.
+type CategoryMove struct {
+	CategoryID int  `json:"category_id"`
+	ParentID   *int `json:"parent_id"`
+	Position   int  `json:"position"`
+}
+
+// CategoryReorder sets the sibling order of ParentID's children to CategoryIDs.
+type CategoryReorder struct {
+	ParentID    *int  `json:"parent_id"`
+	CategoryIDs []int `json:"category_ids"`
+}
+
+// CategoryStoreVisibility enables or disables a category, and with it its subtree, in one store.
+type CategoryStoreVisibility struct {
+	CategoryID int  `json:"category_id"`
+	StoreID    int  `json:"store_id"`
+	Enabled    bool `json:"enabled"`
+}
+
+// CategorySchedule limits when a category is shown. A nil bound leaves that side open.
+type CategorySchedule struct {
+	CategoryID  int        `json:"category_id"`
+	ActiveFrom  *time.Time `json:"active_from"`
+	ActiveUntil *time.Time `json:"active_until"`
+}
+
+// CategoryMappingDropped is a higher level mapping the category tree migration did not keep, since
+// the category was already placed under ParentID.
+type CategoryMappingDropped struct {
+	CategoryID              int    `json:"category_id"`
+	CategoryName            string `json:"category_name"`
+	HigherLevelCategoryID   int    `json:"higher_level_category_id"`
+	HigherLevelCategoryName string `json:"higher_level_category_name"`
+	ParentID                *int   `json:"parent_id"`
+}
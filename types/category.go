@@ -14,6 +14,7 @@ type Create_Category struct {
 	Name      string `json:"name"`
 	Image     string `json:"image"`
 	Promotion bool   `json:"promotion"`
+	ParentID  *int   `json:"parent_id"`
 }
 
 type Update_Category struct {
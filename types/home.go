@@ -0,0 +1,122 @@
+package types
+
+import "time"
+
+// Kinds of home-screen section. A categories section shows the promotion categories.
+const (
+	HomeSectionBanners    = "banners"
+	HomeSectionCollection = "collection"
+	HomeSectionCategories = "categories"
+)
+
+// Rules of a rule-based collection. A collection with no rule lists the items set on it, in order.
+// The category and brand rules take the category or brand id as RuleParam.
+const (
+	HomeRuleSchemeDiscount = "scheme_discount"
+	HomeRuleStoreDiscount  = "store_discount"
+	HomeRuleNewArrivals    = "new_arrivals"
+	HomeRuleCategory       = "category"
+	HomeRuleBrand          = "brand"
+)
+
+// Deep link types of a banner. Category, item, brand and collection links target an id, a search
+// link the search text and a url link an https address.
+const (
+	HomeLinkNone       = "none"
+	HomeLinkCategory   = "category"
+	HomeLinkItem       = "item"
+	HomeLinkBrand      = "brand"
+	HomeLinkCollection = "collection"
+	HomeLinkSearch     = "search"
+	HomeLinkURL        = "url"
+)
+
+type HomeLink struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+}
+
+// HomeScreen is the home layout of a store, its live sections in display order.
+type HomeScreen struct {
+	StoreID  int           `json:"store_id"`
+	Sections []HomeSection `json:"sections"`
+}
+
+// HomeSection holds the content of one section; only the field of its kind is set. SeeAll links a
+// collection to its full listing.
+type HomeSection struct {
+	ID         int                                    `json:"id"`
+	Kind       string                                 `json:"kind"`
+	Title      string                                 `json:"title"`
+	Banners    []HomeBanner                           `json:"banners,omitempty"`
+	Items      []*Get_Items_By_CategoryID_And_StoreID `json:"items,omitempty"`
+	Categories []*Category                            `json:"categories,omitempty"`
+	SeeAll     *HomeLink                              `json:"see_all,omitempty"`
+}
+
+type HomeBanner struct {
+	ID       int      `json:"id"`
+	Title    string   `json:"title"`
+	ImageURL string   `json:"image_url"`
+	Link     HomeLink `json:"link"`
+}
+
+// SetHomeSection creates a section, or updates it when ID is set. A zero StoreID shows the section
+// in every store. Position orders the section unless the store has its own order, and a nil
+// schedule bound leaves that side open.
+type SetHomeSection struct {
+	ID          int        `json:"id"`
+	StoreID     int        `json:"store_id"`
+	Kind        string     `json:"kind"`
+	Title       string     `json:"title"`
+	Position    int        `json:"position"`
+	Enabled     bool       `json:"enabled"`
+	ActiveFrom  *time.Time `json:"active_from"`
+	ActiveUntil *time.Time `json:"active_until"`
+	Rule        string     `json:"rule"`
+	RuleParam   int        `json:"rule_param"`
+	MaxItems    int        `json:"max_items"`
+}
+
+// HomeSectionConfig is a section as managers edit it, with all its banners and the items of a
+// manual collection.
+type HomeSectionConfig struct {
+	SetHomeSection
+	StorePosition *int            `json:"store_position"`
+	Banners       []SetHomeBanner `json:"banners"`
+	ItemIDs       []int           `json:"item_ids"`
+}
+
+type HomeSectionRequest struct {
+	ID      int `json:"id"`
+	StoreID int `json:"store_id"`
+}
+
+// SetHomeBanner creates a banner in a banners section, or updates it when ID is set.
+type SetHomeBanner struct {
+	ID          int        `json:"id"`
+	SectionID   int        `json:"section_id"`
+	Title       string     `json:"title"`
+	ImageURL    string     `json:"image_url"`
+	Link        HomeLink   `json:"link"`
+	Position    int        `json:"position"`
+	ActiveFrom  *time.Time `json:"active_from"`
+	ActiveUntil *time.Time `json:"active_until"`
+}
+
+type HomeBannerRequest struct {
+	ID int `json:"id"`
+}
+
+// SetHomeCollectionItems replaces the items of a manual collection, in display order.
+type SetHomeCollectionItems struct {
+	SectionID int   `json:"section_id"`
+	ItemIDs   []int `json:"item_ids"`
+}
+
+// SetHomeOrder orders the sections of a store. Sections left out fall back to their own position
+// after the ordered ones; an empty list clears the store's order.
+type SetHomeOrder struct {
+	StoreID    int   `json:"store_id"`
+	SectionIDs []int `json:"section_ids"`
+}
@@ -0,0 +1,86 @@
+package types
+
+// Veg/non-veg marks as printed on Indian food packaging.
+const (
+	VegMarkVeg    = "veg"
+	VegMarkNonVeg = "non_veg"
+	VegMarkEgg    = "egg"
+)
+
+// Attribute names used by category schemas, one per field group of ItemAttributes.
+const (
+	AttributeVegMark         = "veg_mark"
+	AttributeIngredients     = "ingredients"
+	AttributeAllergens       = "allergens"
+	AttributeNutrition       = "nutrition"
+	AttributeShelfLifeDays   = "shelf_life_days"
+	AttributeCountryOfOrigin = "country_of_origin"
+	AttributeManufacturer    = "manufacturer"
+)
+
+var ItemAttributeNames = []string{
+	AttributeVegMark,
+	AttributeIngredients,
+	AttributeAllergens,
+	AttributeNutrition,
+	AttributeShelfLifeDays,
+	AttributeCountryOfOrigin,
+	AttributeManufacturer,
+}
+
+// NutritionFacts are declared per 100 g or 100 ml, as on the label. Unset values are omitted.
+type NutritionFacts struct {
+	Per           string   `json:"per"`
+	EnergyKcal    *float64 `json:"energy_kcal,omitempty"`
+	ProteinG      *float64 `json:"protein_g,omitempty"`
+	CarbohydrateG *float64 `json:"carbohydrate_g,omitempty"`
+	SugarG        *float64 `json:"sugar_g,omitempty"`
+	FatG          *float64 `json:"fat_g,omitempty"`
+	SaturatedFatG *float64 `json:"saturated_fat_g,omitempty"`
+	TransFatG     *float64 `json:"trans_fat_g,omitempty"`
+	FibreG        *float64 `json:"fibre_g,omitempty"`
+	SodiumMg      *float64 `json:"sodium_mg,omitempty"`
+	CholesterolMg *float64 `json:"cholesterol_mg,omitempty"`
+}
+
+type Manufacturer struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+// ItemAttributes is the structured label information of an item. CountryOfOrigin is an ISO 3166 alpha-2 code.
+type ItemAttributes struct {
+	ItemID          int             `json:"item_id"`
+	VegMark         string          `json:"veg_mark,omitempty"`
+	Ingredients     string          `json:"ingredients,omitempty"`
+	Allergens       []string        `json:"allergens,omitempty"`
+	Nutrition       *NutritionFacts `json:"nutrition,omitempty"`
+	ShelfLifeDays   *int            `json:"shelf_life_days,omitempty"`
+	CountryOfOrigin string          `json:"country_of_origin,omitempty"`
+	Manufacturer    *Manufacturer   `json:"manufacturer,omitempty"`
+	UpdatedAt       string          `json:"updated_at,omitempty"`
+}
+
+// CategoryAttribute says that an attribute applies to items of a category, and whether it must be filled in.
+// A category inherits the schema of its ancestors.
+type CategoryAttribute struct {
+	Attribute string `json:"attribute"`
+	Required  bool   `json:"required"`
+}
+
+type CategoryAttributeSchema struct {
+	CategoryID int                 `json:"category_id"`
+	Attributes []CategoryAttribute `json:"attributes"`
+}
+
+type GetItemAttributes struct {
+	ItemID int `json:"item_id"`
+}
+
+// ItemAttributeDetails is the manager view of an item: its attributes and the schema they are checked against.
+type ItemAttributeDetails struct {
+	Attributes *ItemAttributes     `json:"attributes"`
+	Schema     []CategoryAttribute `json:"schema"`
+	Missing    []string            `json:"missing"`
+}
@@ -0,0 +1,30 @@
+package types
+
+// Barcode formats. Codes migrated from item.barcode are kept as BarcodeLegacy without validation.
+const (
+	BarcodeEAN8   = "ean8"
+	BarcodeUPCA   = "upca"
+	BarcodeEAN13  = "ean13"
+	BarcodeLegacy = "legacy"
+)
+
+// ItemBarcode is one of the codes an item can be scanned by. The primary code is mirrored in item.barcode.
+type ItemBarcode struct {
+	ID        int    `json:"id"`
+	ItemID    int    `json:"item_id"`
+	Barcode   string `json:"barcode"`
+	Format    string `json:"format"`
+	Primary   bool   `json:"primary"`
+	CreatedAt string `json:"created_at"`
+}
+
+type AddItemBarcode struct {
+	ItemID  int    `json:"item_id"`
+	Barcode string `json:"barcode"`
+	Primary bool   `json:"primary"`
+}
+
+type RemoveItemBarcode struct {
+	ItemID  int    `json:"item_id"`
+	Barcode string `json:"barcode"`
+}
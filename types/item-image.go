@@ -0,0 +1,28 @@
+package types
+
+// ItemImageMaxBytes caps the size of one uploaded item image.
+const ItemImageMaxBytes = 10 << 20
+
+// ItemImage is one image of an item. Uploaded images carry resized variants; Srcset lists them
+// by width and is the bare URL for images added by link.
+type ItemImage struct {
+	ID            int    `json:"id"`
+	ItemID        int    `json:"item_id"`
+	ImageURL      string `json:"image_url"`
+	OrderPosition int    `json:"order_position"`
+	Width         *int   `json:"width"`
+	MediumURL     string `json:"medium_url,omitempty"`
+	ThumbnailURL  string `json:"thumbnail_url,omitempty"`
+	Srcset        string `json:"srcset"`
+}
+
+// ItemImageReorder sets the order of all images of an item to ImageIDs.
+type ItemImageReorder struct {
+	ItemID   int   `json:"item_id"`
+	ImageIDs []int `json:"image_ids"`
+}
+
+type ItemImageDelete struct {
+	ItemID  int `json:"item_id"`
+	ImageID int `json:"image_id"`
+}
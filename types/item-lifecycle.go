@@ -0,0 +1,40 @@
+package types
+
+// An item starts as a draft when it is entered without its price, tax, image, barcode or shelf, and
+// only active items are shown to customers.
+const (
+	ItemDraft        = "draft"
+	ItemActive       = "active"
+	ItemPaused       = "paused"
+	ItemDiscontinued = "discontinued"
+)
+
+type SetItemStatus struct {
+	ItemID    int    `json:"item_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	ChangedBy int    `json:"changed_by"`
+}
+
+type ItemLifecycleRequest struct {
+	ItemID int `json:"item_id"`
+}
+
+type ItemStatusChange struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason,omitempty"`
+	ChangedBy  int    `json:"changed_by,omitempty"`
+	ChangedAt  string `json:"changed_at"`
+}
+
+// ItemLifecycle reports the status of an item and what it still lacks before it can be activated.
+// RemovedFromCarts counts the carts an item was taken out of when it stopped being active.
+type ItemLifecycle struct {
+	ItemID           int                `json:"item_id"`
+	Status           string             `json:"status"`
+	Ready            bool               `json:"ready"`
+	Missing          []string           `json:"missing"`
+	RemovedFromCarts int                `json:"removed_from_carts,omitempty"`
+	History          []ItemStatusChange `json:"history"`
+}
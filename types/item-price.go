@@ -0,0 +1,72 @@
+package types
+
+const (
+	PriceChangeScheduled = "scheduled"
+	PriceChangeApplied   = "applied"
+	PriceChangeCancelled = "cancelled"
+	PriceChangeFailed    = "failed"
+)
+
+// SchedulePriceChange sets the prices of an item from EffectiveAt (RFC3339) on. Unset prices keep
+// their value; BuyPrice is tax exclusive, as in ItemFinance. StorePrice is the selling price and is
+// applied to StoreID, or to every store of the item when StoreID is 0. A past EffectiveAt applies
+// the change right away.
+type SchedulePriceChange struct {
+	ItemID      int      `json:"item_id"`
+	StoreID     int      `json:"store_id"`
+	MRPPrice    *float64 `json:"mrp_price"`
+	BuyPrice    *float64 `json:"buy_price"`
+	StorePrice  *float64 `json:"store_price"`
+	EffectiveAt string   `json:"effective_at"`
+	Reason      string   `json:"reason"`
+	CreatedBy   int      `json:"created_by"`
+}
+
+type ItemPriceChange struct {
+	ID          int      `json:"id"`
+	ItemID      int      `json:"item_id"`
+	StoreID     int      `json:"store_id,omitempty"`
+	MRPPrice    *float64 `json:"mrp_price,omitempty"`
+	BuyPrice    *float64 `json:"buy_price,omitempty"`
+	StorePrice  *float64 `json:"store_price,omitempty"`
+	EffectiveAt string   `json:"effective_at"`
+	Status      string   `json:"status"`
+	Error       string   `json:"error,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	AppliedAt   *string  `json:"applied_at,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	CreatedBy   int      `json:"created_by,omitempty"`
+}
+
+type CancelPriceChange struct {
+	ID int `json:"id"`
+}
+
+// ItemPriceHistoryRequest limits the store rows of the history to StoreID when it is set.
+type ItemPriceHistoryRequest struct {
+	ItemID  int `json:"item_id"`
+	StoreID int `json:"store_id"`
+}
+
+// ItemPriceHistoryEntry is one recorded price. Rows without a store carry the item's MRP and buy
+// price (tax inclusive); store rows carry the store's selling price and discount. Source is
+// "initial", "edit", "import", "reset" or "schedule", with ChangeID set for scheduled changes.
+type ItemPriceHistoryEntry struct {
+	ID         int64    `json:"id"`
+	StoreID    int      `json:"store_id,omitempty"`
+	MRPPrice   *float64 `json:"mrp_price,omitempty"`
+	BuyPrice   *float64 `json:"buy_price,omitempty"`
+	StorePrice *int     `json:"store_price,omitempty"`
+	Discount   *int     `json:"discount,omitempty"`
+	Source     string   `json:"source"`
+	ChangeID   int      `json:"change_id,omitempty"`
+	ChangedAt  string   `json:"changed_at"`
+}
+
+// ItemPriceHistory lists the item's scheduled changes in every status and its recorded prices,
+// newest first.
+type ItemPriceHistory struct {
+	ItemID  int                     `json:"item_id"`
+	Changes []ItemPriceChange       `json:"changes"`
+	History []ItemPriceHistoryEntry `json:"history"`
+}
@@ -32,6 +32,7 @@ type Get_Item struct {
 	Stock_Quantity   int      `json:"stock_quantity"`
 	Locked_Quantity  int      `json:"locked_quantity"`
 	Images           []string `json:"images"`
+	Srcset           []string `json:"srcset"`
 	Brand            string   `json:"brand"`
 	Quantity         int      `json:"quantity"`
 	Unit_Of_Quantity string   `json:"unit_of_quantity"`
@@ -40,23 +41,27 @@ type Get_Item struct {
 }
 
 type Get_Item_Barcode struct {
-	ID               int      `json:"id"`
-	Name             string   `json:"name"`
-	MRP_Price        float64  `json:"mrp_price"`
-	Discount         float64  `json:"discount"`
-	Store_Price      float64  `json:"store_price"`
-	Description      string   `json:"description"`
-	Stores           []string `json:"stores"`
-	Categories       []string `json:"categories"`
-	Stock_Quantity   int      `json:"stock_quantity"`
-	Locked_Quantity  int      `json:"locked_quantity"`
-	Images           []string `json:"images"`
-	Brand            string   `json:"brand"`
-	Quantity         int      `json:"quantity"`
-	Unit_Of_Quantity string   `json:"unit_of_quantity"`
-	Created_At       string   `json:"created_at"`
-	Created_By       int      `json:"created_by"`
-	Barcode          string   `json:"barcode"`
+	ID               int               `json:"id"`
+	Name             string            `json:"name"`
+	MRP_Price        float64           `json:"mrp_price"`
+	Discount         float64           `json:"discount"`
+	Store_Price      float64           `json:"store_price"`
+	Description      string            `json:"description"`
+	Stores           []string          `json:"stores"`
+	Categories       []string          `json:"categories"`
+	Stock_Quantity   int               `json:"stock_quantity"`
+	Locked_Quantity  int               `json:"locked_quantity"`
+	Images           []string          `json:"images"`
+	Srcset           []string          `json:"srcset"`
+	Brand            string            `json:"brand"`
+	Quantity         int               `json:"quantity"`
+	Unit_Of_Quantity string            `json:"unit_of_quantity"`
+	Created_At       string            `json:"created_at"`
+	Created_By       int               `json:"created_by"`
+	Barcode          string            `json:"barcode"`
+	Barcodes         []string          `json:"barcodes"`
+	Attributes       *ItemAttributes   `json:"attributes,omitempty"`
+	Components       []BundleComponent `json:"components,omitempty"`
 }
 
 type Create_Item struct {
@@ -105,38 +110,45 @@ type Delete_Item struct {
 
 // Custom
 type Get_Items_By_CategoryID_And_StoreID struct {
-	ID               int      `json:"id"`
-	Name             string   `json:"name"`
-	MRP_Price        float64  `json:"mrp_price"`
-	Discount         float64  `json:"discount"`
-	Store_Price      float64  `json:"store_price"`
-	Store            string   `json:"store"`
-	Category         string   `json:"category"`
-	Stock_Quantity   int      `json:"stock_quantity"`
-	Locked_Quantity  int      `json:"locked_quantity"`
-	Images           []string `json:"image"`
-	Brand            string   `json:"brand"`
-	Quantity         int      `json:"quantity"`
-	Unit_Of_Quantity string   `json:"unit_of_quantity"`
-	Created_At       string   `json:"created_at"`
-	Created_By       int      `json:"created_by"`
+	ID               int             `json:"id"`
+	Name             string          `json:"name"`
+	MRP_Price        float64         `json:"mrp_price"`
+	Discount         float64         `json:"discount"`
+	Store_Price      float64         `json:"store_price"`
+	Store            string          `json:"store"`
+	Category         string          `json:"category"`
+	Stock_Quantity   int             `json:"stock_quantity"`
+	Locked_Quantity  int             `json:"locked_quantity"`
+	Images           []string        `json:"image"`
+	Srcset           []string        `json:"srcset"`
+	Brand            string          `json:"brand"`
+	Quantity         int             `json:"quantity"`
+	Unit_Of_Quantity string          `json:"unit_of_quantity"`
+	Created_At       string          `json:"created_at"`
+	Created_By       int             `json:"created_by"`
+	Attributes       *ItemAttributes `json:"attributes,omitempty"`
+	Product_Group_ID int             `json:"product_group_id,omitempty"`
+	Variants         []ItemVariant   `json:"variants,omitempty"`
 }
 
 type Get_Items_By_CategoryID_And_StoreID_noCategory struct {
-	ID               int     `json:"id"`
-	Name             string  `json:"name"`
-	MRP_Price        float64 `json:"mrp_price"`
-	Discount         float64 `json:"discount"`
-	Store_Price      float64 `json:"store_price"`
-	Store            string  `json:"store"`
-	Stock_Quantity   int     `json:"stock_quantity"`
-	Locked_Quantity  int     `json:"locked_quantity"`
-	Image            string  `json:"image"`
-	Brand            string  `json:"brand"`
-	Quantity         int     `json:"quantity"`
-	Unit_Of_Quantity string  `json:"unit_of_quantity"`
-	Created_At       string  `json:"created_at"`
-	Created_By       int     `json:"created_by"`
+	ID               int           `json:"id"`
+	Name             string        `json:"name"`
+	MRP_Price        float64       `json:"mrp_price"`
+	Discount         float64       `json:"discount"`
+	Store_Price      float64       `json:"store_price"`
+	Store            string        `json:"store"`
+	Stock_Quantity   int           `json:"stock_quantity"`
+	Locked_Quantity  int           `json:"locked_quantity"`
+	Image            string        `json:"image"`
+	Brand            string        `json:"brand"`
+	Quantity         int           `json:"quantity"`
+	Unit_Of_Quantity string        `json:"unit_of_quantity"`
+	Created_At       string        `json:"created_at"`
+	Created_By       int           `json:"created_by"`
+	Veg_Mark         string        `json:"veg_mark,omitempty"`
+	Product_Group_ID int           `json:"product_group_id,omitempty"`
+	Variants         []ItemVariant `json:"variants,omitempty"`
 }
 
 func New_Item(name string, mrp_price float64, discount float64, store_price float64, category []string, store string, brand string, stock_quantity int, image string, description string, quantity int, unit_of_quantity string) (*Item, error) {
@@ -0,0 +1,55 @@
+package types
+
+const (
+	WeightSettlementNone    = "none"
+	WeightSettlementRefund  = "refund"
+	WeightSettlementCollect = "collect"
+)
+
+// SetLooseItem sells an item by weight. The item's quantity and unit (g or kg) are the estimated
+// weight of one ordered unit, and PricePerKg, when set, becomes its MRP scaled to that weight.
+// TolerancePct bounds how far a packed weight may be from its estimate.
+type SetLooseItem struct {
+	ItemID       int      `json:"item_id"`
+	SoldByWeight bool     `json:"sold_by_weight"`
+	PricePerKg   *float64 `json:"price_per_kg"`
+	TolerancePct *float64 `json:"tolerance_pct"`
+	CreatedBy    int      `json:"created_by"`
+}
+
+type LooseItem struct {
+	ItemID         int     `json:"item_id"`
+	SoldByWeight   bool    `json:"sold_by_weight"`
+	EstimatedGrams int     `json:"estimated_grams"`
+	MRPPrice       float64 `json:"mrp_price"`
+	PricePerKg     float64 `json:"price_per_kg"`
+	TolerancePct   float64 `json:"tolerance_pct"`
+}
+
+type OrderWeightRequest struct {
+	SalesOrderID int `json:"sales_order_id"`
+}
+
+// OrderWeightLine is a loose item of an order: UnitPrice is the price of one ordered unit of
+// EstimatedGrams / Quantity, and ActualAmount is charged for the packed ActualGrams.
+type OrderWeightLine struct {
+	ItemID          int     `json:"item_id"`
+	Name            string  `json:"name"`
+	Quantity        int     `json:"quantity"`
+	EstimatedGrams  int     `json:"estimated_grams"`
+	ActualGrams     int     `json:"actual_grams"`
+	UnitPrice       float64 `json:"unit_price"`
+	EstimatedAmount float64 `json:"estimated_amount"`
+	ActualAmount    float64 `json:"actual_amount"`
+}
+
+// OrderWeightSettlement is the difference between what an order's loose items were estimated and
+// packed at. Prepaid orders settle a negative Delta with a refund and a positive one with a
+// collection; cash orders are simply collected at the actual amount.
+type OrderWeightSettlement struct {
+	SalesOrderID int               `json:"sales_order_id"`
+	Lines        []OrderWeightLine `json:"lines"`
+	Delta        float64           `json:"delta"`
+	Settlement   string            `json:"settlement"`
+	Status       string            `json:"status"`
+}
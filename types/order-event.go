@@ -0,0 +1,15 @@
+package types
+
+// OrderEvent is published whenever a sales_order row is created or its order_status,
+// order_dp_status or delivery partner changes.
+type OrderEvent struct {
+	Event             string `json:"event"`
+	OrderID           int    `json:"order_id"`
+	StoreID           int    `json:"store_id"`
+	CustomerID        int    `json:"customer_id"`
+	CartID            int    `json:"cart_id"`
+	DeliveryPartnerID *int   `json:"delivery_partner_id"`
+	PackerID          *int   `json:"packer_id"`
+	OrderStatus       string `json:"order_status"`
+	OrderDPStatus     string `json:"order_dp_status"`
+}
@@ -24,6 +24,12 @@ type PackerFCM struct {
 	FCM   string `json:"fcm"`
 }
 
+// SetPackerStore assigns a packer to the store whose orders they pack.
+type SetPackerStore struct {
+	PackerID int `json:"packer_id"`
+	StoreID  int `json:"store_id"`
+}
+
 type PackerPhone struct {
 	Phone string `json:"phone"`
 }
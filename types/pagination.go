@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ListParams carries the paging, filtering and sorting options shared by the list endpoints.
+// Filters that do not apply to a given list are ignored.
+type ListParams struct {
+	Limit      int        `json:"limit"`
+	Cursor     string     `json:"cursor"`
+	Sort       string     `json:"sort"`
+	Desc       bool       `json:"desc"`
+	StoreID    int        `json:"store_id"`
+	Status     string     `json:"status"`
+	From       *time.Time `json:"from"`
+	To         *time.Time `json:"to"`
+	BrandID    int        `json:"brand_id"`
+	CategoryID int        `json:"category_id"`
+}
+
+// Page is one slice of a keyset-paginated list. Total is only filled in on the first page.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int   `json:"total,omitempty"`
+}
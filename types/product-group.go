@@ -0,0 +1,40 @@
+package types
+
+// ProductGroup links the sizes of one product, such as 200 g, 500 g and 1 kg packs, which are
+// separate items with their own stock and price. Listings show the group as one card.
+type ProductGroup struct {
+	ID            int           `json:"id"`
+	Name          string        `json:"name"`
+	DefaultItemID int           `json:"default_item_id"`
+	Variants      []ItemVariant `json:"variants"`
+	CreatedAt     string        `json:"created_at"`
+}
+
+// ItemVariant is one size in a product card or group. Price and stock are for the listed store
+// when the variant comes from a store listing.
+type ItemVariant struct {
+	ItemID           int     `json:"item_id"`
+	Name             string  `json:"name"`
+	Quantity         int     `json:"quantity"`
+	Unit_Of_Quantity string  `json:"unit_of_quantity"`
+	MRP_Price        float64 `json:"mrp_price"`
+	Discount         float64 `json:"discount"`
+	Store_Price      float64 `json:"store_price"`
+	Stock_Quantity   int     `json:"stock_quantity"`
+	Locked_Quantity  int     `json:"locked_quantity"`
+	Image            string  `json:"image"`
+	Default          bool    `json:"default"`
+}
+
+type CreateProductGroup struct {
+	Name          string `json:"name"`
+	ItemIDs       []int  `json:"item_ids"`
+	DefaultItemID int    `json:"default_item_id"`
+}
+
+// ProductGroupVariant names a variant for the add, remove and set-default endpoints.
+type ProductGroupVariant struct {
+	ProductGroupID int  `json:"product_group_id"`
+	ItemID         int  `json:"item_id"`
+	Default        bool `json:"default"`
+}
@@ -0,0 +1,93 @@
+package types
+
+import "fmt"
+
+// Codes returned with a cart update or stock lock that breaks a purchase limit or needs a
+// restriction confirmed first.
+const (
+	ErrItemOrderLimit         = "item_order_limit"
+	ErrCategoryOrderLimit     = "category_order_limit"
+	ErrItemDailyLimit         = "item_daily_limit"
+	ErrCategoryDailyLimit     = "category_daily_limit"
+	ErrRestrictionUnconfirmed = "restriction_unconfirmed"
+)
+
+// Restrictions an item can carry. The customer confirms each one on the cart before checkout.
+const (
+	RestrictionAgeConfirmation = "age_confirmation"
+)
+
+var ItemRestrictions = []string{RestrictionAgeConfirmation}
+
+// PurchaseLimitError is returned when a cart breaks a purchase limit or holds a restricted item the
+// customer has not confirmed. Code is one of the Err constants above.
+type PurchaseLimitError struct {
+	Code        string `json:"code"`
+	ItemID      int    `json:"item_id,omitempty"`
+	CategoryID  int    `json:"category_id,omitempty"`
+	Restriction string `json:"restriction,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+	Quantity    int    `json:"quantity,omitempty"`
+}
+
+func (e *PurchaseLimitError) Error() string {
+	switch e.Code {
+	case ErrItemOrderLimit:
+		return fmt.Sprintf("item %d is limited to %d per order, the cart has %d", e.ItemID, e.Limit, e.Quantity)
+	case ErrCategoryOrderLimit:
+		return fmt.Sprintf("category %d is limited to %d items per order, the cart has %d", e.CategoryID, e.Limit, e.Quantity)
+	case ErrItemDailyLimit:
+		return fmt.Sprintf("item %d is limited to %d per customer per day, %d with this cart", e.ItemID, e.Limit, e.Quantity)
+	case ErrCategoryDailyLimit:
+		return fmt.Sprintf("category %d is limited to %d items per customer per day, %d with this cart", e.CategoryID, e.Limit, e.Quantity)
+	case ErrRestrictionUnconfirmed:
+		return fmt.Sprintf("item %d needs %s to be confirmed", e.ItemID, e.Restriction)
+	}
+	return e.Code
+}
+
+func (e *PurchaseLimitError) ErrorCode() string {
+	return e.Code
+}
+
+// SetPurchaseLimit sets the limits of an item or a category, exactly one of ItemID and CategoryID.
+// A zero limit is no limit; a category limit counts the items of the category and its subcategories.
+type SetPurchaseLimit struct {
+	ItemID            int `json:"item_id"`
+	CategoryID        int `json:"category_id"`
+	MaxPerOrder       int `json:"max_per_order"`
+	MaxPerCustomerDay int `json:"max_per_customer_day"`
+}
+
+type PurchaseLimit struct {
+	ID                int    `json:"id"`
+	ItemID            int    `json:"item_id,omitempty"`
+	CategoryID        int    `json:"category_id,omitempty"`
+	Name              string `json:"name"`
+	MaxPerOrder       int    `json:"max_per_order"`
+	MaxPerCustomerDay int    `json:"max_per_customer_day"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+type PurchaseLimitRequest struct {
+	ItemID     int `json:"item_id"`
+	CategoryID int `json:"category_id"`
+}
+
+type SetItemRestrictions struct {
+	ItemID       int      `json:"item_id"`
+	Restrictions []string `json:"restrictions"`
+}
+
+type ConfirmCartRestriction struct {
+	CartId      int    `json:"cart_id"`
+	CustomerId  int    `json:"customer_id"`
+	Restriction string `json:"restriction"`
+}
+
+type CartRestrictions struct {
+	CartId      int      `json:"cart_id"`
+	Required    []string `json:"required"`
+	Confirmed   []string `json:"confirmed"`
+	Unconfirmed []string `json:"unconfirmed"`
+}
@@ -0,0 +1,39 @@
+package types
+
+// RecommendationRequest asks for recommendations for the authenticated customer. CartID is only read
+// by /recommendations/cart. Languages is the customer's fallback chain from Accept-Language.
+type RecommendationRequest struct {
+	PhoneAuth string   `json:"phone_auth"`
+	CartID    int      `json:"cart_id"`
+	StoreID   int      `json:"store_id"`
+	Limit     int      `json:"limit"`
+	Languages []string `json:"-"`
+}
+
+// RecommendedItem is an in-stock item of the requested store. Score orders the list: the summed
+// confidence of the cart's items for frequently-bought-together, the number of past orders for buy-again.
+type RecommendedItem struct {
+	ItemID           int     `json:"item_id"`
+	Name             string  `json:"name"`
+	Brand            string  `json:"brand"`
+	Image            string  `json:"image"`
+	Quantity         int     `json:"quantity"`
+	Unit_Of_Quantity string  `json:"unit_of_quantity"`
+	MRP_Price        float64 `json:"mrp_price"`
+	Discount         float64 `json:"discount"`
+	Store_Price      float64 `json:"store_price"`
+	Stock_Quantity   int     `json:"stock_quantity"`
+	Score            float64 `json:"score"`
+	Lift             float64 `json:"lift,omitempty"`
+	LastOrderedAt    string  `json:"last_ordered_at,omitempty"`
+}
+
+// RecommendationRun records one refresh of the recommendation tables.
+type RecommendationRun struct {
+	ID         int    `json:"id"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+	Orders     int    `json:"orders"`
+	Pairs      int    `json:"pairs"`
+	Customers  int    `json:"customers"`
+}
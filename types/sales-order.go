@@ -127,6 +127,7 @@ type AcceptOrderItem struct {
 	SalesOrderID int    `json:"sales_order_id"`
 	Barcode      string `json:"barcode"`
 	StoreId      int    `json:"store_id"`
+	WeightGrams  int    `json:"weight_grams"`
 }
 
 type OrderQuick struct {
@@ -135,6 +136,7 @@ type OrderQuick struct {
 	ItemId      int 	`json:"item_id"`
 	ItemQuantity int 	`json:"item_quantity"`
 	StoreId      int    `json:"store_id"`
+	WeightGrams  int    `json:"weight_grams"`
 }
 
 type SpaceOrder struct {
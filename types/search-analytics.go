@@ -0,0 +1,59 @@
+package types
+
+const (
+	SearchSuggestionItem     = "item"
+	SearchSuggestionBrand    = "brand"
+	SearchSuggestionCategory = "category"
+	SearchSuggestionQuery    = "query"
+)
+
+// SearchSuggestion is one autocomplete entry. ID is the item, brand or category id; popular
+// queries have none.
+type SearchSuggestion struct {
+	Text string `json:"text"`
+	Kind string `json:"kind"`
+	ID   int    `json:"id,omitempty"`
+}
+
+const (
+	SearchEventClick     = "click"
+	SearchEventAddToCart = "add_to_cart"
+)
+
+// SearchEvent records what the customer did with a search result, for conversion reporting.
+type SearchEvent struct {
+	PhoneAuth string `json:"phone_auth"`
+	Search_ID int64  `json:"search_id"`
+	Item_ID   int    `json:"item_id"`
+	Event     string `json:"event"`
+}
+
+// SearchReportRequest covers the last Days days (30 when unset) of one store, or all stores when
+// Store_ID is 0. Limit caps each list of the report.
+type SearchReportRequest struct {
+	Store_ID int `json:"store_id"`
+	Days     int `json:"days"`
+	Limit    int `json:"limit"`
+}
+
+// SearchQueryStat aggregates the searches of one normalized query. Conversion is the share of
+// searches followed by an add-to-cart from the results.
+type SearchQueryStat struct {
+	Query        string  `json:"query"`
+	Searches     int     `json:"searches"`
+	Avg_Results  float64 `json:"avg_results"`
+	Zero_Results int     `json:"zero_results"`
+	Clicks       int     `json:"clicks"`
+	Add_To_Cart  int     `json:"add_to_cart"`
+	Conversion   float64 `json:"conversion"`
+}
+
+// SearchReport points at catalog gaps: what customers search for most, what finds nothing, and
+// what finds items that are rarely added to the cart.
+type SearchReport struct {
+	Store_ID               int               `json:"store_id"`
+	Days                   int               `json:"days"`
+	Top_Queries            []SearchQueryStat `json:"top_queries"`
+	Zero_Result_Queries    []SearchQueryStat `json:"zero_result_queries"`
+	Low_Conversion_Queries []SearchQueryStat `json:"low_conversion_queries"`
+}
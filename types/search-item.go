@@ -1,7 +1,63 @@
 package types
 
+// Search_Item filters on item attributes when the optional fields are set. Items without
+// recorded allergens are excluded once Exclude_Allergens is given, since they cannot be vouched for.
+// Store_ID scopes prices, stock and popularity to one store; without it the best-stocked store is used.
+// Category_ID includes its subcategories, and a Min_Price or Max_Price of 0 is no bound.
+// Languages is the customer's fallback chain from Accept-Language, used for the names returned.
 type Search_Item struct {
-	Name string `json:"name"`
+	PhoneAuth         string   `json:"phone_auth"`
+	Name              string   `json:"name"`
+	Veg_Mark          string   `json:"veg_mark"`
+	Exclude_Allergens []string `json:"exclude_allergens"`
+	Country_Of_Origin string   `json:"country_of_origin"`
+	Store_ID          int      `json:"store_id"`
+	Brand_IDs         []int    `json:"brand_ids"`
+	Category_ID       int      `json:"category_id"`
+	Min_Price         float64  `json:"min_price"`
+	Max_Price         float64  `json:"max_price"`
+	In_Stock          bool     `json:"in_stock"`
+	Limit             int      `json:"limit"`
+	Languages         []string `json:"-"`
+}
+
+// SearchFacetValue counts the matching items for one value of a facet.
+type SearchFacetValue struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets are counted with every filter applied except the facet's own, so the app can offer
+// the other brands, categories or price ranges of the current query.
+type SearchFacets struct {
+	Brands     []SearchFacetValue `json:"brands"`
+	Categories []SearchFacetValue `json:"categories"`
+	Prices     []SearchFacetValue `json:"prices"`
+	In_Stock   int                `json:"in_stock"`
+}
+
+// SearchResult is the answer of /search. Queries lists the query after transliteration together
+// with its synonym expansions; Total counts the matching items before the limit and before sizes
+// of one product are collapsed into a card. Search_ID identifies the logged search for /search-event.
+type SearchResult struct {
+	Search_ID int64                                             `json:"search_id,omitempty"`
+	Query     string                                            `json:"query"`
+	Queries   []string                                          `json:"queries"`
+	Total     int                                               `json:"total"`
+	Items     []*Get_Items_By_CategoryID_And_StoreID_noCategory `json:"items"`
+	Facets    SearchFacets                                      `json:"facets"`
+}
+
+// SearchSynonym is a group of equivalent search terms, e.g. "atta" and "wheat flour". A query
+// containing one of the terms also searches for the others.
+type SearchSynonym struct {
+	ID    int      `json:"id"`
+	Terms []string `json:"terms"`
+}
+
+type DeleteSearchSynonym struct {
+	ID int `json:"id"`
 }
 
 type Search_Item_Result struct {
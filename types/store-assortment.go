@@ -0,0 +1,48 @@
+package types
+
+// SetStoreAssortment lists or delists ItemIDs at StoreID. Listing an item the store has never
+// carried adds it with no stock.
+type SetStoreAssortment struct {
+	StoreID int   `json:"store_id"`
+	ItemIDs []int `json:"item_ids"`
+	Listed  bool  `json:"listed"`
+}
+
+// StorePriceOverride fixes the selling price of an item at one store, whatever its MRP and
+// discount. A nil Price removes the override.
+type StorePriceOverride struct {
+	StoreID int      `json:"store_id"`
+	ItemID  int      `json:"item_id"`
+	Price   *float64 `json:"price"`
+}
+
+// CloneStoreAssortment copies the items of FromStoreID, and whether they are listed, to
+// ToStoreID. IncludePrices also copies discounts and price overrides. Stock is never copied.
+type CloneStoreAssortment struct {
+	FromStoreID   int  `json:"from_store_id"`
+	ToStoreID     int  `json:"to_store_id"`
+	IncludePrices bool `json:"include_prices"`
+}
+
+// StoreAssortmentRequest limits the assortment to listed or delisted items when Listed is set.
+type StoreAssortmentRequest struct {
+	StoreID int   `json:"store_id"`
+	Listed  *bool `json:"listed"`
+}
+
+type StoreAssortmentItem struct {
+	ItemID        int      `json:"item_id"`
+	Name          string   `json:"name"`
+	Listed        bool     `json:"listed"`
+	MRPPrice      float64  `json:"mrp_price"`
+	Discount      int      `json:"discount"`
+	PriceOverride *float64 `json:"price_override,omitempty"`
+	Price         float64  `json:"price"`
+	StockQuantity int      `json:"stock_quantity"`
+}
+
+type StoreAssortmentChange struct {
+	StoreID int `json:"store_id"`
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,39 @@
+package types
+
+import "time"
+
+type WebhookEndpoint struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateWebhookEndpoint registers a URL for outbox events. An empty secret is generated,
+// and empty event_types subscribes to every event.
+type CreateWebhookEndpoint struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+// WebhookReplay queues events again. EventIDs takes precedence over the From/To window;
+// EndpointID 0 replays to every active endpoint subscribed to the event.
+type WebhookReplay struct {
+	EventIDs   []int64    `json:"event_ids"`
+	From       *time.Time `json:"from"`
+	To         *time.Time `json:"to"`
+	EndpointID int        `json:"endpoint_id"`
+}
+
+type WebhookReplayResult struct {
+	Queued int `json:"queued"`
+}
+
+type CheckoutCancelledEvent struct {
+	Event                 string `json:"event"`
+	CartID                int    `json:"cart_id"`
+	MerchantTransactionID string `json:"merchant_transaction_id"`
+}